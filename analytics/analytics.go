@@ -34,6 +34,7 @@ func New(conf config.Config) (*Analytics, error) {
 	if err != nil {
 		return nil, err
 	}
+	svc.BasePath = conf.BasePath(svc.BasePath)
 
 	ds := &Analytics{
 		service: svc,
@@ -0,0 +1,288 @@
+package bigquery
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	SDK "google.golang.org/api/bigquery/v2"
+	"google.golang.org/api/googleapi"
+)
+
+const (
+	defaultAccessMaxRetries = 5
+	defaultAccessBaseDelay  = 200 * time.Millisecond
+	defaultAccessMaxDelay   = 5 * time.Second
+)
+
+// basic roles of dataset access.
+const (
+	RoleReader = "READER"
+	RoleWriter = "WRITER"
+	RoleOwner  = "OWNER"
+)
+
+// basicRoles maps IAM roles into the equivalent basic roles.
+// The dataset access may be returned with either of them.
+var basicRoles = map[string]string{
+	"roles/bigquery.dataViewer": RoleReader,
+	"roles/bigquery.dataEditor": RoleWriter,
+	"roles/bigquery.dataOwner":  RoleOwner,
+}
+
+// normalizeRole returns the basic role of the equivalent IAM role.
+func normalizeRole(role string) string {
+	if basic, ok := basicRoles[role]; ok {
+		return basic
+	}
+	return role
+}
+
+// entity types of dataset access.
+const (
+	EntityUser         = "userByEmail"
+	EntityGroup        = "groupByEmail"
+	EntityDomain       = "domain"
+	EntitySpecialGroup = "specialGroup"
+	EntityIAMMember    = "iamMember"
+	EntityView         = "view"
+)
+
+// special groups of dataset access.
+const (
+	SpecialGroupProjectOwners         = "projectOwners"
+	SpecialGroupProjectReaders        = "projectReaders"
+	SpecialGroupProjectWriters        = "projectWriters"
+	SpecialGroupAllAuthenticatedUsers = "allAuthenticatedUsers"
+)
+
+// AccessEntry is an entry of dataset access.
+type AccessEntry struct {
+	// Role is the basic role (e.g. READER) or IAM role (e.g. roles/bigquery.dataViewer).
+	// It is empty for authorized view.
+	Role       string
+	EntityType string
+	// Entity is email, domain, special group or IAM member. It is empty for authorized view.
+	Entity string
+	// View is the authorized view for EntityView.
+	View *SDK.TableReference
+}
+
+func newAccessEntry(a *SDK.DatasetAccess) (AccessEntry, bool) {
+	e := AccessEntry{
+		Role: a.Role,
+	}
+	switch {
+	case a.UserByEmail != "":
+		e.EntityType, e.Entity = EntityUser, a.UserByEmail
+	case a.GroupByEmail != "":
+		e.EntityType, e.Entity = EntityGroup, a.GroupByEmail
+	case a.Domain != "":
+		e.EntityType, e.Entity = EntityDomain, a.Domain
+	case a.SpecialGroup != "":
+		e.EntityType, e.Entity = EntitySpecialGroup, a.SpecialGroup
+	case a.IamMember != "":
+		e.EntityType, e.Entity = EntityIAMMember, a.IamMember
+	case a.View != nil:
+		e.EntityType, e.View = EntityView, a.View
+	default:
+		// authorized dataset and routine are not supported.
+		return e, false
+	}
+	return e, true
+}
+
+func (e AccessEntry) toSDK() (*SDK.DatasetAccess, error) {
+	a := &SDK.DatasetAccess{
+		Role: e.Role,
+	}
+	switch e.EntityType {
+	case EntityUser:
+		a.UserByEmail = e.Entity
+	case EntityGroup:
+		a.GroupByEmail = e.Entity
+	case EntityDomain:
+		a.Domain = e.Entity
+	case EntitySpecialGroup:
+		a.SpecialGroup = e.Entity
+	case EntityIAMMember:
+		a.IamMember = e.Entity
+	case EntityView:
+		if e.View == nil {
+			return nil, fmt.Errorf("View is required for the entity type; entityType=[%s]", e.EntityType)
+		}
+		a.View = e.View
+	default:
+		return nil, fmt.Errorf("unknown entity type of dataset access; entityType=[%s]", e.EntityType)
+	}
+	return a, nil
+}
+
+// isSameAccess checks if the entry matches with the dataset access.
+func (e AccessEntry) isSameAccess(a *SDK.DatasetAccess) bool {
+	other, ok := newAccessEntry(a)
+	if !ok || e.EntityType != other.EntityType || normalizeRole(e.Role) != normalizeRole(other.Role) {
+		return false
+	}
+	if e.EntityType == EntityView {
+		return isSameTableReference(e.View, other.View)
+	}
+	return strings.EqualFold(e.Entity, other.Entity)
+}
+
+// ListAccess returns the entries of the dataset access.
+func (ds *DatasetAPI) ListAccess() ([]AccessEntry, error) {
+	return ds.ListAccessWithContext(context.Background())
+}
+
+// ListAccessWithContext returns the entries of the dataset access with the given context.
+func (ds *DatasetAPI) ListAccessWithContext(ctx context.Context) ([]AccessEntry, error) {
+	dataset, err := ds.GetWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]AccessEntry, 0, len(dataset.Access))
+	for _, a := range dataset.Access {
+		if e, ok := newAccessEntry(a); ok {
+			list = append(list, e)
+		}
+	}
+	return list, nil
+}
+
+// GrantAccess adds the entries into the dataset access.
+// Existing entries are ignored.
+func (ds *DatasetAPI) GrantAccess(entries ...AccessEntry) error {
+	return ds.GrantAccessWithContext(context.Background(), entries...)
+}
+
+// GrantAccessWithContext adds the entries into the dataset access with the given context.
+func (ds *DatasetAPI) GrantAccessWithContext(ctx context.Context, entries ...AccessEntry) error {
+	accesses := make([]*SDK.DatasetAccess, len(entries))
+	for i, e := range entries {
+		a, err := e.toSDK()
+		if err != nil {
+			return err
+		}
+		accesses[i] = a
+	}
+
+	return ds.updateAccess(ctx, func(list []*SDK.DatasetAccess) ([]*SDK.DatasetAccess, bool) {
+		changed := false
+		for i, e := range entries {
+			if hasAccess(list, e) {
+				continue
+			}
+			list = append(list, accesses[i])
+			changed = true
+		}
+		return list, changed
+	})
+}
+
+// RevokeAccess removes the entries from the dataset access.
+// Missing entries are ignored.
+func (ds *DatasetAPI) RevokeAccess(entries ...AccessEntry) error {
+	return ds.RevokeAccessWithContext(context.Background(), entries...)
+}
+
+// RevokeAccessWithContext removes the entries from the dataset access with the given context.
+func (ds *DatasetAPI) RevokeAccessWithContext(ctx context.Context, entries ...AccessEntry) error {
+	return ds.updateAccess(ctx, func(list []*SDK.DatasetAccess) ([]*SDK.DatasetAccess, bool) {
+		result := make([]*SDK.DatasetAccess, 0, len(list))
+		for _, a := range list {
+			if !matchAnyAccess(entries, a) {
+				result = append(result, a)
+			}
+		}
+		return result, len(result) != len(list)
+	})
+}
+
+// updateAccess fetches the dataset access, modifies it by fn and patches it with etag.
+// When the dataset is modified by others during the update, it retries from fetching.
+func (ds *DatasetAPI) updateAccess(ctx context.Context, fn func([]*SDK.DatasetAccess) ([]*SDK.DatasetAccess, bool)) error {
+	bo := Backoff{
+		BaseDelay: defaultAccessBaseDelay,
+		MaxDelay:  defaultAccessMaxDelay,
+	}
+
+	for i := 0; ; i++ {
+		dataset, err := ds.GetWithContext(ctx)
+		if err != nil {
+			return err
+		}
+
+		access, changed := fn(dataset.Access)
+		if !changed {
+			return nil
+		}
+
+		patch := &SDK.Dataset{
+			Access:          access,
+			ForceSendFields: []string{"Access"},
+		}
+		_, err = ds.client.PatchDatasetIfMatchWithContext(ctx, ds.datasetID, patch, dataset.Etag)
+		if !isPreconditionFailed(err) || i >= defaultAccessMaxRetries {
+			return err
+		}
+
+		if err := SleepContext(ctx, bo.Next()); err != nil {
+			return err
+		}
+	}
+}
+
+func hasAccess(list []*SDK.DatasetAccess, e AccessEntry) bool {
+	for _, a := range list {
+		if e.isSameAccess(a) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchAnyAccess(entries []AccessEntry, a *SDK.DatasetAccess) bool {
+	for _, e := range entries {
+		if e.isSameAccess(a) {
+			return true
+		}
+	}
+	return false
+}
+
+func isSameTableReference(a, b *SDK.TableReference) bool {
+	if a == nil || b == nil {
+		return false
+	}
+	return a.ProjectId == b.ProjectId && a.DatasetId == b.DatasetId && a.TableId == b.TableId
+}
+
+func isPreconditionFailed(err error) bool {
+	apiErr, ok := err.(*googleapi.Error)
+	return ok && apiErr.Code == http.StatusPreconditionFailed
+}
+
+// GetIAMPolicy gets the IAM policy of the table.
+func (t *TableAPI) GetIAMPolicy() (*SDK.Policy, error) {
+	return t.GetIAMPolicyWithContext(context.Background())
+}
+
+// GetIAMPolicyWithContext gets the IAM policy of the table with the given context.
+func (t *TableAPI) GetIAMPolicyWithContext(ctx context.Context) (*SDK.Policy, error) {
+	return t.dataset.client.GetTableIamPolicyWithContext(ctx, t.dataset.datasetID, t.tableID)
+}
+
+// SetIAMPolicy sets the IAM policy of the table.
+// The policy fetched by GetIAMPolicy has etag, and it fails when the policy is modified by others after fetching.
+func (t *TableAPI) SetIAMPolicy(policy *SDK.Policy) (*SDK.Policy, error) {
+	return t.SetIAMPolicyWithContext(context.Background(), policy)
+}
+
+// SetIAMPolicyWithContext sets the IAM policy of the table with the given context.
+func (t *TableAPI) SetIAMPolicyWithContext(ctx context.Context, policy *SDK.Policy) (*SDK.Policy, error) {
+	return t.dataset.client.SetTableIamPolicyWithContext(ctx, t.dataset.datasetID, t.tableID, policy)
+}
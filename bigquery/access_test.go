@@ -0,0 +1,178 @@
+package bigquery
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+
+	SDK "google.golang.org/api/bigquery/v2"
+)
+
+func TestAccessEntryIsSameAccess(t *testing.T) {
+	view := &SDK.TableReference{ProjectId: "project", DatasetId: "ds", TableId: "view"}
+
+	tests := []struct {
+		name   string
+		entry  AccessEntry
+		access *SDK.DatasetAccess
+		want   bool
+	}{
+		{"same", AccessEntry{Role: RoleReader, EntityType: EntityUser, Entity: "a@example.com"},
+			&SDK.DatasetAccess{Role: "READER", UserByEmail: "a@example.com"}, true},
+		{"email is case insensitive", AccessEntry{Role: RoleReader, EntityType: EntityUser, Entity: "A@example.com"},
+			&SDK.DatasetAccess{Role: "READER", UserByEmail: "a@example.com"}, true},
+		{"IAM role of reader", AccessEntry{Role: "roles/bigquery.dataViewer", EntityType: EntityUser, Entity: "a@example.com"},
+			&SDK.DatasetAccess{Role: "READER", UserByEmail: "a@example.com"}, true},
+		{"IAM role of writer", AccessEntry{Role: RoleWriter, EntityType: EntityGroup, Entity: "g@example.com"},
+			&SDK.DatasetAccess{Role: "roles/bigquery.dataEditor", GroupByEmail: "g@example.com"}, true},
+		{"IAM role of owner", AccessEntry{Role: "roles/bigquery.dataOwner", EntityType: EntitySpecialGroup, Entity: SpecialGroupProjectOwners},
+			&SDK.DatasetAccess{Role: "OWNER", SpecialGroup: "projectOwners"}, true},
+		{"other IAM role", AccessEntry{Role: "roles/bigquery.user", EntityType: EntityIAMMember, Entity: "user:a@example.com"},
+			&SDK.DatasetAccess{Role: "roles/bigquery.user", IamMember: "user:a@example.com"}, true},
+		{"different role", AccessEntry{Role: RoleReader, EntityType: EntityUser, Entity: "a@example.com"},
+			&SDK.DatasetAccess{Role: "roles/bigquery.dataEditor", UserByEmail: "a@example.com"}, false},
+		{"different entity type", AccessEntry{Role: RoleReader, EntityType: EntityGroup, Entity: "a@example.com"},
+			&SDK.DatasetAccess{Role: "READER", UserByEmail: "a@example.com"}, false},
+		{"different entity", AccessEntry{Role: RoleReader, EntityType: EntityUser, Entity: "b@example.com"},
+			&SDK.DatasetAccess{Role: "READER", UserByEmail: "a@example.com"}, false},
+		{"view", AccessEntry{EntityType: EntityView, View: view},
+			&SDK.DatasetAccess{View: &SDK.TableReference{ProjectId: "project", DatasetId: "ds", TableId: "view"}}, true},
+		{"different view", AccessEntry{EntityType: EntityView, View: view},
+			&SDK.DatasetAccess{View: &SDK.TableReference{ProjectId: "project", DatasetId: "ds", TableId: "other"}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.entry.isSameAccess(tt.access); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// accessTestServer is a fake dataset API which checks the etag on patch.
+type accessTestServer struct {
+	mu        sync.Mutex
+	etag      int
+	access    []*SDK.DatasetAccess
+	conflicts int // the number of the patches failed by others' modification.
+	gets      int
+	ifMatches []string
+}
+
+func (s *accessTestServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodGet:
+		s.gets++
+	case http.MethodPatch:
+		ifMatch := r.Header.Get("If-Match")
+		s.ifMatches = append(s.ifMatches, ifMatch)
+		if s.conflicts > 0 {
+			s.conflicts--
+			s.etag++ // modified by others.
+		}
+		if ifMatch != strconv.Itoa(s.etag) {
+			http.Error(w, `{"error":{"code":412,"message":"precondition failed"}}`, http.StatusPreconditionFailed)
+			return
+		}
+
+		var ds SDK.Dataset
+		if err := json.NewDecoder(r.Body).Decode(&ds); err != nil {
+			http.Error(w, `{"error":{"code":400,"message":"invalid body"}}`, http.StatusBadRequest)
+			return
+		}
+		s.access = ds.Access
+		s.etag++
+	default:
+		http.Error(w, `{"error":{"code":405,"message":"method not allowed"}}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	json.NewEncoder(w).Encode(&SDK.Dataset{
+		Etag:   strconv.Itoa(s.etag),
+		Access: s.access,
+	})
+}
+
+func TestDatasetAPIGrantAndRevokeAccess(t *testing.T) {
+	reader := AccessEntry{Role: RoleReader, EntityType: EntityUser, Entity: "a@example.com"}
+	writer := AccessEntry{Role: RoleWriter, EntityType: EntityGroup, Entity: "g@example.com"}
+	initial := []*SDK.DatasetAccess{
+		{Role: "roles/bigquery.dataViewer", UserByEmail: "a@example.com"},
+	}
+
+	tests := []struct {
+		name        string
+		revoke      bool
+		entries     []AccessEntry
+		wantPatches int
+		wantAccess  []*SDK.DatasetAccess
+	}{
+		{"grant", false, []AccessEntry{writer}, 1, []*SDK.DatasetAccess{
+			{Role: "roles/bigquery.dataViewer", UserByEmail: "a@example.com"},
+			{Role: "WRITER", GroupByEmail: "g@example.com"},
+		}},
+		{"grant existing entry", false, []AccessEntry{reader}, 0, initial},
+		{"grant twice in the same call", false, []AccessEntry{writer, writer}, 1, []*SDK.DatasetAccess{
+			{Role: "roles/bigquery.dataViewer", UserByEmail: "a@example.com"},
+			{Role: "WRITER", GroupByEmail: "g@example.com"},
+		}},
+		{"revoke", true, []AccessEntry{reader}, 1, []*SDK.DatasetAccess{}},
+		{"revoke missing entry", true, []AccessEntry{writer}, 0, initial},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := &accessTestServer{etag: 1, access: initial}
+			ds := newTestBigQuery(t, srv).DatasetAPI("ds")
+
+			// the second call must be no-op.
+			for i := 0; i < 2; i++ {
+				var err error
+				if tt.revoke {
+					err = ds.RevokeAccess(tt.entries...)
+				} else {
+					err = ds.GrantAccess(tt.entries...)
+				}
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err.Error())
+				}
+			}
+
+			if len(srv.ifMatches) != tt.wantPatches {
+				t.Errorf("patches: got %d, want %d", len(srv.ifMatches), tt.wantPatches)
+			}
+			if !reflect.DeepEqual(srv.access, tt.wantAccess) {
+				t.Errorf("got %#v, want %#v", srv.access, tt.wantAccess)
+			}
+		})
+	}
+}
+
+func TestDatasetAPIGrantAccessRetry(t *testing.T) {
+	srv := &accessTestServer{etag: 1, conflicts: 1}
+	ds := newTestBigQuery(t, srv).DatasetAPI("ds")
+
+	entry := AccessEntry{Role: RoleReader, EntityType: EntityUser, Entity: "a@example.com"}
+	if err := ds.GrantAccess(entry); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	// it is retried from fetching the dataset with the new etag.
+	if srv.gets != 2 {
+		t.Errorf("gets: got %d, want 2", srv.gets)
+	}
+	if want := []string{"1", "2"}; !reflect.DeepEqual(srv.ifMatches, want) {
+		t.Errorf("If-Match: got %v, want %v", srv.ifMatches, want)
+	}
+	want := []*SDK.DatasetAccess{{Role: "READER", UserByEmail: "a@example.com"}}
+	if !reflect.DeepEqual(srv.access, want) {
+		t.Errorf("got %#v, want %#v", srv.access, want)
+	}
+}
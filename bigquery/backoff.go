@@ -0,0 +1,56 @@
+package bigquery
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+const (
+	defaultBackoffBaseDelay = 1 * time.Second
+	defaultBackoffMaxDelay  = 32 * time.Second
+)
+
+// Backoff calculates exponential backoff delay with jitter.
+// The zero value uses the default delays. (base: 1s, max: 32s)
+type Backoff struct {
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+
+	attempt int
+}
+
+// Next returns the next delay and increments the attempt count.
+func (b *Backoff) Next() time.Duration {
+	base := b.BaseDelay
+	if base <= 0 {
+		base = defaultBackoffBaseDelay
+	}
+	max := b.MaxDelay
+	if max <= 0 {
+		max = defaultBackoffMaxDelay
+	}
+
+	d := base << uint(b.attempt)
+	if d <= 0 || d > max {
+		d = max // overflow or exceeded
+	} else {
+		b.attempt++
+	}
+
+	// add jitter up to the half of the delay.
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// SleepContext waits for the duration or until the context is done.
+func SleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
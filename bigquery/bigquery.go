@@ -2,6 +2,7 @@ package bigquery
 
 import (
 	"errors"
+	"net/http"
 
 	SDK "google.golang.org/api/bigquery/v2"
 
@@ -15,8 +16,7 @@ const (
 )
 
 var (
-	errOperationInsertAll = errors.New("error occured on bigquery.InsertAll")
-	errDataType           = errors.New("error data type")
+	errDataType = errors.New("error data type")
 )
 
 // BigQuery is BigQuery API client..
@@ -24,6 +24,9 @@ type BigQuery struct {
 	service   *SDK.Service
 	logger    log.Logger
 	projectID string
+
+	// queryBudget is the maximum estimated bytes to run the query.
+	queryBudget int64
 }
 
 // New returns initialized BigQuery.
@@ -36,6 +39,17 @@ func New(conf config.Config, projectID string) (*BigQuery, error) {
 		return nil, err
 	}
 
+	b, err := NewWithHTTPClient(cli, projectID)
+	if err != nil {
+		return nil, err
+	}
+	b.service.BasePath = conf.BasePath(b.service.BasePath)
+	return b, nil
+}
+
+// NewWithHTTPClient returns initialized BigQuery using the given http client.
+// This can be used for the fake server. (see bigquerytest package)
+func NewWithHTTPClient(cli *http.Client, projectID string) (*BigQuery, error) {
 	svc, err := SDK.New(cli)
 	if err != nil {
 		return nil, err
@@ -54,6 +68,13 @@ func (b *BigQuery) SetLogger(logger log.Logger) {
 	b.logger = logger
 }
 
+// SetQueryBudget sets the maximum estimated bytes processed by a query.
+// When it's set, Query runs a dry run before the query and refuses the query exceeding the budget.
+// Zero means no limit.
+func (b *BigQuery) SetQueryBudget(maxBytes int64) {
+	b.queryBudget = maxBytes
+}
+
 // Errorf logging error information.
 func (b *BigQuery) Errorf(format string, v ...interface{}) {
 	b.logger.Errorf(serviceName, format, v...)
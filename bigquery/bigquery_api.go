@@ -1,10 +1,14 @@
 package bigquery
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"strings"
 
 	SDK "google.golang.org/api/bigquery/v2"
+
+	"github.com/evalphobia/google-api-go-wrapper/config"
 )
 
 // see API documents: https://cloud.google.com/bigquery/docs/reference/rest/v2
@@ -16,7 +20,12 @@ import (
 // CreateDataset performes Datasets.Insert operation.
 // Creates a new empty dataset.
 func (b *BigQuery) CreateDataset(dataset *SDK.Dataset) (*Dataset, error) {
-	ds, err := b.service.Datasets.Insert(b.projectID, dataset).Do()
+	return b.CreateDatasetWithContext(context.Background(), dataset)
+}
+
+// CreateDatasetWithContext performes Datasets.Insert operation with the given context.
+func (b *BigQuery) CreateDatasetWithContext(ctx context.Context, dataset *SDK.Dataset) (*Dataset, error) {
+	ds, err := b.service.Datasets.Insert(b.projectID, dataset).Context(ctx).Do()
 	b.logAPIError("Datasets.Insert", err, logArgs("datasetID", dataset.Id))
 	return &Dataset{ds}, err
 }
@@ -24,7 +33,24 @@ func (b *BigQuery) CreateDataset(dataset *SDK.Dataset) (*Dataset, error) {
 // PatchDataset performes Datasets.Patch operation.
 // Updates information in an existing dataset. The update method replaces the entire dataset resource, whereas the patch method only replaces fields that are provided in the submitted dataset resource. This method supports patch semantics.
 func (b *BigQuery) PatchDataset(datasetID string, dataset *SDK.Dataset) (*Dataset, error) {
-	ds, err := b.service.Datasets.Patch(b.projectID, datasetID, dataset).Do()
+	return b.PatchDatasetWithContext(context.Background(), datasetID, dataset)
+}
+
+// PatchDatasetWithContext performes Datasets.Patch operation with the given context.
+func (b *BigQuery) PatchDatasetWithContext(ctx context.Context, datasetID string, dataset *SDK.Dataset) (*Dataset, error) {
+	ds, err := b.service.Datasets.Patch(b.projectID, datasetID, dataset).Context(ctx).Do()
+	b.logAPIError("Datasets.Patch", err, logArgs("datasetID", datasetID))
+	return &Dataset{ds}, err
+}
+
+// PatchDatasetIfMatchWithContext performes Datasets.Patch operation only when the dataset has the given etag.
+// It returns 412 Precondition Failed error when the dataset is modified after the etag is fetched.
+func (b *BigQuery) PatchDatasetIfMatchWithContext(ctx context.Context, datasetID string, dataset *SDK.Dataset, etag string) (*Dataset, error) {
+	call := b.service.Datasets.Patch(b.projectID, datasetID, dataset).Context(ctx)
+	if etag != "" {
+		call.Header().Set("If-Match", etag)
+	}
+	ds, err := call.Do()
 	b.logAPIError("Datasets.Patch", err, logArgs("datasetID", datasetID))
 	return &Dataset{ds}, err
 }
@@ -32,7 +58,12 @@ func (b *BigQuery) PatchDataset(datasetID string, dataset *SDK.Dataset) (*Datase
 // UpdateDataset performes Datasets.Update operation.
 // Updates information in an existing dataset. The update method replaces the entire dataset resource, whereas the patch method only replaces fields that are provided in the submitted dataset resource.
 func (b *BigQuery) UpdateDataset(datasetID string, dataset *SDK.Dataset) (*Dataset, error) {
-	ds, err := b.service.Datasets.Update(b.projectID, datasetID, dataset).Do()
+	return b.UpdateDatasetWithContext(context.Background(), datasetID, dataset)
+}
+
+// UpdateDatasetWithContext performes Datasets.Update operation with the given context.
+func (b *BigQuery) UpdateDatasetWithContext(ctx context.Context, datasetID string, dataset *SDK.Dataset) (*Dataset, error) {
+	ds, err := b.service.Datasets.Update(b.projectID, datasetID, dataset).Context(ctx).Do()
 	b.logAPIError("Datasets.Update", err, logArgs("datasetID", datasetID))
 	return &Dataset{ds}, err
 }
@@ -40,7 +71,12 @@ func (b *BigQuery) UpdateDataset(datasetID string, dataset *SDK.Dataset) (*Datas
 // DeleteDataset performes Datasets.Delete operation.
 // Deletes the dataset specified by the datasetId value. Before you can delete a dataset, you must delete all its tables, either manually or by specifying deleteContents. Immediately after deletion, you can create another dataset with the same name.
 func (b *BigQuery) DeleteDataset(datasetID string) error {
-	err := b.service.Datasets.Delete(b.projectID, datasetID).Do()
+	return b.DeleteDatasetWithContext(context.Background(), datasetID)
+}
+
+// DeleteDatasetWithContext performes Datasets.Delete operation with the given context.
+func (b *BigQuery) DeleteDatasetWithContext(ctx context.Context, datasetID string) error {
+	err := b.service.Datasets.Delete(b.projectID, datasetID).Context(ctx).Do()
 	b.logAPIError("Datasets.Delete", err, logArgs("datasetID", datasetID))
 	return err
 }
@@ -48,7 +84,12 @@ func (b *BigQuery) DeleteDataset(datasetID string) error {
 // GetDataset performes Datasets.Get operation.
 // Returns the dataset specified by datasetID.
 func (b *BigQuery) GetDataset(datasetID string) (*Dataset, error) {
-	ds, err := b.service.Datasets.Get(b.projectID, datasetID).Do()
+	return b.GetDatasetWithContext(context.Background(), datasetID)
+}
+
+// GetDatasetWithContext performes Datasets.Get operation with the given context.
+func (b *BigQuery) GetDatasetWithContext(ctx context.Context, datasetID string) (*Dataset, error) {
+	ds, err := b.service.Datasets.Get(b.projectID, datasetID).Context(ctx).Do()
 	b.logAPIError("Datasets.Get", err, logArgs("datasetID", datasetID))
 	return &Dataset{ds}, err
 }
@@ -56,7 +97,12 @@ func (b *BigQuery) GetDataset(datasetID string) (*Dataset, error) {
 // ListDatasets performes Datasets.List operation.
 // Lists all datasets in the specified project to which you have been granted the READER dataset role.
 func (b *BigQuery) ListDatasets() (*SDK.DatasetList, error) {
-	list, err := b.service.Datasets.List(b.projectID).Do()
+	return b.ListDatasetsWithContext(context.Background())
+}
+
+// ListDatasetsWithContext performes Datasets.List operation with the given context.
+func (b *BigQuery) ListDatasetsWithContext(ctx context.Context) (*SDK.DatasetList, error) {
+	list, err := b.service.Datasets.List(b.projectID).Context(ctx).Do()
 	b.logAPIError("Datasets.List", err)
 	return list, err
 }
@@ -67,8 +113,23 @@ func (b *BigQuery) ListDatasets() (*SDK.DatasetList, error) {
 
 // RunJob performes Jobs.Insert operation.
 // Starts a new asynchronous job. Requires the Can View project role.
+// Use StartJob to get the job handle to wait for the job.
 func (b *BigQuery) RunJob(job *SDK.Job) (*SDK.Job, error) {
-	j, err := b.service.Jobs.Insert(b.projectID, job).Do()
+	return b.RunJobWithContext(context.Background(), job)
+}
+
+// RunJobWithContext performes Jobs.Insert operation with the given context.
+func (b *BigQuery) RunJobWithContext(ctx context.Context, job *SDK.Job) (*SDK.Job, error) {
+	ctx = idempotentContext(ctx, isIdempotentJob(job))
+	j, err := b.service.Jobs.Insert(b.projectID, job).Context(ctx).Do()
+	b.logAPIError("Jobs.Insert", err)
+	return j, err
+}
+
+// RunMediaJobWithContext performes Jobs.Insert operation with media upload.
+// This is used for load job from local data.
+func (b *BigQuery) RunMediaJobWithContext(ctx context.Context, job *SDK.Job, media io.Reader) (*SDK.Job, error) {
+	j, err := b.service.Jobs.Insert(b.projectID, job).Media(media).Context(ctx).Do()
 	b.logAPIError("Jobs.Insert", err)
 	return j, err
 }
@@ -76,7 +137,13 @@ func (b *BigQuery) RunJob(job *SDK.Job) (*SDK.Job, error) {
 // RunQuery performes Jobs.Query operation.
 // Runs a BigQuery SQL query and returns results if the query completes within a specified timeout.
 func (b *BigQuery) RunQuery(query *SDK.QueryRequest) (*SDK.QueryResponse, error) {
-	resp, err := b.service.Jobs.Query(b.projectID, query).Do()
+	return b.RunQueryWithContext(context.Background(), query)
+}
+
+// RunQueryWithContext performes Jobs.Query operation with the given context.
+func (b *BigQuery) RunQueryWithContext(ctx context.Context, query *SDK.QueryRequest) (*SDK.QueryResponse, error) {
+	ctx = idempotentContext(ctx, query != nil && (query.RequestId != "" || query.DryRun))
+	resp, err := b.service.Jobs.Query(b.projectID, query).Context(ctx).Do()
 	b.logAPIError("Jobs.Query", err)
 	return resp, err
 }
@@ -84,7 +151,22 @@ func (b *BigQuery) RunQuery(query *SDK.QueryRequest) (*SDK.QueryResponse, error)
 // CancelJob performes Jobs.Cancel operation.
 // Requests that a job be cancelled. This call will return immediately, and the client will need to poll for the job status to see if the cancel completed successfully. Cancelled jobs may still incur costs. For more information, see pricing.
 func (b *BigQuery) CancelJob(jobID string) (*SDK.JobCancelResponse, error) {
-	resp, err := b.service.Jobs.Cancel(b.projectID, jobID).Do()
+	return b.CancelJobWithContext(context.Background(), jobID)
+}
+
+// CancelJobWithContext performes Jobs.Cancel operation with the given context.
+func (b *BigQuery) CancelJobWithContext(ctx context.Context, jobID string) (*SDK.JobCancelResponse, error) {
+	return b.CancelJobInLocationWithContext(ctx, jobID, "")
+}
+
+// CancelJobInLocationWithContext performes Jobs.Cancel operation for the job in the location.
+func (b *BigQuery) CancelJobInLocationWithContext(ctx context.Context, jobID, location string) (*SDK.JobCancelResponse, error) {
+	call := b.service.Jobs.Cancel(b.projectID, jobID).Context(ctx)
+	if location != "" {
+		call = call.Location(location)
+	}
+
+	resp, err := call.Do()
 	b.logAPIError("Jobs.Cancel", err, logArgs("jobID", jobID))
 	return resp, err
 }
@@ -92,7 +174,23 @@ func (b *BigQuery) CancelJob(jobID string) (*SDK.JobCancelResponse, error) {
 // GetJob performes Jobs.Get operation.
 // Returns information about a specific job. Job information is available for a six month period after creation. Requires that you're the person who ran the job, or have the Is Owner project role.
 func (b *BigQuery) GetJob(jobID string) (*SDK.Job, error) {
-	j, err := b.service.Jobs.Get(b.projectID, jobID).Do()
+	return b.GetJobWithContext(context.Background(), jobID)
+}
+
+// GetJobWithContext performes Jobs.Get operation with the given context.
+func (b *BigQuery) GetJobWithContext(ctx context.Context, jobID string) (*SDK.Job, error) {
+	return b.GetJobInLocationWithContext(ctx, jobID, "")
+}
+
+// GetJobInLocationWithContext performes Jobs.Get operation for the job in the location.
+// The location is required for the jobs outside of the US and EU multi-regional location.
+func (b *BigQuery) GetJobInLocationWithContext(ctx context.Context, jobID, location string) (*SDK.Job, error) {
+	call := b.service.Jobs.Get(b.projectID, jobID).Context(ctx)
+	if location != "" {
+		call = call.Location(location)
+	}
+
+	j, err := call.Do()
 	b.logAPIError("Jobs.Get", err, logArgs("jobID", jobID))
 	return j, err
 }
@@ -100,7 +198,12 @@ func (b *BigQuery) GetJob(jobID string) (*SDK.Job, error) {
 // ListJobs performes Jobs.List operation.
 // Lists all jobs that you started in the specified project. Job information is available for a six month period after creation. The job list is sorted in reverse chronological order, by job creation time. Requires the Can View project role, or the Is Owner project role if you set the allUsers property.
 func (b *BigQuery) ListJobs() (*SDK.JobList, error) {
-	list, err := b.service.Jobs.List(b.projectID).Do()
+	return b.ListJobsWithContext(context.Background())
+}
+
+// ListJobsWithContext performes Jobs.List operation with the given context.
+func (b *BigQuery) ListJobsWithContext(ctx context.Context) (*SDK.JobList, error) {
+	list, err := b.service.Jobs.List(b.projectID).Context(ctx).Do()
 	b.logAPIError("Jobs.List", err)
 	return list, err
 }
@@ -108,7 +211,12 @@ func (b *BigQuery) ListJobs() (*SDK.JobList, error) {
 // GetQueryResults performes Jobs.GetQueryResults operation.
 // Retrieves the results of a query job.
 func (b *BigQuery) GetQueryResults(jobID string) (*SDK.GetQueryResultsResponse, error) {
-	resp, err := b.service.Jobs.GetQueryResults(b.projectID, jobID).Do()
+	return b.GetQueryResultsWithContext(context.Background(), jobID)
+}
+
+// GetQueryResultsWithContext performes Jobs.GetQueryResults operation with the given context.
+func (b *BigQuery) GetQueryResultsWithContext(ctx context.Context, jobID string) (*SDK.GetQueryResultsResponse, error) {
+	resp, err := b.service.Jobs.GetQueryResults(b.projectID, jobID).Context(ctx).Do()
 	b.logAPIError("Jobs.GetQueryResults", err, logArgs("jobID", jobID))
 	return resp, err
 }
@@ -120,7 +228,13 @@ func (b *BigQuery) GetQueryResults(jobID string) (*SDK.GetQueryResultsResponse,
 // InsertAll performes Tabledata.InsertAll operation.
 // Streams data into BigQuery one record at a time without needing to run a load job. For more information, see streaming data into BigQuery.
 func (b *BigQuery) InsertAll(datasetID string, tableID string, rows *SDK.TableDataInsertAllRequest) (*SDK.TableDataInsertAllResponse, error) {
-	resp, err := b.service.Tabledata.InsertAll(b.projectID, datasetID, tableID, rows).Do()
+	return b.InsertAllWithContext(context.Background(), datasetID, tableID, rows)
+}
+
+// InsertAllWithContext performes Tabledata.InsertAll operation with the given context.
+func (b *BigQuery) InsertAllWithContext(ctx context.Context, datasetID string, tableID string, rows *SDK.TableDataInsertAllRequest) (*SDK.TableDataInsertAllResponse, error) {
+	ctx = idempotentContext(ctx, hasInsertIDs(rows))
+	resp, err := b.service.Tabledata.InsertAll(b.projectID, datasetID, tableID, rows).Context(ctx).Do()
 	b.logAPIError("Tabledata.InsertAll", err, logArgs("datasetID", datasetID), logArgs("tableID", tableID))
 	return resp, err
 }
@@ -128,7 +242,12 @@ func (b *BigQuery) InsertAll(datasetID string, tableID string, rows *SDK.TableDa
 // GetTableData performes Tabledata.List operation.
 // Retrieves table data from a specified set of rows. Requires the READER dataset role.
 func (b *BigQuery) GetTableData(datasetID string, tableID string) (*SDK.TableDataList, error) {
-	list, err := b.service.Tabledata.List(b.projectID, datasetID, tableID).Do()
+	return b.GetTableDataWithContext(context.Background(), datasetID, tableID)
+}
+
+// GetTableDataWithContext performes Tabledata.List operation with the given context.
+func (b *BigQuery) GetTableDataWithContext(ctx context.Context, datasetID string, tableID string) (*SDK.TableDataList, error) {
+	list, err := b.service.Tabledata.List(b.projectID, datasetID, tableID).Context(ctx).Do()
 	b.logAPIError("Tabledata.List", err, logArgs("datasetID", datasetID), logArgs("tableID", tableID))
 	return list, err
 }
@@ -140,7 +259,12 @@ func (b *BigQuery) GetTableData(datasetID string, tableID string) (*SDK.TableDat
 // CreateTable performes Table.Insert operation.
 // Creates a new, empty table in the dataset.
 func (b *BigQuery) CreateTable(datasetID string, tbl *SDK.Table) (*Table, error) {
-	t, err := b.service.Tables.Insert(b.projectID, datasetID, tbl).Do()
+	return b.CreateTableWithContext(context.Background(), datasetID, tbl)
+}
+
+// CreateTableWithContext performes Table.Insert operation with the given context.
+func (b *BigQuery) CreateTableWithContext(ctx context.Context, datasetID string, tbl *SDK.Table) (*Table, error) {
+	t, err := b.service.Tables.Insert(b.projectID, datasetID, tbl).Context(ctx).Do()
 	b.logAPIError("Table.Insert", err, logArgs("datasetID", datasetID))
 	return &Table{t}, err
 }
@@ -148,7 +272,12 @@ func (b *BigQuery) CreateTable(datasetID string, tbl *SDK.Table) (*Table, error)
 // PatchTable performes Tables.Patch operation.
 // Updates information in an existing table. The update method replaces the entire table resource, whereas the patch method only replaces fields that are provided in the submitted table resource. This method supports patch semantics.
 func (b *BigQuery) PatchTable(datasetID string, tableID string, tbl *SDK.Table) (*Table, error) {
-	t, err := b.service.Tables.Patch(b.projectID, datasetID, tableID, tbl).Do()
+	return b.PatchTableWithContext(context.Background(), datasetID, tableID, tbl)
+}
+
+// PatchTableWithContext performes Tables.Patch operation with the given context.
+func (b *BigQuery) PatchTableWithContext(ctx context.Context, datasetID string, tableID string, tbl *SDK.Table) (*Table, error) {
+	t, err := b.service.Tables.Patch(b.projectID, datasetID, tableID, tbl).Context(ctx).Do()
 	b.logAPIError("Table.Patch", err, logArgs("datasetID", datasetID), logArgs("tableID", tableID))
 	return &Table{t}, err
 }
@@ -156,7 +285,12 @@ func (b *BigQuery) PatchTable(datasetID string, tableID string, tbl *SDK.Table)
 // UpdateTable performes Tables.Update operation.
 // Updates information in an existing table. The update method replaces the entire table resource, whereas the patch method only replaces fields that are provided in the submitted table resource.
 func (b *BigQuery) UpdateTable(datasetID string, tableID string, tbl *SDK.Table) (*Table, error) {
-	t, err := b.service.Tables.Update(b.projectID, datasetID, tableID, tbl).Do()
+	return b.UpdateTableWithContext(context.Background(), datasetID, tableID, tbl)
+}
+
+// UpdateTableWithContext performes Tables.Update operation with the given context.
+func (b *BigQuery) UpdateTableWithContext(ctx context.Context, datasetID string, tableID string, tbl *SDK.Table) (*Table, error) {
+	t, err := b.service.Tables.Update(b.projectID, datasetID, tableID, tbl).Context(ctx).Do()
 	b.logAPIError("Table.Update", err, logArgs("datasetID", datasetID), logArgs("tableID", tableID))
 	return &Table{t}, err
 }
@@ -164,7 +298,12 @@ func (b *BigQuery) UpdateTable(datasetID string, tableID string, tbl *SDK.Table)
 // DropTable performes Tables.Delete operation.
 // Deletes the table specified by tableId from the dataset. If the table contains data, all the data will be deleted.
 func (b *BigQuery) DropTable(datasetID string, tableID string) error {
-	err := b.service.Tables.Delete(b.projectID, datasetID, tableID).Do()
+	return b.DropTableWithContext(context.Background(), datasetID, tableID)
+}
+
+// DropTableWithContext performes Tables.Delete operation with the given context.
+func (b *BigQuery) DropTableWithContext(ctx context.Context, datasetID string, tableID string) error {
+	err := b.service.Tables.Delete(b.projectID, datasetID, tableID).Context(ctx).Do()
 	b.logAPIError("Table.Delete", err, logArgs("datasetID", datasetID), logArgs("tableID", tableID))
 	return err
 }
@@ -172,7 +311,12 @@ func (b *BigQuery) DropTable(datasetID string, tableID string) error {
 // GetTable performes Tables.Get operation.
 // Gets the specified table resource by table ID. This method does not return the data in the table, it only returns the table resource, which describes the structure of this table.
 func (b *BigQuery) GetTable(datasetID string, tableID string) (*Table, error) {
-	t, err := b.service.Tables.Get(b.projectID, datasetID, tableID).Do()
+	return b.GetTableWithContext(context.Background(), datasetID, tableID)
+}
+
+// GetTableWithContext performes Tables.Get operation with the given context.
+func (b *BigQuery) GetTableWithContext(ctx context.Context, datasetID string, tableID string) (*Table, error) {
+	t, err := b.service.Tables.Get(b.projectID, datasetID, tableID).Context(ctx).Do()
 	b.logAPIError("Table.Get", err, logArgs("datasetID", datasetID), logArgs("tableID", tableID))
 	return &Table{t}, err
 }
@@ -180,11 +324,121 @@ func (b *BigQuery) GetTable(datasetID string, tableID string) (*Table, error) {
 // ListTables performes Tables.List operation.
 // Lists all tables in the specified dataset. Requires the READER dataset role.
 func (b *BigQuery) ListTables(datasetID string, tableID string) (*SDK.TableList, error) {
-	list, err := b.service.Tables.List(b.projectID, datasetID).Do()
+	return b.ListTablesWithContext(context.Background(), datasetID, tableID)
+}
+
+// ListTablesWithContext performes Tables.List operation with the given context.
+func (b *BigQuery) ListTablesWithContext(ctx context.Context, datasetID string, tableID string) (*SDK.TableList, error) {
+	list, err := b.service.Tables.List(b.projectID, datasetID).Context(ctx).Do()
 	b.logAPIError("Table.List", err, logArgs("datasetID", datasetID), logArgs("tableID", tableID))
 	return list, err
 }
 
+// GetTableIamPolicy performes Tables.GetIamPolicy operation.
+// Gets the access control policy for the table.
+func (b *BigQuery) GetTableIamPolicy(datasetID string, tableID string) (*SDK.Policy, error) {
+	return b.GetTableIamPolicyWithContext(context.Background(), datasetID, tableID)
+}
+
+// GetTableIamPolicyWithContext performes Tables.GetIamPolicy operation with the given context.
+func (b *BigQuery) GetTableIamPolicyWithContext(ctx context.Context, datasetID string, tableID string) (*SDK.Policy, error) {
+	// GetIamPolicy uses POST method, but it does not change anything.
+	ctx = idempotentContext(ctx, true)
+	policy, err := b.service.Tables.GetIamPolicy(b.tableResource(datasetID, tableID), &SDK.GetIamPolicyRequest{}).Context(ctx).Do()
+	b.logAPIError("Table.GetIamPolicy", err, logArgs("datasetID", datasetID), logArgs("tableID", tableID))
+	return policy, err
+}
+
+// SetTableIamPolicy performes Tables.SetIamPolicy operation.
+// Sets the access control policy for the table, and replaces any existing policy.
+func (b *BigQuery) SetTableIamPolicy(datasetID string, tableID string, policy *SDK.Policy) (*SDK.Policy, error) {
+	return b.SetTableIamPolicyWithContext(context.Background(), datasetID, tableID, policy)
+}
+
+// SetTableIamPolicyWithContext performes Tables.SetIamPolicy operation with the given context.
+func (b *BigQuery) SetTableIamPolicyWithContext(ctx context.Context, datasetID string, tableID string, policy *SDK.Policy) (*SDK.Policy, error) {
+	req := &SDK.SetIamPolicyRequest{
+		Policy: policy,
+	}
+	p, err := b.service.Tables.SetIamPolicy(b.tableResource(datasetID, tableID), req).Context(ctx).Do()
+	b.logAPIError("Table.SetIamPolicy", err, logArgs("datasetID", datasetID), logArgs("tableID", tableID))
+	return p, err
+}
+
+// ==========
+// Routines
+// ==========
+
+// CreateRoutine performes Routines.Insert operation.
+// Creates a new routine in the dataset.
+func (b *BigQuery) CreateRoutine(datasetID string, routine *SDK.Routine) (*Routine, error) {
+	return b.CreateRoutineWithContext(context.Background(), datasetID, routine)
+}
+
+// CreateRoutineWithContext performes Routines.Insert operation with the given context.
+func (b *BigQuery) CreateRoutineWithContext(ctx context.Context, datasetID string, routine *SDK.Routine) (*Routine, error) {
+	r, err := b.service.Routines.Insert(b.projectID, datasetID, routine).Context(ctx).Do()
+	b.logAPIError("Routines.Insert", err, logArgs("datasetID", datasetID))
+	return &Routine{r}, err
+}
+
+// UpdateRoutine performes Routines.Update operation.
+// Updates information in an existing routine. The update method replaces the entire Routine resource.
+func (b *BigQuery) UpdateRoutine(datasetID string, routineID string, routine *SDK.Routine) (*Routine, error) {
+	return b.UpdateRoutineWithContext(context.Background(), datasetID, routineID, routine)
+}
+
+// UpdateRoutineWithContext performes Routines.Update operation with the given context.
+func (b *BigQuery) UpdateRoutineWithContext(ctx context.Context, datasetID string, routineID string, routine *SDK.Routine) (*Routine, error) {
+	r, err := b.service.Routines.Update(b.projectID, datasetID, routineID, routine).Context(ctx).Do()
+	b.logAPIError("Routines.Update", err, logArgs("datasetID", datasetID), logArgs("routineID", routineID))
+	return &Routine{r}, err
+}
+
+// DeleteRoutine performes Routines.Delete operation.
+// Deletes the routine specified by routineId from the dataset.
+func (b *BigQuery) DeleteRoutine(datasetID string, routineID string) error {
+	return b.DeleteRoutineWithContext(context.Background(), datasetID, routineID)
+}
+
+// DeleteRoutineWithContext performes Routines.Delete operation with the given context.
+func (b *BigQuery) DeleteRoutineWithContext(ctx context.Context, datasetID string, routineID string) error {
+	err := b.service.Routines.Delete(b.projectID, datasetID, routineID).Context(ctx).Do()
+	b.logAPIError("Routines.Delete", err, logArgs("datasetID", datasetID), logArgs("routineID", routineID))
+	return err
+}
+
+// GetRoutine performes Routines.Get operation.
+// Gets the specified routine resource by routine ID.
+func (b *BigQuery) GetRoutine(datasetID string, routineID string) (*Routine, error) {
+	return b.GetRoutineWithContext(context.Background(), datasetID, routineID)
+}
+
+// GetRoutineWithContext performes Routines.Get operation with the given context.
+func (b *BigQuery) GetRoutineWithContext(ctx context.Context, datasetID string, routineID string) (*Routine, error) {
+	r, err := b.service.Routines.Get(b.projectID, datasetID, routineID).Context(ctx).Do()
+	b.logAPIError("Routines.Get", err, logArgs("datasetID", datasetID), logArgs("routineID", routineID))
+	return &Routine{r}, err
+}
+
+// ListRoutines performes Routines.List operation.
+// Lists all routines in the specified dataset. Requires the READER dataset role.
+func (b *BigQuery) ListRoutines(datasetID string) (*SDK.ListRoutinesResponse, error) {
+	return b.ListRoutinesWithContext(context.Background(), datasetID)
+}
+
+// ListRoutinesWithContext performes Routines.List operation with the given context.
+func (b *BigQuery) ListRoutinesWithContext(ctx context.Context, datasetID string) (*SDK.ListRoutinesResponse, error) {
+	list, err := b.service.Routines.List(b.projectID, datasetID).Context(ctx).Do()
+	b.logAPIError("Routines.List", err, logArgs("datasetID", datasetID))
+	return list, err
+}
+
+// tableResource returns the resource name of the table for IAM operations.
+func (b *BigQuery) tableResource(datasetID string, tableID string) string {
+	return fmt.Sprintf("projects/%s/datasets/%s/tables/%s", b.projectID, datasetID, tableID)
+}
+
 func (b *BigQuery) logAPIError(apiName string, err error, opts ...string) {
 	if err == nil {
 		return
@@ -200,3 +454,36 @@ func (b *BigQuery) logAPIError(apiName string, err error, opts ...string) {
 func logArgs(key, value string) string {
 	return fmt.Sprintf("%s=[%s]", key, value)
 }
+
+// idempotentContext marks the request as idempotent to be retried by config.RetryPolicy when ok is true.
+func idempotentContext(ctx context.Context, ok bool) context.Context {
+	if !ok {
+		return ctx
+	}
+	return config.WithIdempotent(ctx)
+}
+
+// isIdempotentJob returns true when the job is not run twice on retry.
+// The job with the same job ID is rejected as duplicate, and dry run does not run the job.
+func isIdempotentJob(job *SDK.Job) bool {
+	if job == nil {
+		return false
+	}
+	if job.JobReference != nil && job.JobReference.JobId != "" {
+		return true
+	}
+	return job.Configuration != nil && job.Configuration.DryRun
+}
+
+// hasInsertIDs returns true when all of the rows have insert ID for the best effort de-duplication.
+func hasInsertIDs(rows *SDK.TableDataInsertAllRequest) bool {
+	if rows == nil || len(rows.Rows) == 0 {
+		return false
+	}
+	for _, r := range rows.Rows {
+		if r.InsertId == "" {
+			return false
+		}
+	}
+	return true
+}
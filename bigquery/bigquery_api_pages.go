@@ -0,0 +1,127 @@
+package bigquery
+
+import (
+	"context"
+
+	SDK "google.golang.org/api/bigquery/v2"
+)
+
+// PageOption is optional parameters used for paginated list operations.
+type PageOption struct {
+	// PageToken is a token returned by the previous call to request the next page.
+	PageToken string
+	// MaxResults is the maximum number of results per page.
+	MaxResults int64
+	// StartIndex is zero-based index of the starting row. (Tabledata.List and Jobs.GetQueryResults only)
+	StartIndex uint64
+	// Location is the geographic location of the job. (Jobs.GetQueryResults only)
+	Location string
+	// TimeoutMs is how long to wait for the query to complete. (Jobs.GetQueryResults only)
+	TimeoutMs int64
+
+	// MaxRows is the maximum number of items returned by iterators in total.
+	// zero means no limit.
+	MaxRows int64
+}
+
+// ListDatasetsPageWithContext performes Datasets.List operation for a single page.
+func (b *BigQuery) ListDatasetsPageWithContext(ctx context.Context, opt PageOption) (*SDK.DatasetList, error) {
+	call := b.service.Datasets.List(b.projectID).Context(ctx)
+	if opt.PageToken != "" {
+		call = call.PageToken(opt.PageToken)
+	}
+	if opt.MaxResults > 0 {
+		call = call.MaxResults(opt.MaxResults)
+	}
+
+	list, err := call.Do()
+	b.logAPIError("Datasets.List", err, logArgs("pageToken", opt.PageToken))
+	return list, err
+}
+
+// ListJobsPageWithContext performes Jobs.List operation for a single page.
+func (b *BigQuery) ListJobsPageWithContext(ctx context.Context, opt PageOption) (*SDK.JobList, error) {
+	call := b.service.Jobs.List(b.projectID).Context(ctx)
+	if opt.PageToken != "" {
+		call = call.PageToken(opt.PageToken)
+	}
+	if opt.MaxResults > 0 {
+		call = call.MaxResults(opt.MaxResults)
+	}
+
+	list, err := call.Do()
+	b.logAPIError("Jobs.List", err, logArgs("pageToken", opt.PageToken))
+	return list, err
+}
+
+// ListTablesPageWithContext performes Tables.List operation for a single page.
+func (b *BigQuery) ListTablesPageWithContext(ctx context.Context, datasetID string, opt PageOption) (*SDK.TableList, error) {
+	call := b.service.Tables.List(b.projectID, datasetID).Context(ctx)
+	if opt.PageToken != "" {
+		call = call.PageToken(opt.PageToken)
+	}
+	if opt.MaxResults > 0 {
+		call = call.MaxResults(opt.MaxResults)
+	}
+
+	list, err := call.Do()
+	b.logAPIError("Table.List", err, logArgs("datasetID", datasetID), logArgs("pageToken", opt.PageToken))
+	return list, err
+}
+
+// GetTableDataPageWithContext performes Tabledata.List operation for a single page.
+func (b *BigQuery) GetTableDataPageWithContext(ctx context.Context, datasetID, tableID string, opt PageOption) (*SDK.TableDataList, error) {
+	call := b.service.Tabledata.List(b.projectID, datasetID, tableID).Context(ctx)
+	if opt.PageToken != "" {
+		call = call.PageToken(opt.PageToken)
+	}
+	if opt.MaxResults > 0 {
+		call = call.MaxResults(opt.MaxResults)
+	}
+	if opt.StartIndex > 0 {
+		call = call.StartIndex(opt.StartIndex)
+	}
+
+	list, err := call.Do()
+	b.logAPIError("Tabledata.List", err, logArgs("datasetID", datasetID), logArgs("tableID", tableID), logArgs("pageToken", opt.PageToken))
+	return list, err
+}
+
+// GetQueryResultsPageWithContext performes Jobs.GetQueryResults operation for a single page.
+func (b *BigQuery) GetQueryResultsPageWithContext(ctx context.Context, jobID string, opt PageOption) (*SDK.GetQueryResultsResponse, error) {
+	call := b.service.Jobs.GetQueryResults(b.projectID, jobID).Context(ctx)
+	if opt.PageToken != "" {
+		call = call.PageToken(opt.PageToken)
+	}
+	if opt.MaxResults > 0 {
+		call = call.MaxResults(opt.MaxResults)
+	}
+	if opt.StartIndex > 0 {
+		call = call.StartIndex(opt.StartIndex)
+	}
+	if opt.Location != "" {
+		call = call.Location(opt.Location)
+	}
+	if opt.TimeoutMs > 0 {
+		call = call.TimeoutMs(opt.TimeoutMs)
+	}
+
+	resp, err := call.Do()
+	b.logAPIError("Jobs.GetQueryResults", err, logArgs("jobID", jobID), logArgs("pageToken", opt.PageToken))
+	return resp, err
+}
+
+// ListRoutinesPageWithContext performes Routines.List operation for a single page.
+func (b *BigQuery) ListRoutinesPageWithContext(ctx context.Context, datasetID string, opt PageOption) (*SDK.ListRoutinesResponse, error) {
+	call := b.service.Routines.List(b.projectID, datasetID).Context(ctx)
+	if opt.PageToken != "" {
+		call = call.PageToken(opt.PageToken)
+	}
+	if opt.MaxResults > 0 {
+		call = call.MaxResults(opt.MaxResults)
+	}
+
+	list, err := call.Do()
+	b.logAPIError("Routines.List", err, logArgs("datasetID", datasetID), logArgs("pageToken", opt.PageToken))
+	return list, err
+}
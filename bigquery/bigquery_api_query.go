@@ -1,9 +1,101 @@
 package bigquery
 
+import (
+	"context"
+	"fmt"
+
+	SDK "google.golang.org/api/bigquery/v2"
+)
+
+// Query runs the query.
 func (b *BigQuery) Query(opt QueryOption) (*QueryResponse, error) {
-	resp, err := b.RunQuery(opt.ToRequest())
+	return b.QueryWithContext(context.Background(), opt)
+}
+
+// QueryWithContext runs the query with the given context.
+// When the query budget is set, it returns *QueryBudgetError for the query whose estimate exceeds the budget.
+func (b *BigQuery) QueryWithContext(ctx context.Context, opt QueryOption) (*QueryResponse, error) {
+	if b.queryBudget > 0 && !opt.DryRun {
+		if err := b.checkQueryBudget(ctx, opt); err != nil {
+			return nil, err
+		}
+	}
+
+	req, err := opt.BuildRequest()
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := b.RunQueryWithContext(ctx, req)
 	if err != nil {
 		return nil, err
 	}
-	return &QueryResponse{resp}, nil
+	return &QueryResponse{
+		QueryResponse: resp,
+		client:        b,
+	}, nil
+}
+
+// QueryEstimate is the result of the dry run of the query.
+type QueryEstimate struct {
+	// TotalBytesProcessed is the estimated bytes processed by the query.
+	TotalBytesProcessed int64
+	ReferencedTables    []*SDK.TableReference
+	Schema              *SDK.TableSchema
+	StatementType       string
+}
+
+// EstimateQuery performes the dry run of the query and returns the estimate.
+func (b *BigQuery) EstimateQuery(opt QueryOption) (*QueryEstimate, error) {
+	return b.EstimateQueryWithContext(context.Background(), opt)
+}
+
+// EstimateQueryWithContext performes the dry run of the query with the given context.
+func (b *BigQuery) EstimateQueryWithContext(ctx context.Context, opt QueryOption) (*QueryEstimate, error) {
+	opt.DryRun = true
+	req, err := opt.buildJob(b.projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := b.StartJobWithContext(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &QueryEstimate{}
+	if job.Statistics == nil {
+		return e, nil
+	}
+	e.TotalBytesProcessed = job.BytesProcessed()
+	if stats := job.Statistics.Query; stats != nil {
+		e.ReferencedTables = stats.ReferencedTables
+		e.Schema = stats.Schema
+		e.StatementType = stats.StatementType
+	}
+	return e, nil
+}
+
+func (b *BigQuery) checkQueryBudget(ctx context.Context, opt QueryOption) error {
+	e, err := b.EstimateQueryWithContext(ctx, opt)
+	if err != nil {
+		return err
+	}
+	if e.TotalBytesProcessed > b.queryBudget {
+		return &QueryBudgetError{
+			EstimatedBytes: e.TotalBytesProcessed,
+			Budget:         b.queryBudget,
+		}
+	}
+	return nil
+}
+
+// QueryBudgetError is returned when the estimated bytes of the query exceeds the query budget.
+type QueryBudgetError struct {
+	EstimatedBytes int64
+	Budget         int64
+}
+
+func (e *QueryBudgetError) Error() string {
+	return fmt.Sprintf("estimated bytes exceed the query budget; estimated=[%d] budget=[%d]", e.EstimatedBytes, e.Budget)
 }
@@ -0,0 +1,128 @@
+package bigquery
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+
+	SDK "google.golang.org/api/bigquery/v2"
+)
+
+// newQueryTestServer returns the fake of Jobs.Insert for the dry run and Jobs.Query,
+// and the recorded requests of Jobs.Query.
+func newQueryTestServer(estimatedBytes int64) (http.Handler, *jobTestServer, *[]*SDK.QueryRequest) {
+	srv := &jobTestServer{
+		states: []string{JobStateDone},
+		statistics: &SDK.JobStatistics{
+			Query: &SDK.JobStatistics2{
+				TotalBytesProcessed: estimatedBytes,
+				StatementType:       "SELECT",
+				ReferencedTables: []*SDK.TableReference{
+					{ProjectId: "project", DatasetId: "ds", TableId: "events"},
+				},
+			},
+		},
+	}
+
+	var queries []*SDK.QueryRequest
+	mux := http.NewServeMux()
+	mux.HandleFunc("/bigquery/v2/projects/project/queries", func(w http.ResponseWriter, r *http.Request) {
+		req := &SDK.QueryRequest{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			http.Error(w, `{"error":{"code":400,"message":"invalid body"}}`, http.StatusBadRequest)
+			return
+		}
+		queries = append(queries, req)
+		json.NewEncoder(w).Encode(&SDK.QueryResponse{JobComplete: true})
+	})
+	mux.Handle("/", srv)
+	return mux, srv, &queries
+}
+
+func TestBigQueryEstimateQuery(t *testing.T) {
+	handler, srv, queries := newQueryTestServer(1000)
+	b := newTestBigQuery(t, handler)
+
+	e, err := b.EstimateQuery(QueryOption{
+		SQL:            "SELECT * FROM ds.events",
+		Location:       "EU",
+		MaxBytesBilled: 5000,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	want := &QueryEstimate{
+		TotalBytesProcessed: 1000,
+		ReferencedTables:    []*SDK.TableReference{{ProjectId: "project", DatasetId: "ds", TableId: "events"}},
+		StatementType:       "SELECT",
+	}
+	if !reflect.DeepEqual(e, want) {
+		t.Errorf("got %#v, want %#v", e, want)
+	}
+
+	// it's estimated by the dry run job without running the query.
+	if len(*queries) != 0 {
+		t.Errorf("got %d queries, want no query", len(*queries))
+	}
+	if want := []string{"POST /jobs"}; !reflect.DeepEqual(srv.requests, want) {
+		t.Errorf("got %v, want %v", srv.requests, want)
+	}
+	conf := srv.inserted.Configuration
+	if !conf.DryRun {
+		t.Errorf("dryRun: got false, want true")
+	}
+	if conf.Query.Query != "SELECT * FROM ds.events" || conf.Query.MaximumBytesBilled != 5000 {
+		t.Errorf("got query=[%s] maximumBytesBilled=[%d]", conf.Query.Query, conf.Query.MaximumBytesBilled)
+	}
+	if loc := srv.inserted.JobReference.Location; loc != "EU" {
+		t.Errorf("location: got %s, want EU", loc)
+	}
+}
+
+func TestBigQueryQueryBudget(t *testing.T) {
+	tests := []struct {
+		name           string
+		budget         int64
+		dryRun         bool
+		estimatedBytes int64
+		wantErr        error
+		wantDryRuns    int
+		wantQueries    int
+	}{
+		{"no budget", 0, false, 2000, nil, 0, 1},
+		{"within budget", 1000, false, 1000, nil, 1, 1},
+		{"over budget", 1000, false, 1001, &QueryBudgetError{EstimatedBytes: 1001, Budget: 1000}, 1, 0},
+		{"dry run is not checked", 1000, true, 2000, nil, 0, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, srv, queries := newQueryTestServer(tt.estimatedBytes)
+			b := newTestBigQuery(t, handler)
+			b.SetQueryBudget(tt.budget)
+
+			_, err := b.Query(QueryOption{SQL: "SELECT * FROM ds.events", DryRun: tt.dryRun})
+			if !reflect.DeepEqual(err, tt.wantErr) {
+				t.Errorf("got %#v, want %#v", err, tt.wantErr)
+			}
+			if len(srv.jobs) != tt.wantDryRuns {
+				t.Errorf("dry runs: got %d, want %d", len(srv.jobs), tt.wantDryRuns)
+			}
+			for _, job := range srv.jobs {
+				if !job.Configuration.DryRun {
+					t.Errorf("got the job without dry run: %#v", job.Configuration)
+				}
+			}
+			if len(*queries) != tt.wantQueries {
+				t.Fatalf("queries: got %d, want %d", len(*queries), tt.wantQueries)
+			}
+			for _, q := range *queries {
+				if q.Query != "SELECT * FROM ds.events" || q.DryRun != tt.dryRun {
+					t.Errorf("got query=[%s] dryRun=[%v]", q.Query, q.DryRun)
+				}
+			}
+		})
+	}
+}
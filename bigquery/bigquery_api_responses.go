@@ -13,3 +13,8 @@ type Table struct {
 type Dataset struct {
 	*SDK.Dataset
 }
+
+// Routine represents SDK.Routine.
+type Routine struct {
+	*SDK.Routine
+}
@@ -0,0 +1,104 @@
+package bigquery
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	SDK "google.golang.org/api/bigquery/v2"
+
+	"github.com/evalphobia/google-api-go-wrapper/config"
+)
+
+// newTestBigQuery returns BigQuery client connected to the handler.
+func newTestBigQuery(t *testing.T, handler http.Handler) *BigQuery {
+	ts := httptest.NewServer(handler)
+	t.Cleanup(ts.Close)
+
+	b, err := New(config.Config{
+		Endpoint:         ts.URL + "/bigquery/v2/",
+		NoAuthentication: true,
+		Retry:            &config.RetryPolicy{MaxAttempts: 1},
+	}, "project")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	return b
+}
+
+func TestNewWithEndpoint(t *testing.T) {
+	var gotPath, gotAuth string
+	var gotBody SDK.Dataset
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.Method + " " + r.URL.Path
+		gotAuth = r.Header.Get("Authorization")
+		if err := json.NewDecoder(r.Body).Decode(&gotBody); err != nil {
+			http.Error(w, `{"error":{"code":400,"message":"invalid body"}}`, http.StatusBadRequest)
+			return
+		}
+		json.NewEncoder(w).Encode(&gotBody)
+	}))
+	defer ts.Close()
+
+	b, err := New(config.Config{
+		Endpoint:         ts.URL + "/bigquery/v2/",
+		NoAuthentication: true,
+	}, "project")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	ds := &SDK.Dataset{
+		DatasetReference: &SDK.DatasetReference{ProjectId: "project", DatasetId: "ds"},
+		Location:         "US",
+	}
+	if _, err := b.CreateDataset(ds); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	if want := "POST /bigquery/v2/projects/project/datasets"; gotPath != want {
+		t.Errorf("got %s, want %s", gotPath, want)
+	}
+	if gotAuth != "" {
+		t.Errorf("got Authorization=[%s], want no Authorization", gotAuth)
+	}
+	if !reflect.DeepEqual(&gotBody, ds) {
+		t.Errorf("got %#v, want %#v", &gotBody, ds)
+	}
+}
+
+func TestBigQueryNilRequest(t *testing.T) {
+	b := newTestBigQuery(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":{"code":400,"message":"invalid request"}}`, http.StatusBadRequest)
+	}))
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		fn   func() error
+	}{
+		{"RunQuery", func() error {
+			_, err := b.RunQueryWithContext(ctx, nil)
+			return err
+		}},
+		{"RunJob", func() error {
+			_, err := b.RunJobWithContext(ctx, nil)
+			return err
+		}},
+		{"InsertAll", func() error {
+			_, err := b.InsertAllWithContext(ctx, "dataset", "table", nil)
+			return err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.fn(); err == nil {
+				t.Errorf("expected error")
+			}
+		})
+	}
+}
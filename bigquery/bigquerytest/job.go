@@ -0,0 +1,420 @@
+package bigquerytest
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	SDK "google.golang.org/api/bigquery/v2"
+
+	"github.com/evalphobia/google-api-go-wrapper/bigquery"
+)
+
+const (
+	stateRunning = "RUNNING"
+	stateDone    = "DONE"
+)
+
+// job is the query job and its results.
+type job struct {
+	meta  *SDK.Job
+	polls int // remaining status checks until DONE.
+	seq   int
+
+	result *queryResult
+	err    *apiError
+}
+
+type queryResult struct {
+	schema     *SDK.TableSchema
+	rows       []*SDK.TableRow
+	referenced []*SDK.TableReference
+	bytes      int64
+}
+
+func (j *job) isDone() bool {
+	return j.meta.Status.State == stateDone
+}
+
+// poll moves the job state forward by the status check.
+func (j *job) poll() {
+	if j.isDone() {
+		return
+	}
+	j.polls--
+	if j.polls <= 0 {
+		j.finish()
+	}
+}
+
+func (j *job) finish() {
+	j.meta.Status.State = stateDone
+	j.meta.Statistics.EndTime = nowMillis()
+	if j.err != nil {
+		e := j.err.toErrorProto()
+		j.meta.Status.ErrorResult = e
+		j.meta.Status.Errors = []*SDK.ErrorProto{e}
+		return
+	}
+	if r := j.result; r != nil {
+		j.meta.Statistics.TotalBytesProcessed = r.bytes
+		j.meta.Statistics.Query = newQueryStatistics(r)
+	}
+}
+
+func newQueryStatistics(r *queryResult) *SDK.JobStatistics2 {
+	return &SDK.JobStatistics2{
+		ReferencedTables:    r.referenced,
+		Schema:              r.schema,
+		StatementType:       "SELECT",
+		TotalBytesBilled:    r.bytes,
+		TotalBytesProcessed: r.bytes,
+	}
+}
+
+// newJob registers the job and runs the query.
+// The job is DONE immediately or after the status checks of JobPolls.
+func (s *Server) newJob(meta *SDK.Job) *job {
+	s.jobSeq++
+	if meta.JobReference == nil {
+		meta.JobReference = &SDK.JobReference{}
+	}
+	ref := meta.JobReference
+	ref.ProjectId = s.projectID
+	if ref.JobId == "" {
+		ref.JobId = fmt.Sprintf("job_fake_%d", s.jobSeq)
+	}
+	if ref.Location == "" {
+		ref.Location = defaultLocation
+	}
+	meta.Id = s.projectID + ":" + ref.Location + "." + ref.JobId
+	meta.Kind = "bigquery#job"
+	meta.Status = &SDK.JobStatus{
+		State: stateRunning,
+	}
+	now := nowMillis()
+	meta.Statistics = &SDK.JobStatistics{
+		CreationTime: now,
+		StartTime:    now,
+	}
+
+	j := &job{
+		meta:  meta,
+		polls: s.JobPolls,
+		seq:   s.jobSeq,
+	}
+	if meta.Configuration != nil && meta.Configuration.Query != nil {
+		j.result, j.err = s.runQuery(meta.Configuration.Query)
+	} else {
+		j.err = newAPIError(http.StatusNotImplemented, "notImplemented", "the job type is not supported by the fake; only query jobs are supported")
+	}
+	if j.polls <= 0 {
+		j.finish()
+	}
+
+	s.jobs[ref.JobId] = j
+	return j
+}
+
+func (s *Server) getJobByID(jobID string) (*job, error) {
+	j, ok := s.jobs[jobID]
+	if !ok {
+		return nil, newAPIError(http.StatusNotFound, "notFound", "Not found: Job %s:%s", s.projectID, jobID)
+	}
+	return j, nil
+}
+
+func (s *Server) insertJob(r *http.Request) (*SDK.Job, error) {
+	var meta SDK.Job
+	if err := decodeBody(r, &meta); err != nil {
+		return nil, err
+	}
+	if meta.JobReference != nil && meta.JobReference.JobId != "" {
+		if _, ok := s.jobs[meta.JobReference.JobId]; ok {
+			return nil, newAPIError(http.StatusConflict, "duplicate", "Already Exists: Job %s:%s", s.projectID, meta.JobReference.JobId)
+		}
+	}
+
+	if meta.Configuration != nil && meta.Configuration.DryRun {
+		return s.dryRunJob(&meta)
+	}
+	return s.newJob(&meta).meta, nil
+}
+
+// dryRunJob validates the query and returns the estimate without registering the job.
+func (s *Server) dryRunJob(meta *SDK.Job) (*SDK.Job, error) {
+	if meta.Configuration.Query == nil {
+		return nil, newAPIError(http.StatusNotImplemented, "notImplemented", "the job type is not supported by the fake; only query jobs are supported")
+	}
+	result, err := s.runQuery(meta.Configuration.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	meta.Kind = "bigquery#job"
+	meta.Status = &SDK.JobStatus{
+		State: stateDone,
+	}
+	meta.Statistics = &SDK.JobStatistics{
+		CreationTime:        nowMillis(),
+		TotalBytesProcessed: result.bytes,
+		Query:               newQueryStatistics(result),
+	}
+	return meta, nil
+}
+
+func (s *Server) getJob(jobID string) (*SDK.Job, error) {
+	j, err := s.getJobByID(jobID)
+	if err != nil {
+		return nil, err
+	}
+	j.poll()
+	return j.meta, nil
+}
+
+func (s *Server) cancelJob(jobID string) (*SDK.JobCancelResponse, error) {
+	j, err := s.getJobByID(jobID)
+	if err != nil {
+		return nil, err
+	}
+	if !j.isDone() {
+		j.result = nil
+		j.err = newAPIError(http.StatusBadRequest, "stopped", "Job execution was cancelled: User requested cancellation")
+		j.finish()
+	}
+	return &SDK.JobCancelResponse{
+		Job:  j.meta,
+		Kind: "bigquery#jobCancelResponse",
+	}, nil
+}
+
+func (s *Server) listJobs() *SDK.JobList {
+	list := make([]*job, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		list = append(list, j)
+	}
+	// newer jobs first.
+	sort.Slice(list, func(i, k int) bool {
+		return list[i].seq > list[k].seq
+	})
+
+	result := &SDK.JobList{
+		Kind: "bigquery#jobList",
+	}
+	for _, j := range list {
+		result.Jobs = append(result.Jobs, &SDK.JobListJobs{
+			Configuration: j.meta.Configuration,
+			ErrorResult:   j.meta.Status.ErrorResult,
+			Id:            j.meta.Id,
+			JobReference:  j.meta.JobReference,
+			Kind:          j.meta.Kind,
+			State:         j.meta.Status.State,
+			Statistics:    j.meta.Statistics,
+			Status:        j.meta.Status,
+		})
+	}
+	return result
+}
+
+// query handles Jobs.Query, which runs the query job and returns the first page of the results.
+func (s *Server) query(r *http.Request) (*SDK.QueryResponse, error) {
+	var req SDK.QueryRequest
+	if err := decodeBody(r, &req); err != nil {
+		return nil, err
+	}
+
+	conf := &SDK.JobConfigurationQuery{
+		Query:           req.Query,
+		DefaultDataset:  req.DefaultDataset,
+		ParameterMode:   req.ParameterMode,
+		QueryParameters: req.QueryParameters,
+		UseLegacySql:    req.UseLegacySql,
+	}
+	if req.DryRun {
+		result, err := s.runQuery(conf)
+		if err != nil {
+			return nil, err
+		}
+		return &SDK.QueryResponse{
+			JobComplete:         true,
+			Kind:                "bigquery#queryResponse",
+			Schema:              result.schema,
+			TotalBytesProcessed: result.bytes,
+		}, nil
+	}
+
+	j := s.newJob(&SDK.Job{
+		Configuration: &SDK.JobConfiguration{
+			Labels: req.Labels,
+			Query:  conf,
+		},
+		JobReference: &SDK.JobReference{
+			Location: req.Location,
+		},
+	})
+	if j.isDone() && j.err != nil {
+		return nil, j.err
+	}
+
+	resp := &SDK.QueryResponse{
+		JobComplete:  j.isDone(),
+		JobReference: j.meta.JobReference,
+		Kind:         "bigquery#queryResponse",
+	}
+	if !resp.JobComplete {
+		return resp, nil
+	}
+
+	rows, token := pageResultRows(j.result.rows, req.MaxResults)
+	resp.PageToken = token
+	resp.Rows = rows
+	resp.Schema = j.result.schema
+	resp.TotalBytesProcessed = j.result.bytes
+	resp.TotalRows = uint64(len(j.result.rows))
+	return resp, nil
+}
+
+// getQueryResults handles Jobs.GetQueryResults.
+// It counts as the status check of the job.
+func (s *Server) getQueryResults(r *http.Request, jobID string) (*SDK.GetQueryResultsResponse, error) {
+	j, err := s.getJobByID(jobID)
+	if err != nil {
+		return nil, err
+	}
+	j.poll()
+
+	resp := &SDK.GetQueryResultsResponse{
+		JobComplete:  j.isDone(),
+		JobReference: j.meta.JobReference,
+		Kind:         "bigquery#getQueryResultsResponse",
+	}
+	if !resp.JobComplete {
+		return resp, nil
+	}
+	if j.err != nil {
+		return nil, j.err
+	}
+
+	rows, token, err := pageRows(r, j.result.rows)
+	if err != nil {
+		return nil, err
+	}
+	resp.PageToken = token
+	resp.Rows = rows
+	resp.Schema = j.result.schema
+	resp.TotalBytesProcessed = j.result.bytes
+	resp.TotalRows = uint64(len(j.result.rows))
+	return resp, nil
+}
+
+// pageResultRows returns the first page of the rows for Jobs.Query.
+func pageResultRows(rows []*SDK.TableRow, maxResults int64) ([]*SDK.TableRow, string) {
+	if maxResults > 0 && int(maxResults) < len(rows) {
+		return rows[:maxResults], strconv.Itoa(int(maxResults))
+	}
+	return rows, ""
+}
+
+// runQuery parses the query and evaluates it with the current table data.
+func (s *Server) runQuery(conf *SDK.JobConfigurationQuery) (*queryResult, *apiError) {
+	stmt, err := parseQuery(conf.Query, queryContext{
+		projectID:      s.projectID,
+		defaultDataset: conf.DefaultDataset,
+		params:         conf.QueryParameters,
+	})
+	if err != nil {
+		return nil, newAPIError(http.StatusBadRequest, "invalidQuery", "%s", err.Error())
+	}
+
+	ref := stmt.table
+	if ref.ProjectId != s.projectID {
+		return nil, newAPIError(http.StatusNotFound, "notFound", "Not found: Table %s:%s.%s", ref.ProjectId, ref.DatasetId, ref.TableId)
+	}
+	tbl, err := s.getTable(ref.DatasetId, ref.TableId)
+	if err != nil {
+		return nil, err.(*apiError)
+	}
+	if tbl.meta.Type != "TABLE" {
+		return nil, newAPIError(http.StatusNotImplemented, "notImplemented", "querying %s is not supported by the fake", tbl.meta.Type)
+	}
+
+	fields := tbl.meta.Schema.Fields
+	indexes := make([]int, 0, len(fields))
+	if stmt.columns == nil {
+		for i := range fields {
+			indexes = append(indexes, i)
+		}
+	}
+	for _, name := range stmt.columns {
+		idx := findField(fields, name)
+		if idx < 0 {
+			return nil, newAPIError(http.StatusBadRequest, "invalidQuery", "Unrecognized name: %s", name)
+		}
+		indexes = append(indexes, idx)
+	}
+
+	result := &queryResult{
+		schema:     &SDK.TableSchema{},
+		referenced: []*SDK.TableReference{tbl.meta.TableReference},
+	}
+	for _, idx := range indexes {
+		result.schema.Fields = append(result.schema.Fields, fields[idx])
+	}
+
+	// bytes processed are the size of the selected columns of all rows as BigQuery does.
+	for _, row := range tbl.rows {
+		for _, idx := range indexes {
+			result.bytes += estimateBytes(row.F[idx].V)
+		}
+	}
+
+	for _, row := range tbl.rows {
+		if stmt.limit >= 0 && int64(len(result.rows)) >= stmt.limit {
+			break
+		}
+		if stmt.where != nil {
+			m, err := bigquery.DecodeRow(tbl.meta.Schema, row)
+			if err != nil {
+				return nil, newAPIError(http.StatusInternalServerError, "internalError", "%s", err.Error())
+			}
+			v, err := stmt.where.eval(m)
+			if err != nil {
+				return nil, newAPIError(http.StatusBadRequest, "invalidQuery", "%s", err.Error())
+			}
+			if !isTrue(v) {
+				continue
+			}
+		}
+
+		cells := make([]*SDK.TableCell, len(indexes))
+		for i, idx := range indexes {
+			cells[i] = row.F[idx]
+		}
+		result.rows = append(result.rows, &SDK.TableRow{F: cells})
+	}
+	return result, nil
+}
+
+// estimateBytes returns the rough size of the cell value for the bytes processed.
+func estimateBytes(v interface{}) int64 {
+	switch vv := v.(type) {
+	case nil:
+		return 0
+	case string:
+		return int64(len(vv))
+	case []interface{}:
+		var n int64
+		for _, elem := range vv {
+			n += estimateBytes(elem)
+		}
+		return n
+	case map[string]interface{}:
+		var n int64
+		for _, elem := range vv {
+			n += estimateBytes(elem)
+		}
+		return n
+	}
+	return 8
+}
@@ -0,0 +1,132 @@
+package bigquerytest
+
+import (
+	"net/http"
+	"sort"
+
+	SDK "google.golang.org/api/bigquery/v2"
+)
+
+// listRoutines returns the routines without the definition as Routines.List does.
+func (s *Server) listRoutines(datasetID string) (*SDK.ListRoutinesResponse, error) {
+	ds, ok := s.datasets[datasetID]
+	if !ok {
+		return nil, newAPIError(http.StatusNotFound, "notFound", "Not found: Dataset %s:%s", s.projectID, datasetID)
+	}
+
+	ids := make([]string, 0, len(ds.routines))
+	for id := range ds.routines {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	list := &SDK.ListRoutinesResponse{}
+	for _, id := range ids {
+		meta := ds.routines[id]
+		list.Routines = append(list.Routines, &SDK.Routine{
+			CreationTime:     meta.CreationTime,
+			Etag:             meta.Etag,
+			Language:         meta.Language,
+			LastModifiedTime: meta.LastModifiedTime,
+			RoutineReference: meta.RoutineReference,
+			RoutineType:      meta.RoutineType,
+		})
+	}
+	return list, nil
+}
+
+func (s *Server) insertRoutine(r *http.Request, datasetID string) (*SDK.Routine, error) {
+	ds, ok := s.datasets[datasetID]
+	if !ok {
+		return nil, newAPIError(http.StatusNotFound, "notFound", "Not found: Dataset %s:%s", s.projectID, datasetID)
+	}
+
+	var meta SDK.Routine
+	if err := decodeBody(r, &meta); err != nil {
+		return nil, err
+	}
+	if meta.RoutineReference == nil || meta.RoutineReference.RoutineId == "" {
+		return nil, newAPIError(http.StatusBadRequest, "invalid", "Routine ID is required")
+	}
+	if err := validateRoutine(&meta); err != nil {
+		return nil, err
+	}
+
+	id := meta.RoutineReference.RoutineId
+	if _, ok := ds.routines[id]; ok {
+		return nil, newAPIError(http.StatusConflict, "duplicate", "Already Exists: Routine %s:%s.%s", s.projectID, datasetID, id)
+	}
+
+	meta.RoutineReference.ProjectId = s.projectID
+	meta.RoutineReference.DatasetId = datasetID
+	now := nowMillis()
+	meta.CreationTime = now
+	meta.LastModifiedTime = now
+	meta.Etag = s.nextEtag()
+	ds.routines[id] = &meta
+	return &meta, nil
+}
+
+func (s *Server) getRoutine(datasetID, routineID string) (*SDK.Routine, error) {
+	ds, ok := s.datasets[datasetID]
+	if !ok {
+		return nil, newAPIError(http.StatusNotFound, "notFound", "Not found: Dataset %s:%s", s.projectID, datasetID)
+	}
+	meta, ok := ds.routines[routineID]
+	if !ok {
+		return nil, newAPIError(http.StatusNotFound, "notFound", "Not found: Routine %s:%s.%s", s.projectID, datasetID, routineID)
+	}
+	return meta, nil
+}
+
+// updateRoutine replaces the entire routine as Routines.Update does.
+func (s *Server) updateRoutine(r *http.Request, datasetID, routineID string) (*SDK.Routine, error) {
+	current, err := s.getRoutine(datasetID, routineID)
+	if err != nil {
+		return nil, err
+	}
+
+	var meta SDK.Routine
+	if err := decodeBody(r, &meta); err != nil {
+		return nil, err
+	}
+	if ref := meta.RoutineReference; ref != nil && ref.RoutineId != "" && ref.RoutineId != routineID {
+		return nil, newAPIError(http.StatusBadRequest, "invalid", "Routine ID cannot be changed")
+	}
+	if err := validateRoutine(&meta); err != nil {
+		return nil, err
+	}
+
+	meta.RoutineReference = current.RoutineReference
+	meta.CreationTime = current.CreationTime
+	meta.LastModifiedTime = nowMillis()
+	meta.Etag = s.nextEtag()
+	s.datasets[datasetID].routines[routineID] = &meta
+	return &meta, nil
+}
+
+func (s *Server) deleteRoutine(datasetID, routineID string) error {
+	if _, err := s.getRoutine(datasetID, routineID); err != nil {
+		return err
+	}
+	delete(s.datasets[datasetID].routines, routineID)
+	return nil
+}
+
+// validateRoutine checks the required fields of the routine.
+func validateRoutine(meta *SDK.Routine) error {
+	switch {
+	case meta.RoutineType == "":
+		return newAPIError(http.StatusBadRequest, "invalid", "Routine type is required")
+	case meta.DefinitionBody == "":
+		return newAPIError(http.StatusBadRequest, "invalid", "Routine definition body is required")
+	case meta.Language == "JAVASCRIPT" && meta.RoutineType == "SCALAR_FUNCTION" && meta.ReturnType == nil:
+		return newAPIError(http.StatusBadRequest, "invalid", "Return type is required for JavaScript function")
+	}
+	for i, arg := range meta.Arguments {
+		if arg.Name == "" {
+			return newAPIError(http.StatusBadRequest, "invalid", "Argument name is required; index=[%d]", i)
+		}
+	}
+	return nil
+}
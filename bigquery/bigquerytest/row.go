@@ -0,0 +1,261 @@
+package bigquerytest
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/civil"
+	SDK "google.golang.org/api/bigquery/v2"
+)
+
+// layouts of TIMESTAMP string accepted by streaming inserts.
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999 MST",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02",
+}
+
+// newRowCells converts the row json of streaming inserts into the cells of Tabledata.List.
+func newRowCells(fields []*SDK.TableFieldSchema, row map[string]SDK.JsonValue, ignoreUnknown bool) ([]*SDK.TableCell, []*SDK.ErrorProto) {
+	var errs []*SDK.ErrorProto
+	if !ignoreUnknown {
+		for name := range row {
+			if findField(fields, name) < 0 {
+				errs = append(errs, &SDK.ErrorProto{
+					Reason:   "invalid",
+					Location: name,
+					Message:  "no such field: " + name,
+				})
+			}
+		}
+	}
+
+	cells := make([]*SDK.TableCell, len(fields))
+	for i, f := range fields {
+		v, err := newCellValue(f, lookupValue(row, f.Name))
+		if err != nil {
+			errs = append(errs, &SDK.ErrorProto{
+				Reason:   "invalid",
+				Location: f.Name,
+				Message:  err.Error(),
+			})
+			continue
+		}
+		cells[i] = &SDK.TableCell{V: v}
+	}
+	return cells, errs
+}
+
+// lookupValue returns the value of the column. Column names are case-insensitive.
+func lookupValue(row map[string]SDK.JsonValue, name string) interface{} {
+	if v, ok := row[name]; ok {
+		return v
+	}
+	for k, v := range row {
+		if strings.EqualFold(k, name) {
+			return v
+		}
+	}
+	return nil
+}
+
+func findField(fields []*SDK.TableFieldSchema, name string) int {
+	for i, f := range fields {
+		if strings.EqualFold(f.Name, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+func newCellValue(f *SDK.TableFieldSchema, v interface{}) (interface{}, error) {
+	if v == nil {
+		if f.Mode == "REQUIRED" {
+			return nil, fmt.Errorf("missing required field: %s", f.Name)
+		}
+		if f.Mode == "REPEATED" {
+			return []interface{}{}, nil
+		}
+		return nil, nil
+	}
+	if f.Mode != "REPEATED" {
+		return newSingleCellValue(f, v)
+	}
+
+	list, ok := v.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("array specified for non-repeated field or non-array for repeated field: %s", f.Name)
+	}
+	values := make([]interface{}, len(list))
+	for i, elem := range list {
+		if elem == nil {
+			return nil, fmt.Errorf("NULL in array is not allowed: %s", f.Name)
+		}
+		value, err := newSingleCellValue(f, elem)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = map[string]interface{}{"v": value}
+	}
+	return values, nil
+}
+
+func newSingleCellValue(f *SDK.TableFieldSchema, v interface{}) (interface{}, error) {
+	switch f.Type {
+	case "RECORD", "STRUCT":
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("this field is not a record: %s", f.Name)
+		}
+		row := make(map[string]SDK.JsonValue, len(m))
+		for k, val := range m {
+			row[k] = val
+		}
+		cells, errs := newRowCells(f.Fields, row, false)
+		if len(errs) != 0 {
+			return nil, fmt.Errorf("%s.%s", f.Name, errs[0].Message)
+		}
+		values := make([]interface{}, len(cells))
+		for i, c := range cells {
+			values[i] = map[string]interface{}{"v": c.V}
+		}
+		return map[string]interface{}{"f": values}, nil
+	case "JSON":
+		if s, ok := v.(string); ok && json.Valid([]byte(s)) {
+			return s, nil
+		}
+		b, err := json.Marshal(v)
+		return string(b), err
+	}
+
+	s, err := scalarString(v)
+	if err != nil {
+		return nil, fmt.Errorf("cannot convert value to %s: %s", f.Type, f.Name)
+	}
+	cell, err := normalizeScalar(f.Type, s)
+	if err != nil {
+		return nil, fmt.Errorf("cannot convert value to %s: %s; value=[%s]", f.Type, f.Name, s)
+	}
+	return cell, nil
+}
+
+func scalarString(v interface{}) (string, error) {
+	switch vv := v.(type) {
+	case string:
+		return vv, nil
+	case json.Number:
+		return vv.String(), nil
+	case float64:
+		return strconv.FormatFloat(vv, 'g', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(vv), nil
+	}
+	return "", fmt.Errorf("unsupported value: %v", v)
+}
+
+// normalizeScalar validates the value and returns the cell value of Tabledata.List format.
+func normalizeScalar(typ, s string) (string, error) {
+	switch typ {
+	case "INTEGER", "INT64":
+		if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return strconv.FormatInt(n, 10), nil
+		}
+		// integral float. (e.g. 1e3)
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil || f != float64(int64(f)) {
+			return "", fmt.Errorf("invalid integer: %s", s)
+		}
+		return strconv.FormatInt(int64(f), 10), nil
+	case "FLOAT", "FLOAT64":
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatFloat(f, 'g', -1, 64), nil
+	case "BOOLEAN", "BOOL":
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatBool(b), nil
+	case "NUMERIC", "BIGNUMERIC", "DECIMAL", "BIGDECIMAL":
+		r, ok := new(big.Rat).SetString(s)
+		if !ok {
+			return "", fmt.Errorf("invalid numeric: %s", s)
+		}
+		if (typ == "NUMERIC" || typ == "DECIMAL") && !hasNumericScale(r) {
+			return "", fmt.Errorf("invalid numeric: more than 9 decimal digits: %s", s)
+		}
+		return strings.TrimSuffix(strings.TrimRight(r.FloatString(38), "0"), "."), nil
+	case "BYTES":
+		if _, err := base64.StdEncoding.DecodeString(s); err != nil {
+			return "", err
+		}
+		return s, nil
+	case "TIMESTAMP":
+		t, err := parseTimestamp(s)
+		if err != nil {
+			return "", err
+		}
+		return timestampCell(t), nil
+	case "DATE":
+		d, err := civil.ParseDate(s)
+		if err != nil {
+			return "", err
+		}
+		return d.String(), nil
+	case "DATETIME":
+		dt, err := civil.ParseDateTime(strings.Replace(s, " ", "T", 1))
+		if err != nil {
+			return "", err
+		}
+		return dt.String(), nil
+	case "TIME":
+		t, err := civil.ParseTime(s)
+		if err != nil {
+			return "", err
+		}
+		return t.String(), nil
+	}
+	// STRING, GEOGRAPHY and unknown types.
+	return s, nil
+}
+
+// parseTimestamp parses TIMESTAMP value, which is seconds from epoch or time string.
+func parseTimestamp(s string) (time.Time, error) {
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		sec := int64(f)
+		return time.Unix(sec, int64((f-float64(sec))*1e9)).Round(time.Microsecond).UTC(), nil
+	}
+	for _, layout := range timestampLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid timestamp: %s", s)
+}
+
+// timestampCell returns TIMESTAMP value of Tabledata.List, which is seconds from epoch. (e.g. "1500000000.123456")
+func timestampCell(t time.Time) string {
+	micros := t.Unix()*1e6 + int64(t.Nanosecond()/1e3)
+	sign := ""
+	if micros < 0 {
+		sign = "-"
+		micros = -micros
+	}
+	return fmt.Sprintf("%s%d.%06d", sign, micros/1e6, micros%1e6)
+}
+
+// hasNumericScale reports whether the value fits in the scale of NUMERIC type, which is 9.
+func hasNumericScale(r *big.Rat) bool {
+	scaled := new(big.Rat).Mul(r, new(big.Rat).SetInt64(1e9))
+	return scaled.IsInt()
+}
@@ -0,0 +1,737 @@
+// Package bigquerytest provides the in-memory fake of BigQuery API for tests.
+//
+// The fake supports datasets, tables with schemas, routines, streaming inserts, query jobs of simple
+// SELECT statements and job states, and the wrapper client connected to it works without network.
+//
+//	srv := bigquerytest.NewServer("my-project")
+//	cli, err := srv.Client()
+//	...
+//	err = cli.DatasetAPI("my_dataset").TableAPI("my_table").InsertAll(rows)
+package bigquerytest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	SDK "google.golang.org/api/bigquery/v2"
+
+	"github.com/evalphobia/google-api-go-wrapper/bigquery"
+)
+
+const defaultLocation = "US"
+
+// Server is the in-memory fake of BigQuery REST API.
+// It implements http.Handler, so it can be used with httptest.Server as well as Client.
+type Server struct {
+	mu        sync.Mutex
+	projectID string
+	datasets  map[string]*dataset
+	jobs      map[string]*job
+	jobSeq    int
+	etagSeq   int
+
+	// JobPolls is the number of status checks until the job becomes DONE.
+	// Jobs are RUNNING until then. (default: 0, jobs are done immediately)
+	JobPolls int
+}
+
+type dataset struct {
+	meta     *SDK.Dataset
+	tables   map[string]*table
+	routines map[string]*SDK.Routine
+}
+
+type table struct {
+	meta      *SDK.Table
+	rows      []*SDK.TableRow
+	insertIDs map[string]struct{}
+}
+
+// NewServer returns initialized Server for the project.
+func NewServer(projectID string) *Server {
+	return &Server{
+		projectID: projectID,
+		datasets:  make(map[string]*dataset),
+		jobs:      make(map[string]*job),
+	}
+}
+
+// Client returns BigQuery client connected to the server without network.
+func (s *Server) Client() (*bigquery.BigQuery, error) {
+	cli := &http.Client{
+		Transport: handlerTransport{handler: s},
+	}
+	return bigquery.NewWithHTTPClient(cli, s.projectID)
+}
+
+// Rows returns the rows of the table decoded by the table schema.
+func (s *Server) Rows(datasetID, tableID string) ([]map[string]interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tbl, err := s.getTable(datasetID, tableID)
+	if err != nil {
+		return nil, err
+	}
+	return bigquery.DecodeRows(tbl.meta.Schema, tbl.rows)
+}
+
+// handlerTransport is http.RoundTripper which sends the request to the handler directly.
+type handlerTransport struct {
+	handler http.Handler
+}
+
+func (t handlerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rec := httptest.NewRecorder()
+	t.handler.ServeHTTP(rec, req)
+	return rec.Result(), nil
+}
+
+// ServeHTTP handles the request of BigQuery REST API.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	resp, err := s.route(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if resp == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// route dispatches the request by the path after "projects/{projectId}/".
+func (s *Server) route(r *http.Request) (interface{}, error) {
+	path := r.URL.Path
+	idx := strings.Index(path, "/projects/")
+	if idx < 0 || strings.HasPrefix(path, "/upload/") {
+		return nil, newAPIError(http.StatusNotImplemented, "notImplemented", "the operation is not supported by the fake; path=[%s]", path)
+	}
+
+	parts := strings.Split(strings.Trim(path[idx+len("/projects/"):], "/"), "/")
+	if parts[0] != s.projectID {
+		return nil, newAPIError(http.StatusNotFound, "notFound", "Not found: Project %s", parts[0])
+	}
+
+	method := r.Method
+	switch p := parts[1:]; {
+	case len(p) == 1 && p[0] == "datasets":
+		switch method {
+		case http.MethodGet:
+			return s.listDatasets(), nil
+		case http.MethodPost:
+			return s.insertDataset(r)
+		}
+	case len(p) == 2 && p[0] == "datasets":
+		switch method {
+		case http.MethodGet:
+			return s.getDataset(p[1])
+		case http.MethodPatch, http.MethodPut:
+			return s.patchDataset(r, p[1], method == http.MethodPut)
+		case http.MethodDelete:
+			return nil, s.deleteDataset(p[1], r.URL.Query().Get("deleteContents") == "true")
+		}
+	case len(p) == 3 && p[0] == "datasets" && p[2] == "tables":
+		switch method {
+		case http.MethodGet:
+			return s.listTables(p[1])
+		case http.MethodPost:
+			return s.insertTable(r, p[1])
+		}
+	case len(p) == 4 && p[0] == "datasets" && p[2] == "tables":
+		switch method {
+		case http.MethodGet:
+			return s.getTableMeta(p[1], p[3])
+		case http.MethodPatch, http.MethodPut:
+			return s.patchTable(r, p[1], p[3], method == http.MethodPut)
+		case http.MethodDelete:
+			return nil, s.deleteTable(p[1], p[3])
+		}
+	case len(p) == 5 && p[0] == "datasets" && p[2] == "tables" && p[4] == "insertAll" && method == http.MethodPost:
+		return s.insertAll(r, p[1], p[3])
+	case len(p) == 5 && p[0] == "datasets" && p[2] == "tables" && p[4] == "data" && method == http.MethodGet:
+		return s.listTableData(r, p[1], p[3])
+	case len(p) == 3 && p[0] == "datasets" && p[2] == "routines":
+		switch method {
+		case http.MethodGet:
+			return s.listRoutines(p[1])
+		case http.MethodPost:
+			return s.insertRoutine(r, p[1])
+		}
+	case len(p) == 4 && p[0] == "datasets" && p[2] == "routines":
+		switch method {
+		case http.MethodGet:
+			return s.getRoutine(p[1], p[3])
+		case http.MethodPut:
+			return s.updateRoutine(r, p[1], p[3])
+		case http.MethodDelete:
+			return nil, s.deleteRoutine(p[1], p[3])
+		}
+	case len(p) == 1 && p[0] == "jobs":
+		switch method {
+		case http.MethodGet:
+			return s.listJobs(), nil
+		case http.MethodPost:
+			return s.insertJob(r)
+		}
+	case len(p) == 2 && p[0] == "jobs" && method == http.MethodGet:
+		return s.getJob(p[1])
+	case len(p) == 3 && p[0] == "jobs" && p[2] == "cancel" && method == http.MethodPost:
+		return s.cancelJob(p[1])
+	case len(p) == 1 && p[0] == "queries" && method == http.MethodPost:
+		return s.query(r)
+	case len(p) == 2 && p[0] == "queries" && method == http.MethodGet:
+		return s.getQueryResults(r, p[1])
+	}
+	return nil, newAPIError(http.StatusNotImplemented, "notImplemented", "the operation is not supported by the fake; method=[%s] path=[%s]", method, path)
+}
+
+// ==========
+// Dataset
+// ==========
+
+func (s *Server) listDatasets() *SDK.DatasetList {
+	ids := make([]string, 0, len(s.datasets))
+	for id := range s.datasets {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	list := &SDK.DatasetList{
+		Kind: "bigquery#datasetList",
+	}
+	for _, id := range ids {
+		meta := s.datasets[id].meta
+		list.Datasets = append(list.Datasets, &SDK.DatasetListDatasets{
+			DatasetReference: meta.DatasetReference,
+			FriendlyName:     meta.FriendlyName,
+			Id:               meta.Id,
+			Kind:             "bigquery#dataset",
+			Labels:           meta.Labels,
+			Location:         meta.Location,
+		})
+	}
+	return list
+}
+
+func (s *Server) insertDataset(r *http.Request) (*SDK.Dataset, error) {
+	var meta SDK.Dataset
+	if err := decodeBody(r, &meta); err != nil {
+		return nil, err
+	}
+	if meta.DatasetReference == nil || meta.DatasetReference.DatasetId == "" {
+		return nil, newAPIError(http.StatusBadRequest, "invalid", "Dataset ID is required")
+	}
+
+	id := meta.DatasetReference.DatasetId
+	if _, ok := s.datasets[id]; ok {
+		return nil, newAPIError(http.StatusConflict, "duplicate", "Already Exists: Dataset %s:%s", s.projectID, id)
+	}
+
+	meta.DatasetReference.ProjectId = s.projectID
+	meta.Id = s.projectID + ":" + id
+	meta.Kind = "bigquery#dataset"
+	if meta.Location == "" {
+		meta.Location = defaultLocation
+	}
+	now := nowMillis()
+	meta.CreationTime = now
+	meta.LastModifiedTime = now
+	meta.Etag = s.nextEtag()
+	s.datasets[id] = &dataset{
+		meta:     &meta,
+		tables:   make(map[string]*table),
+		routines: make(map[string]*SDK.Routine),
+	}
+	return &meta, nil
+}
+
+func (s *Server) getDataset(datasetID string) (*SDK.Dataset, error) {
+	ds, ok := s.datasets[datasetID]
+	if !ok {
+		return nil, newAPIError(http.StatusNotFound, "notFound", "Not found: Dataset %s:%s", s.projectID, datasetID)
+	}
+	return ds.meta, nil
+}
+
+func (s *Server) patchDataset(r *http.Request, datasetID string, replace bool) (*SDK.Dataset, error) {
+	ds, ok := s.datasets[datasetID]
+	if !ok {
+		return nil, newAPIError(http.StatusNotFound, "notFound", "Not found: Dataset %s:%s", s.projectID, datasetID)
+	}
+	if etag := r.Header.Get("If-Match"); etag != "" && etag != ds.meta.Etag {
+		return nil, newAPIError(http.StatusPreconditionFailed, "conditionNotMet", "Precondition check failed.")
+	}
+
+	var meta SDK.Dataset
+	if err := mergeBody(r, ds.meta, &meta, replace); err != nil {
+		return nil, err
+	}
+	meta.DatasetReference = ds.meta.DatasetReference
+	meta.Id = ds.meta.Id
+	meta.Kind = ds.meta.Kind
+	meta.CreationTime = ds.meta.CreationTime
+	meta.LastModifiedTime = nowMillis()
+	meta.Etag = s.nextEtag()
+	ds.meta = &meta
+	return &meta, nil
+}
+
+func (s *Server) deleteDataset(datasetID string, deleteContents bool) error {
+	ds, ok := s.datasets[datasetID]
+	if !ok {
+		return newAPIError(http.StatusNotFound, "notFound", "Not found: Dataset %s:%s", s.projectID, datasetID)
+	}
+	if len(ds.tables)+len(ds.routines) != 0 && !deleteContents {
+		return newAPIError(http.StatusBadRequest, "resourceInUse", "Dataset %s:%s is still in use", s.projectID, datasetID)
+	}
+	delete(s.datasets, datasetID)
+	return nil
+}
+
+// ==========
+// Table
+// ==========
+
+func (s *Server) getTable(datasetID, tableID string) (*table, error) {
+	ds, ok := s.datasets[datasetID]
+	if !ok {
+		return nil, newAPIError(http.StatusNotFound, "notFound", "Not found: Dataset %s:%s", s.projectID, datasetID)
+	}
+	tbl, ok := ds.tables[tableID]
+	if !ok {
+		return nil, newAPIError(http.StatusNotFound, "notFound", "Not found: Table %s:%s.%s", s.projectID, datasetID, tableID)
+	}
+	return tbl, nil
+}
+
+func (s *Server) listTables(datasetID string) (*SDK.TableList, error) {
+	ds, ok := s.datasets[datasetID]
+	if !ok {
+		return nil, newAPIError(http.StatusNotFound, "notFound", "Not found: Dataset %s:%s", s.projectID, datasetID)
+	}
+
+	ids := make([]string, 0, len(ds.tables))
+	for id := range ds.tables {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	list := &SDK.TableList{
+		Kind:       "bigquery#tableList",
+		TotalItems: int64(len(ids)),
+	}
+	for _, id := range ids {
+		meta := ds.tables[id].meta
+		list.Tables = append(list.Tables, &SDK.TableListTables{
+			CreationTime:     meta.CreationTime,
+			ExpirationTime:   meta.ExpirationTime,
+			FriendlyName:     meta.FriendlyName,
+			Id:               meta.Id,
+			Kind:             "bigquery#table",
+			Labels:           meta.Labels,
+			TableReference:   meta.TableReference,
+			TimePartitioning: meta.TimePartitioning,
+			Type:             meta.Type,
+		})
+	}
+	return list, nil
+}
+
+func (s *Server) insertTable(r *http.Request, datasetID string) (*SDK.Table, error) {
+	ds, ok := s.datasets[datasetID]
+	if !ok {
+		return nil, newAPIError(http.StatusNotFound, "notFound", "Not found: Dataset %s:%s", s.projectID, datasetID)
+	}
+
+	var meta SDK.Table
+	if err := decodeBody(r, &meta); err != nil {
+		return nil, err
+	}
+	if meta.TableReference == nil || meta.TableReference.TableId == "" {
+		return nil, newAPIError(http.StatusBadRequest, "invalid", "Table ID is required")
+	}
+
+	id := meta.TableReference.TableId
+	if _, ok := ds.tables[id]; ok {
+		return nil, newAPIError(http.StatusConflict, "duplicate", "Already Exists: Table %s:%s.%s", s.projectID, datasetID, id)
+	}
+
+	meta.TableReference.ProjectId = s.projectID
+	meta.TableReference.DatasetId = datasetID
+	meta.Id = fmt.Sprintf("%s:%s.%s", s.projectID, datasetID, id)
+	meta.Kind = "bigquery#table"
+	meta.Location = ds.meta.Location
+	switch {
+	case meta.View != nil:
+		meta.Type = "VIEW"
+	case meta.MaterializedView != nil:
+		meta.Type = "MATERIALIZED_VIEW"
+	default:
+		meta.Type = "TABLE"
+	}
+	if meta.Schema == nil {
+		meta.Schema = &SDK.TableSchema{}
+	}
+	normalizeFields(meta.Schema.Fields)
+	now := nowMillis()
+	meta.CreationTime = now
+	meta.LastModifiedTime = uint64(now)
+	meta.Etag = s.nextEtag()
+	ds.tables[id] = &table{
+		meta:      &meta,
+		insertIDs: make(map[string]struct{}),
+	}
+	return &meta, nil
+}
+
+func (s *Server) getTableMeta(datasetID, tableID string) (*SDK.Table, error) {
+	tbl, err := s.getTable(datasetID, tableID)
+	if err != nil {
+		return nil, err
+	}
+	meta := *tbl.meta
+	meta.NumRows = uint64(len(tbl.rows))
+	return &meta, nil
+}
+
+func (s *Server) patchTable(r *http.Request, datasetID, tableID string, replace bool) (*SDK.Table, error) {
+	tbl, err := s.getTable(datasetID, tableID)
+	if err != nil {
+		return nil, err
+	}
+	if etag := r.Header.Get("If-Match"); etag != "" && etag != tbl.meta.Etag {
+		return nil, newAPIError(http.StatusPreconditionFailed, "conditionNotMet", "Precondition check failed.")
+	}
+
+	var meta SDK.Table
+	if err := mergeBody(r, tbl.meta, &meta, replace); err != nil {
+		return nil, err
+	}
+	if meta.Schema != nil {
+		normalizeFields(meta.Schema.Fields)
+	}
+	if err := checkSchemaUpdate(tbl.meta.Schema, meta.Schema); err != nil {
+		return nil, err
+	}
+	meta.TableReference = tbl.meta.TableReference
+	meta.Id = tbl.meta.Id
+	meta.Kind = tbl.meta.Kind
+	meta.Type = tbl.meta.Type
+	meta.CreationTime = tbl.meta.CreationTime
+	meta.LastModifiedTime = uint64(nowMillis())
+	meta.Etag = s.nextEtag()
+	tbl.meta = &meta
+
+	// existing rows get NULL for the added columns.
+	for _, row := range tbl.rows {
+		for len(row.F) < len(meta.Schema.Fields) {
+			row.F = append(row.F, &SDK.TableCell{})
+		}
+	}
+	return &meta, nil
+}
+
+// checkSchemaUpdate checks the schema update has only additive changes as BigQuery does.
+func checkSchemaUpdate(current, updated *SDK.TableSchema) error {
+	if current == nil || len(current.Fields) == 0 {
+		return nil
+	}
+	if updated == nil || len(updated.Fields) < len(current.Fields) {
+		return newAPIError(http.StatusBadRequest, "invalid", "Provided Schema does not match Table. Cannot delete columns.")
+	}
+	for i, f := range current.Fields {
+		u := updated.Fields[i]
+		if !strings.EqualFold(f.Name, u.Name) || f.Type != u.Type {
+			return newAPIError(http.StatusBadRequest, "invalid", "Provided Schema does not match Table. Field %s has changed.", f.Name)
+		}
+	}
+	for _, u := range updated.Fields[len(current.Fields):] {
+		if u.Mode == "REQUIRED" {
+			return newAPIError(http.StatusBadRequest, "invalid", "Provided Schema does not match Table. Cannot add required field %s.", u.Name)
+		}
+	}
+	return nil
+}
+
+// normalizeFields upper-cases the types and modes of the fields, because they are case-insensitive in the request.
+func normalizeFields(fields []*SDK.TableFieldSchema) {
+	for _, f := range fields {
+		f.Type = strings.ToUpper(f.Type)
+		f.Mode = strings.ToUpper(f.Mode)
+		normalizeFields(f.Fields)
+	}
+}
+
+func (s *Server) deleteTable(datasetID, tableID string) error {
+	if _, err := s.getTable(datasetID, tableID); err != nil {
+		return err
+	}
+	delete(s.datasets[datasetID].tables, tableID)
+	return nil
+}
+
+// ==========
+// Tabledata
+// ==========
+
+func (s *Server) insertAll(r *http.Request, datasetID, tableID string) (*SDK.TableDataInsertAllResponse, error) {
+	tbl, err := s.getTable(datasetID, tableID)
+	if err != nil {
+		return nil, err
+	}
+
+	var req SDK.TableDataInsertAllRequest
+	if err := decodeBody(r, &req); err != nil {
+		return nil, err
+	}
+
+	resp := &SDK.TableDataInsertAllResponse{
+		Kind: "bigquery#tableDataInsertAllResponse",
+	}
+	rows := make([]*SDK.TableRow, len(req.Rows))
+	for i, row := range req.Rows {
+		cells, errs := newRowCells(tbl.meta.Schema.Fields, row.Json, req.IgnoreUnknownValues)
+		if len(errs) != 0 {
+			resp.InsertErrors = append(resp.InsertErrors, &SDK.TableDataInsertAllResponseInsertErrors{
+				Index:  int64(i),
+				Errors: errs,
+			})
+			continue
+		}
+		rows[i] = &SDK.TableRow{F: cells}
+	}
+
+	if len(resp.InsertErrors) != 0 && !req.SkipInvalidRows {
+		// no rows are inserted when any row is invalid.
+		failed := make(map[int64]bool, len(resp.InsertErrors))
+		for _, e := range resp.InsertErrors {
+			failed[e.Index] = true
+		}
+		for i := range req.Rows {
+			if failed[int64(i)] {
+				continue
+			}
+			resp.InsertErrors = append(resp.InsertErrors, &SDK.TableDataInsertAllResponseInsertErrors{
+				Index:  int64(i),
+				Errors: []*SDK.ErrorProto{{Reason: "stopped"}},
+			})
+		}
+		sort.Slice(resp.InsertErrors, func(i, j int) bool {
+			return resp.InsertErrors[i].Index < resp.InsertErrors[j].Index
+		})
+		return resp, nil
+	}
+
+	for i, row := range rows {
+		if row == nil {
+			continue
+		}
+		if id := req.Rows[i].InsertId; id != "" {
+			if _, ok := tbl.insertIDs[id]; ok {
+				continue // de-duplicated by insert ID.
+			}
+			tbl.insertIDs[id] = struct{}{}
+		}
+		tbl.rows = append(tbl.rows, row)
+	}
+	return resp, nil
+}
+
+func (s *Server) listTableData(r *http.Request, datasetID, tableID string) (*SDK.TableDataList, error) {
+	tbl, err := s.getTable(datasetID, tableID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, token, err := pageRows(r, tbl.rows)
+	if err != nil {
+		return nil, err
+	}
+	return &SDK.TableDataList{
+		Kind:      "bigquery#tableDataList",
+		PageToken: token,
+		Rows:      rows,
+		TotalRows: int64(len(tbl.rows)),
+	}, nil
+}
+
+// pageRows returns the rows of the page by pageToken, startIndex and maxResults parameters.
+// The page token is the offset of the next page.
+func pageRows(r *http.Request, rows []*SDK.TableRow) ([]*SDK.TableRow, string, error) {
+	q := r.URL.Query()
+	start := 0
+	if v := q.Get("pageToken"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return nil, "", newAPIError(http.StatusBadRequest, "invalid", "Invalid page token: %s", v)
+		}
+		start = n
+	} else if v := q.Get("startIndex"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return nil, "", newAPIError(http.StatusBadRequest, "invalid", "Invalid start index: %s", v)
+		}
+		start = n
+	}
+	if start > len(rows) {
+		start = len(rows)
+	}
+
+	end := len(rows)
+	if v := q.Get("maxResults"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return nil, "", newAPIError(http.StatusBadRequest, "invalid", "Invalid max results: %s", v)
+		}
+		if n > 0 && start+n < end {
+			end = start + n
+		}
+	}
+
+	token := ""
+	if end < len(rows) {
+		token = strconv.Itoa(end)
+	}
+	return rows[start:end], token, nil
+}
+
+// ==========
+// Utility
+// ==========
+
+func (s *Server) nextEtag() string {
+	s.etagSeq++
+	return strconv.Itoa(s.etagSeq)
+}
+
+func nowMillis() int64 {
+	return time.Now().UnixNano() / int64(time.Millisecond)
+}
+
+func decodeBody(r *http.Request, v interface{}) error {
+	dec := json.NewDecoder(r.Body)
+	// keep the precision of INTEGER values in rows.
+	dec.UseNumber()
+	if err := dec.Decode(v); err != nil {
+		return newAPIError(http.StatusBadRequest, "invalid", "Invalid JSON payload received. %s", err.Error())
+	}
+	return nil
+}
+
+// mergeBody applies the request body to the current resource and stores the result into v.
+// Patch replaces only the fields in the request body, and update replaces the entire resource.
+func mergeBody(r *http.Request, current, v interface{}, replace bool) error {
+	var body map[string]json.RawMessage
+	if err := decodeBody(r, &body); err != nil {
+		return err
+	}
+
+	merged := make(map[string]json.RawMessage)
+	if !replace {
+		b, err := json.Marshal(current)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(b, &merged); err != nil {
+			return err
+		}
+	}
+	for k, val := range body {
+		if k == "labels" && !replace {
+			// labels are added or updated, and removed by null value.
+			labels, err := mergeLabels(merged[k], val)
+			if err != nil {
+				return err
+			}
+			val = labels
+		}
+		merged[k] = val
+	}
+
+	b, err := json.Marshal(merged)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+func mergeLabels(current, patch json.RawMessage) (json.RawMessage, error) {
+	labels := make(map[string]*string)
+	if len(current) != 0 {
+		if err := json.Unmarshal(current, &labels); err != nil {
+			return nil, err
+		}
+	}
+	var diff map[string]*string
+	if err := json.Unmarshal(patch, &diff); err != nil {
+		return nil, newAPIError(http.StatusBadRequest, "invalid", "Invalid labels. %s", err.Error())
+	}
+	for k, v := range diff {
+		if v == nil {
+			delete(labels, k)
+			continue
+		}
+		labels[k] = v
+	}
+	return json.Marshal(labels)
+}
+
+// apiError is the error response of BigQuery API.
+type apiError struct {
+	Code    int
+	Reason  string
+	Message string
+}
+
+func newAPIError(code int, reason, format string, v ...interface{}) *apiError {
+	return &apiError{
+		Code:    code,
+		Reason:  reason,
+		Message: fmt.Sprintf(format, v...),
+	}
+}
+
+func (e *apiError) Error() string {
+	return e.Message
+}
+
+func (e *apiError) toErrorProto() *SDK.ErrorProto {
+	return &SDK.ErrorProto{
+		Reason:  e.Reason,
+		Message: e.Message,
+	}
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	apiErr, ok := err.(*apiError)
+	if !ok {
+		apiErr = newAPIError(http.StatusInternalServerError, "internalError", "%s", err.Error())
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(apiErr.Code)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"error": map[string]interface{}{
+			"code":    apiErr.Code,
+			"message": apiErr.Message,
+			"errors": []map[string]string{{
+				"reason":  apiErr.Reason,
+				"message": apiErr.Message,
+			}},
+		},
+	})
+}
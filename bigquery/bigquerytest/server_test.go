@@ -0,0 +1,369 @@
+package bigquerytest
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"reflect"
+	"testing"
+
+	SDK "google.golang.org/api/bigquery/v2"
+
+	"github.com/evalphobia/google-api-go-wrapper/bigquery"
+)
+
+type serverTestRow struct {
+	ID   string `bigquery:"-"`
+	Name string `bigquery:"name"`
+	Age  int64  `bigquery:"age"`
+}
+
+func (r serverTestRow) InsertID() string {
+	return r.ID
+}
+
+func newTestTable(t *testing.T) (*Server, *bigquery.TableAPI) {
+	srv := NewServer("project")
+	cli, err := srv.Client()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	_, err = cli.CreateDataset(&SDK.Dataset{
+		DatasetReference: &SDK.DatasetReference{ProjectId: "project", DatasetId: "ds"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	tbl := cli.DatasetAPI("ds").TableAPI("tbl")
+	if err := tbl.Create(serverTestRow{}); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	return srv, tbl
+}
+
+func TestServerInsertAll(t *testing.T) {
+	tests := []struct {
+		name        string
+		batches     []interface{}
+		opt         bigquery.InsertAllOption
+		wantNames   []string
+		wantReasons map[int64]string // reason of the row error in the last batch.
+	}{
+		{
+			name: "insert",
+			batches: []interface{}{
+				[]serverTestRow{{Name: "a", Age: 1}, {Name: "b", Age: 2}},
+			},
+			wantNames: []string{"a", "b"},
+		},
+		{
+			name: "de-duplicate by insert ID",
+			batches: []interface{}{
+				[]serverTestRow{{ID: "1", Name: "a"}, {ID: "2", Name: "b"}},
+				[]serverTestRow{{ID: "2", Name: "b"}, {ID: "3", Name: "c"}, {Name: "d"}, {Name: "d"}},
+			},
+			wantNames: []string{"a", "b", "c", "d", "d"},
+		},
+		{
+			name: "invalid row stops other rows",
+			batches: []interface{}{
+				[]map[string]interface{}{{"name": "a", "age": 1}, {"name": "b", "age": "x"}, {"name": "c", "age": 3}},
+			},
+			wantReasons: map[int64]string{0: "stopped", 1: "invalid", 2: "stopped"},
+		},
+		{
+			name: "skip invalid rows",
+			batches: []interface{}{
+				[]map[string]interface{}{{"name": "a", "age": 1}, {"name": "b", "age": 2, "unknown": 1}, {"name": "c", "age": 3}},
+			},
+			opt:         bigquery.InsertAllOption{SkipInvalidRows: true},
+			wantNames:   []string{"a", "c"},
+			wantReasons: map[int64]string{1: "invalid"},
+		},
+		{
+			name: "missing required field",
+			batches: []interface{}{
+				[]map[string]interface{}{{"name": "a"}},
+			},
+			wantReasons: map[int64]string{0: "invalid"},
+		},
+		{
+			name: "ignore unknown values",
+			batches: []interface{}{
+				[]map[string]interface{}{{"name": "a", "age": 1, "unknown": 1}},
+			},
+			opt:       bigquery.InsertAllOption{IgnoreUnknownValues: true},
+			wantNames: []string{"a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, tbl := newTestTable(t)
+
+			var err error
+			for _, batch := range tt.batches {
+				err = tbl.InsertAllWithOption(context.Background(), batch, tt.opt)
+			}
+
+			if len(tt.wantReasons) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err.Error())
+				}
+			} else {
+				insertErr, ok := err.(*bigquery.InsertAllError)
+				if !ok {
+					t.Fatalf("expected *bigquery.InsertAllError, got %v", err)
+				}
+				reasons := make(map[int64]string)
+				for _, rowErr := range insertErr.RowErrors {
+					reasons[rowErr.Index] = rowErr.Reason
+				}
+				if !reflect.DeepEqual(reasons, tt.wantReasons) {
+					t.Errorf("reasons: got %v, want %v", reasons, tt.wantReasons)
+				}
+			}
+
+			rows, err := srv.Rows("ds", "tbl")
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+			var names []string
+			for _, row := range rows {
+				names = append(names, row["name"].(string))
+			}
+			if !reflect.DeepEqual(names, tt.wantNames) {
+				t.Errorf("rows: got %v, want %v", names, tt.wantNames)
+			}
+		})
+	}
+}
+
+func TestServerInsertAllDecimal(t *testing.T) {
+	type decimalRow struct {
+		Value *big.Rat `bigquery:"value"`
+	}
+
+	srv, _ := newTestTable(t)
+	cli, err := srv.Client()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	tbl := cli.DatasetAPI("ds").TableAPI("decimal")
+	if err := tbl.Create(decimalRow{}); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	// non-terminating fraction is written with the scale of BIGNUMERIC.
+	if err := tbl.InsertAll([]decimalRow{{Value: big.NewRat(1, 3)}}); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	rows, err := srv.Rows("ds", "decimal")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	want := "0.33333333333333333333333333333333333333"
+	if len(rows) != 1 {
+		t.Fatalf("got %d rows, want 1", len(rows))
+	}
+	if got := rows[0]["value"].(*big.Rat).FloatString(38); got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestServerQuery(t *testing.T) {
+	srv, tbl := newTestTable(t)
+	err := tbl.InsertAll([]serverTestRow{
+		{Name: "a", Age: 10},
+		{Name: "b", Age: 20},
+		{Name: "c", Age: 30},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	cli, err := srv.Client()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	tests := []struct {
+		name      string
+		opt       bigquery.QueryOption
+		wantNames []string
+		wantErr   bool
+	}{
+		{"all", bigquery.QueryOption{SQL: "SELECT * FROM ds.tbl"}, []string{"a", "b", "c"}, false},
+		{"where and limit", bigquery.QueryOption{SQL: "SELECT name FROM ds.tbl WHERE age >= 20 LIMIT 1"}, []string{"b"}, false},
+		{"parameter", bigquery.QueryOption{
+			SQL:        "SELECT name FROM ds.tbl WHERE name IN (@x, @y)",
+			Parameters: []bigquery.QueryParameter{{Name: "x", Value: "a"}, {Name: "y", Value: "c"}},
+		}, []string{"a", "c"}, false},
+		{"default dataset", bigquery.QueryOption{SQL: "SELECT * FROM tbl WHERE age < 0", ProjectID: "project", DatasetID: "ds"}, nil, false},
+		{"unknown column", bigquery.QueryOption{SQL: "SELECT * FROM ds.tbl WHERE unknown = 1"}, nil, true},
+		{"unknown table", bigquery.QueryOption{SQL: "SELECT * FROM ds.unknown"}, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := cli.Query(tt.opt)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got %#v", resp)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+
+			rows, err := resp.ToMap()
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+			var names []string
+			for _, row := range rows {
+				names = append(names, row["name"].(string))
+			}
+			if !reflect.DeepEqual(names, tt.wantNames) {
+				t.Errorf("got %v, want %v", names, tt.wantNames)
+			}
+		})
+	}
+}
+
+func TestServerEnsureRoutine(t *testing.T) {
+	srv, _ := newTestTable(t)
+	cli, err := srv.Client()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	ds := cli.DatasetAPI("ds")
+	opt := bigquery.RoutineOption{
+		Arguments:  []bigquery.RoutineArgument{{Name: "x", Type: "INT64"}},
+		ReturnType: "INT64",
+	}
+
+	// the steps run in order on the same routine.
+	steps := []struct {
+		name        string
+		body        string
+		opt         bigquery.RoutineOption
+		wantChanged bool
+	}{
+		{"create", "x + 1", opt, true},
+		{"no-op", "x + 1", opt, false},
+		{"no-op with whitespace and alias", " x + 1\n", bigquery.RoutineOption{
+			Arguments:  []bigquery.RoutineArgument{{Name: "x", Type: "integer"}},
+			ReturnType: "INT64",
+		}, false},
+		{"update body", "x + 2", opt, true},
+		{"update arguments", "x + 2", bigquery.RoutineOption{
+			Arguments:  []bigquery.RoutineArgument{{Name: "x", Type: "INT64"}, {Name: "y", Type: "INT64"}},
+			ReturnType: "INT64",
+		}, true},
+	}
+
+	for _, step := range steps {
+		changed, err := ds.EnsureRoutine("fn", step.body, step.opt)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", step.name, err.Error())
+		}
+		if changed != step.wantChanged {
+			t.Errorf("%s: got %v, want %v", step.name, changed, step.wantChanged)
+		}
+	}
+
+	routine, err := ds.RoutineAPI("fn").Get()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if routine.DefinitionBody != "x + 2" || len(routine.Arguments) != 2 {
+		t.Errorf("got body=[%s] arguments=%d", routine.DefinitionBody, len(routine.Arguments))
+	}
+}
+
+func TestServerEnsureTable(t *testing.T) {
+	type rowV1 struct {
+		Name string `bigquery:"name"`
+	}
+	type rowV2 struct {
+		Name string `bigquery:"name"`
+		Age  *int64 `bigquery:"age"`
+	}
+	type rowV3 struct {
+		ID   int64  `bigquery:"id"`
+		Name int64  `bigquery:"name"`
+		Age  *int64 `bigquery:"age"`
+	}
+
+	srv, _ := newTestTable(t)
+	cli, err := srv.Client()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	tbl := cli.DatasetAPI("ds").TableAPI("ensure")
+	sync := bigquery.EnsureTableOption{SyncSchema: true}
+
+	if ok, err := tbl.IsExist(); err != nil || ok {
+		t.Fatalf("IsExist: got %v, %v, want false", ok, err)
+	}
+
+	// the steps run in order on the same table.
+	steps := []struct {
+		name             string
+		schema           interface{}
+		opt              bigquery.EnsureTableOption
+		wantChanged      bool
+		wantIncompatible []string
+		wantColumns      []string
+	}{
+		{"create", rowV1{}, sync, true, nil, []string{"name"}},
+		{"no-op", rowV1{}, sync, false, nil, []string{"name"}},
+		{"additive change without SyncSchema", rowV2{}, bigquery.EnsureTableOption{}, false, nil, []string{"name"}},
+		{"additive change", rowV2{}, sync, true, nil, []string{"name", "age"}},
+		{"incompatible change", rowV3{}, sync, true, []string{"name"}, []string{"name", "age", "id"}},
+	}
+
+	for _, step := range steps {
+		changed, err := tbl.Ensure(step.schema, step.opt)
+		if changed != step.wantChanged {
+			t.Errorf("%s: changed: got %v, want %v", step.name, changed, step.wantChanged)
+		}
+
+		if len(step.wantIncompatible) == 0 {
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %s", step.name, err.Error())
+			}
+		} else {
+			var schemaErr *bigquery.IncompatibleSchemaError
+			if !errors.As(err, &schemaErr) {
+				t.Fatalf("%s: expected *bigquery.IncompatibleSchemaError, got %v", step.name, err)
+			}
+			var names []string
+			for _, c := range schemaErr.Diff.Incompatible {
+				names = append(names, c.Name)
+			}
+			if !reflect.DeepEqual(names, step.wantIncompatible) {
+				t.Errorf("%s: incompatible: got %v, want %v", step.name, names, step.wantIncompatible)
+			}
+		}
+
+		meta, err := tbl.Get()
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", step.name, err.Error())
+		}
+		var columns []string
+		for _, f := range meta.Schema.Fields {
+			columns = append(columns, f.Name)
+		}
+		if !reflect.DeepEqual(columns, step.wantColumns) {
+			t.Errorf("%s: columns: got %v, want %v", step.name, columns, step.wantColumns)
+		}
+	}
+
+	if ok, err := tbl.IsExist(); err != nil || !ok {
+		t.Errorf("IsExist: got %v, %v, want true", ok, err)
+	}
+}
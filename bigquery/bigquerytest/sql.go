@@ -0,0 +1,851 @@
+package bigquerytest
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"cloud.google.com/go/civil"
+	SDK "google.golang.org/api/bigquery/v2"
+)
+
+// selectStmt is the parsed query of the subset of SELECT statement:
+//
+//	SELECT * | column [, ...] FROM table [WHERE condition] [LIMIT n]
+//
+// The condition supports comparison operators (=, !=, <>, <, <=, >, >=), IS [NOT] NULL,
+// [NOT] IN (...), AND, OR, NOT and parentheses.
+// The operands are columns, literals (string, number, TRUE, FALSE, NULL, TIMESTAMP '...', DATE '...')
+// and query parameters. (@name or ?)
+type selectStmt struct {
+	columns []string // nil means all of the columns.
+	table   *SDK.TableReference
+	where   expr
+	limit   int64 // negative means no limit.
+}
+
+type queryContext struct {
+	projectID      string
+	defaultDataset *SDK.DatasetReference
+	params         []*SDK.QueryParameter
+}
+
+func parseQuery(sql string, qc queryContext) (*selectStmt, error) {
+	tokens, err := tokenize(sql)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{
+		tokens: tokens,
+		qc:     qc,
+	}
+	return p.parseSelect()
+}
+
+// ==========
+// Tokenizer
+// ==========
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenQuotedIdent
+	tokenString
+	tokenNumber
+	tokenSymbol
+	tokenNamedParam
+	tokenPositionalParam
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+// is checks if the token is the keyword or symbol. keywords are case-insensitive.
+func (t token) is(s string) bool {
+	return (t.kind == tokenIdent || t.kind == tokenSymbol) && strings.EqualFold(t.text, s)
+}
+
+func tokenize(sql string) ([]token, error) {
+	var tokens []token
+	rs := []rune(sql)
+	for i := 0; i < len(rs); {
+		r := rs[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == ';' && strings.TrimSpace(string(rs[i+1:])) == "":
+			i = len(rs)
+		case r == '-' && i+1 < len(rs) && rs[i+1] == '-':
+			// comment until the end of the line.
+			for i < len(rs) && rs[i] != '\n' {
+				i++
+			}
+		case r == '\'' || r == '"':
+			s, n, err := readQuoted(rs[i:], r)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokenString, text: s})
+			i += n
+		case r == '`' || r == '[':
+			end := '`'
+			if r == '[' {
+				end = ']'
+			}
+			j := i + 1
+			for j < len(rs) && rs[j] != end {
+				j++
+			}
+			if j >= len(rs) {
+				return nil, fmt.Errorf("unclosed identifier at [%d]", i)
+			}
+			// legacy SQL uses "project:dataset.table".
+			text := strings.Replace(string(rs[i+1:j]), ":", ".", 1)
+			tokens = append(tokens, token{kind: tokenQuotedIdent, text: text})
+			i = j + 1
+		case unicode.IsDigit(r) || (r == '.' && i+1 < len(rs) && unicode.IsDigit(rs[i+1])):
+			j := i
+			for j < len(rs) && (unicode.IsDigit(rs[j]) || rs[j] == '.' || rs[j] == 'e' || rs[j] == 'E' ||
+				((rs[j] == '+' || rs[j] == '-') && (rs[j-1] == 'e' || rs[j-1] == 'E'))) {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: string(rs[i:j])})
+			i = j
+		case isIdentRune(r):
+			j := i
+			for j < len(rs) && (isIdentRune(rs[j]) || unicode.IsDigit(rs[j]) || rs[j] == '-') {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: string(rs[i:j])})
+			i = j
+		case r == '@':
+			j := i + 1
+			for j < len(rs) && (isIdentRune(rs[j]) || unicode.IsDigit(rs[j])) {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokenNamedParam, text: string(rs[i+1 : j])})
+			i = j
+		case r == '?':
+			tokens = append(tokens, token{kind: tokenPositionalParam, text: "?"})
+			i++
+		default:
+			if i+1 < len(rs) {
+				switch op := string(rs[i : i+2]); op {
+				case "!=", "<>", "<=", ">=":
+					tokens = append(tokens, token{kind: tokenSymbol, text: op})
+					i += 2
+					continue
+				}
+			}
+			if !strings.ContainsRune("=<>(),.*-", r) {
+				return nil, fmt.Errorf("unsupported character in the query: %q", r)
+			}
+			tokens = append(tokens, token{kind: tokenSymbol, text: string(r)})
+			i++
+		}
+	}
+	return append(tokens, token{kind: tokenEOF}), nil
+}
+
+func isIdentRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r)
+}
+
+// readQuoted reads the quoted string and returns the string and the length of read runes.
+func readQuoted(rs []rune, quote rune) (string, int, error) {
+	var sb strings.Builder
+	for i := 1; i < len(rs); i++ {
+		switch rs[i] {
+		case '\\':
+			if i+1 < len(rs) {
+				i++
+				sb.WriteRune(rs[i])
+			}
+		case quote:
+			return sb.String(), i + 1, nil
+		default:
+			sb.WriteRune(rs[i])
+		}
+	}
+	return "", 0, fmt.Errorf("unclosed string literal")
+}
+
+// ==========
+// Parser
+// ==========
+
+type parser struct {
+	tokens   []token
+	pos      int
+	qc       queryContext
+	paramPos int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) accept(s string) bool {
+	if p.peek().is(s) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(s string) error {
+	if !p.accept(s) {
+		return p.unexpected()
+	}
+	return nil
+}
+
+func (p *parser) unexpected() error {
+	t := p.peek()
+	if t.kind == tokenEOF {
+		return fmt.Errorf("Syntax error: Unexpected end of script")
+	}
+	return fmt.Errorf("Syntax error: Unexpected %q", t.text)
+}
+
+func (p *parser) parseSelect() (*selectStmt, error) {
+	if err := p.expect("SELECT"); err != nil {
+		return nil, err
+	}
+
+	stmt := &selectStmt{
+		limit: -1,
+	}
+	if !p.accept("*") {
+		for {
+			name, err := p.parseIdent()
+			if err != nil {
+				return nil, err
+			}
+			stmt.columns = append(stmt.columns, name)
+			if !p.accept(",") {
+				break
+			}
+		}
+	}
+
+	if err := p.expect("FROM"); err != nil {
+		return nil, err
+	}
+	ref, err := p.parseTable()
+	if err != nil {
+		return nil, err
+	}
+	stmt.table = ref
+
+	if p.accept("WHERE") {
+		e, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		stmt.where = e
+	}
+	if p.accept("LIMIT") {
+		t := p.next()
+		n, err := strconv.ParseInt(t.text, 10, 64)
+		if t.kind != tokenNumber || err != nil || n < 0 {
+			return nil, fmt.Errorf("LIMIT expects an integer literal; value=[%s]", t.text)
+		}
+		stmt.limit = n
+	}
+	if p.peek().kind != tokenEOF {
+		return nil, p.unexpected()
+	}
+	return stmt, nil
+}
+
+func (p *parser) parseIdent() (string, error) {
+	t := p.peek()
+	if t.kind != tokenIdent && t.kind != tokenQuotedIdent {
+		return "", p.unexpected()
+	}
+	p.next()
+	return t.text, nil
+}
+
+// parseTable parses the table name. (e.g. `project.dataset.table`, dataset.table, table)
+func (p *parser) parseTable() (*SDK.TableReference, error) {
+	var parts []string
+	for {
+		name, err := p.parseIdent()
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, strings.Split(name, ".")...)
+		if !p.accept(".") {
+			break
+		}
+	}
+
+	ref := &SDK.TableReference{
+		ProjectId: p.qc.projectID,
+	}
+	switch len(parts) {
+	case 1:
+		if p.qc.defaultDataset == nil {
+			return nil, fmt.Errorf("Table %q must be qualified with a dataset (e.g. dataset.table)", parts[0])
+		}
+		ref.DatasetId, ref.TableId = p.qc.defaultDataset.DatasetId, parts[0]
+	case 2:
+		ref.DatasetId, ref.TableId = parts[0], parts[1]
+	case 3:
+		ref.ProjectId, ref.DatasetId, ref.TableId = parts[0], parts[1], parts[2]
+	default:
+		return nil, fmt.Errorf("Invalid table name: %s", strings.Join(parts, "."))
+	}
+	return ref, nil
+}
+
+func (p *parser) parseOr() (expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("OR") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalExpr{op: "OR", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (expr, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("AND") {
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalExpr{op: "AND", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot() (expr, error) {
+	if p.accept("NOT") {
+		e, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &notExpr{x: e}, nil
+	}
+	return p.parsePredicate()
+}
+
+func (p *parser) parsePredicate() (expr, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := p.peek(); {
+	case t.is("IS"):
+		p.next()
+		not := p.accept("NOT")
+		if err := p.expect("NULL"); err != nil {
+			return nil, err
+		}
+		return &isNullExpr{x: left, not: not}, nil
+	case t.is("NOT"), t.is("IN"):
+		not := p.accept("NOT")
+		if err := p.expect("IN"); err != nil {
+			return nil, err
+		}
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+		var list []expr
+		for {
+			e, err := p.parseOperand()
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, e)
+			if !p.accept(",") {
+				break
+			}
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return &inExpr{x: left, list: list, not: not}, nil
+	case t.kind == tokenSymbol && strings.Contains("= != <> < <= > >=", t.text):
+		p.next()
+		right, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		return &compareExpr{op: t.text, left: left, right: right}, nil
+	}
+	// boolean operand. (e.g. WHERE is_active)
+	return left, nil
+}
+
+func (p *parser) parseOperand() (expr, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenString:
+		return &literalExpr{value: t.text}, nil
+	case tokenNumber:
+		if n, err := strconv.ParseInt(t.text, 10, 64); err == nil {
+			return &literalExpr{value: n}, nil
+		}
+		f, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid number literal: %s", t.text)
+		}
+		return &literalExpr{value: f}, nil
+	case tokenNamedParam:
+		return p.namedParam(t.text)
+	case tokenPositionalParam:
+		return p.positionalParam()
+	case tokenQuotedIdent:
+		return &columnExpr{path: strings.Split(t.text, ".")}, nil
+	case tokenSymbol:
+		if t.text == "-" && p.peek().kind == tokenNumber {
+			e, err := p.parseOperand()
+			if err != nil {
+				return nil, err
+			}
+			lit := e.(*literalExpr)
+			switch v := lit.value.(type) {
+			case int64:
+				lit.value = -v
+			case float64:
+				lit.value = -v
+			}
+			return lit, nil
+		}
+		if t.text == "(" {
+			e, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			return e, nil
+		}
+	case tokenIdent:
+		switch strings.ToUpper(t.text) {
+		case "NULL":
+			return &literalExpr{}, nil
+		case "TRUE":
+			return &literalExpr{value: true}, nil
+		case "FALSE":
+			return &literalExpr{value: false}, nil
+		case "TIMESTAMP", "DATE", "DATETIME":
+			if p.peek().kind == tokenString {
+				v, err := parseTypedLiteral(strings.ToUpper(t.text), p.next().text)
+				if err != nil {
+					return nil, err
+				}
+				return &literalExpr{value: v}, nil
+			}
+		}
+
+		path := []string{t.text}
+		for p.accept(".") {
+			name, err := p.parseIdent()
+			if err != nil {
+				return nil, err
+			}
+			path = append(path, name)
+		}
+		return &columnExpr{path: path}, nil
+	}
+	if t.kind != tokenEOF {
+		p.pos--
+	}
+	return nil, p.unexpected()
+}
+
+func (p *parser) namedParam(name string) (expr, error) {
+	for _, param := range p.qc.params {
+		if strings.EqualFold(param.Name, name) {
+			return newParamExpr(param)
+		}
+	}
+	return nil, fmt.Errorf("Query parameter '%s' not found", name)
+}
+
+func (p *parser) positionalParam() (expr, error) {
+	if p.paramPos >= len(p.qc.params) {
+		return nil, fmt.Errorf("Number of query parameters does not match the query")
+	}
+	param := p.qc.params[p.paramPos]
+	p.paramPos++
+	return newParamExpr(param)
+}
+
+func newParamExpr(param *SDK.QueryParameter) (expr, error) {
+	if param.ParameterType == nil || param.ParameterValue == nil {
+		return &literalExpr{}, nil
+	}
+	v := param.ParameterValue.Value
+	if len(param.ParameterValue.ArrayValues) != 0 || param.ParameterType.ArrayType != nil {
+		return nil, fmt.Errorf("ARRAY query parameter is not supported by the fake; name=[%s]", param.Name)
+	}
+
+	typ := param.ParameterType.Type
+	switch typ {
+	case "INT64":
+		n, err := strconv.ParseInt(v, 10, 64)
+		return &literalExpr{value: n}, err
+	case "FLOAT64":
+		f, err := strconv.ParseFloat(v, 64)
+		return &literalExpr{value: f}, err
+	case "BOOL":
+		b, err := strconv.ParseBool(v)
+		return &literalExpr{value: b}, err
+	case "NUMERIC", "BIGNUMERIC":
+		r, ok := new(big.Rat).SetString(v)
+		if !ok {
+			return nil, fmt.Errorf("Invalid %s query parameter; name=[%s] value=[%s]", typ, param.Name, v)
+		}
+		return &literalExpr{value: r}, nil
+	case "TIMESTAMP", "DATE", "DATETIME":
+		t, err := parseTypedLiteral(typ, v)
+		return &literalExpr{value: t}, err
+	}
+	return &literalExpr{value: v}, nil
+}
+
+func parseTypedLiteral(typ, s string) (interface{}, error) {
+	switch typ {
+	case "DATE":
+		d, err := civil.ParseDate(s)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid DATE literal: %s", s)
+		}
+		return d.In(time.UTC), nil
+	case "DATETIME":
+		dt, err := civil.ParseDateTime(strings.Replace(s, " ", "T", 1))
+		if err != nil {
+			return nil, fmt.Errorf("Invalid DATETIME literal: %s", s)
+		}
+		return dt.In(time.UTC), nil
+	}
+	t, err := parseTimestamp(s)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid TIMESTAMP literal: %s", s)
+	}
+	return t, nil
+}
+
+// ==========
+// Expression
+// ==========
+
+// expr is the expression of WHERE clause evaluated with the row decoded by the schema.
+// nil value means NULL, and the condition is true only when the result is true.
+type expr interface {
+	eval(row map[string]interface{}) (interface{}, error)
+}
+
+type literalExpr struct {
+	value interface{}
+}
+
+func (e *literalExpr) eval(row map[string]interface{}) (interface{}, error) {
+	return e.value, nil
+}
+
+type columnExpr struct {
+	path []string
+}
+
+func (e *columnExpr) eval(row map[string]interface{}) (interface{}, error) {
+	var v interface{} = row
+	for _, name := range e.path {
+		if v == nil {
+			// field access of NULL record is NULL.
+			return nil, nil
+		}
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("Unrecognized name: %s", strings.Join(e.path, "."))
+		}
+		value, ok := lookupColumn(m, name)
+		if !ok {
+			return nil, fmt.Errorf("Unrecognized name: %s", strings.Join(e.path, "."))
+		}
+		v = value
+	}
+	return v, nil
+}
+
+func lookupColumn(row map[string]interface{}, name string) (interface{}, bool) {
+	if v, ok := row[name]; ok {
+		return v, true
+	}
+	for k, v := range row {
+		if strings.EqualFold(k, name) {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+type logicalExpr struct {
+	op          string
+	left, right expr
+}
+
+func (e *logicalExpr) eval(row map[string]interface{}) (interface{}, error) {
+	l, err := evalBool(e.left, row)
+	if err != nil {
+		return nil, err
+	}
+	r, err := evalBool(e.right, row)
+	if err != nil {
+		return nil, err
+	}
+
+	// three-valued logic.
+	if e.op == "AND" {
+		switch {
+		case isFalse(l) || isFalse(r):
+			return false, nil
+		case l == nil || r == nil:
+			return nil, nil
+		}
+		return true, nil
+	}
+	switch {
+	case isTrue(l) || isTrue(r):
+		return true, nil
+	case l == nil || r == nil:
+		return nil, nil
+	}
+	return false, nil
+}
+
+type notExpr struct {
+	x expr
+}
+
+func (e *notExpr) eval(row map[string]interface{}) (interface{}, error) {
+	v, err := evalBool(e.x, row)
+	if err != nil || v == nil {
+		return nil, err
+	}
+	return !v.(bool), nil
+}
+
+type isNullExpr struct {
+	x   expr
+	not bool
+}
+
+func (e *isNullExpr) eval(row map[string]interface{}) (interface{}, error) {
+	v, err := e.x.eval(row)
+	if err != nil {
+		return nil, err
+	}
+	return (v == nil) != e.not, nil
+}
+
+type inExpr struct {
+	x    expr
+	list []expr
+	not  bool
+}
+
+func (e *inExpr) eval(row map[string]interface{}) (interface{}, error) {
+	v, err := e.x.eval(row)
+	if err != nil || v == nil {
+		return nil, err
+	}
+
+	hasNull := false
+	for _, elem := range e.list {
+		other, err := elem.eval(row)
+		if err != nil {
+			return nil, err
+		}
+		if other == nil {
+			hasNull = true
+			continue
+		}
+		c, err := compareValues(v, other)
+		if err != nil {
+			return nil, err
+		}
+		if c == 0 {
+			return !e.not, nil
+		}
+	}
+	if hasNull {
+		return nil, nil
+	}
+	return e.not, nil
+}
+
+type compareExpr struct {
+	op          string
+	left, right expr
+}
+
+func (e *compareExpr) eval(row map[string]interface{}) (interface{}, error) {
+	l, err := e.left.eval(row)
+	if err != nil {
+		return nil, err
+	}
+	r, err := e.right.eval(row)
+	if err != nil {
+		return nil, err
+	}
+	if l == nil || r == nil {
+		return nil, nil
+	}
+
+	c, err := compareValues(l, r)
+	if err != nil {
+		return nil, err
+	}
+	switch e.op {
+	case "=":
+		return c == 0, nil
+	case "!=", "<>":
+		return c != 0, nil
+	case "<":
+		return c < 0, nil
+	case "<=":
+		return c <= 0, nil
+	case ">":
+		return c > 0, nil
+	}
+	return c >= 0, nil
+}
+
+func evalBool(e expr, row map[string]interface{}) (interface{}, error) {
+	v, err := e.eval(row)
+	if err != nil || v == nil {
+		return nil, err
+	}
+	if _, ok := v.(bool); !ok {
+		return nil, fmt.Errorf("No matching signature for operator; expected BOOL but got %T", v)
+	}
+	return v, nil
+}
+
+func isTrue(v interface{}) bool {
+	b, ok := v.(bool)
+	return ok && b
+}
+
+func isFalse(v interface{}) bool {
+	b, ok := v.(bool)
+	return ok && !b
+}
+
+// compareValues compares the values decoded by the schema and literals.
+// Numbers are compared each other, and the string literal is coerced into TIMESTAMP, DATE or TIME column.
+func compareValues(a, b interface{}) (int, error) {
+	if ra, ok := toRat(a); ok {
+		if rb, ok := toRat(b); ok {
+			return ra.Cmp(rb), nil
+		}
+	}
+
+	switch va := a.(type) {
+	case string:
+		switch vb := b.(type) {
+		case string:
+			return strings.Compare(va, vb), nil
+		case time.Time, civil.Time:
+			c, err := compareValues(b, a)
+			return -c, err
+		}
+	case bool:
+		if vb, ok := b.(bool); ok {
+			switch {
+			case va == vb:
+				return 0, nil
+			case !va:
+				return -1, nil
+			}
+			return 1, nil
+		}
+	case time.Time:
+		switch vb := b.(type) {
+		case time.Time:
+			return compareTime(va, vb), nil
+		case string:
+			t, err := parseTimestamp(vb)
+			if err != nil {
+				return 0, fmt.Errorf("Could not cast literal %q to TIMESTAMP", vb)
+			}
+			return compareTime(va, t), nil
+		}
+	case civil.Time:
+		var tb civil.Time
+		switch vb := b.(type) {
+		case civil.Time:
+			tb = vb
+		case string:
+			t, err := civil.ParseTime(vb)
+			if err != nil {
+				return 0, fmt.Errorf("Could not cast literal %q to TIME", vb)
+			}
+			tb = t
+		default:
+			return 0, fmt.Errorf("No matching signature for operator; TIME and %T", b)
+		}
+		ta := time.Date(0, 1, 1, va.Hour, va.Minute, va.Second, va.Nanosecond, time.UTC)
+		return compareTime(ta, time.Date(0, 1, 1, tb.Hour, tb.Minute, tb.Second, tb.Nanosecond, time.UTC)), nil
+	}
+	return 0, fmt.Errorf("No matching signature for operator; %T and %T", a, b)
+}
+
+func toRat(v interface{}) (*big.Rat, bool) {
+	switch vv := v.(type) {
+	case int64:
+		return new(big.Rat).SetInt64(vv), true
+	case float64:
+		r := new(big.Rat)
+		if r.SetFloat64(vv) == nil {
+			return nil, false
+		}
+		return r, true
+	case *big.Rat:
+		return vv, true
+	}
+	return nil, false
+}
+
+func compareTime(a, b time.Time) int {
+	switch {
+	case a.Before(b):
+		return -1
+	case a.After(b):
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,223 @@
+package bigquerytest
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	SDK "google.golang.org/api/bigquery/v2"
+)
+
+func TestParseQuery(t *testing.T) {
+	qc := queryContext{
+		projectID:      "project",
+		defaultDataset: &SDK.DatasetReference{ProjectId: "project", DatasetId: "default_ds"},
+		params: []*SDK.QueryParameter{
+			{Name: "name", ParameterType: &SDK.QueryParameterType{Type: "STRING"}, ParameterValue: &SDK.QueryParameterValue{Value: "alice"}},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		sql         string
+		wantColumns []string
+		wantTable   string
+		wantLimit   int64
+		wantErr     bool
+	}{
+		{"all columns", "SELECT * FROM ds.tbl", nil, "project.ds.tbl", -1, false},
+		{"columns and limit", "SELECT name, age FROM ds.tbl LIMIT 10", []string{"name", "age"}, "project.ds.tbl", 10, false},
+		{"quoted table", "SELECT * FROM `other.ds.tbl` WHERE age > 20", nil, "other.ds.tbl", -1, false},
+		{"default dataset", "SELECT * FROM tbl WHERE name = @name", nil, "project.default_ds.tbl", -1, false},
+		{"lower case", "select * from ds.tbl where age in (1, 2) limit 0", nil, "project.ds.tbl", 0, false},
+		{"missing FROM", "SELECT *", nil, "", 0, true},
+		{"invalid LIMIT", "SELECT * FROM ds.tbl LIMIT -1", nil, "", 0, true},
+		{"unknown parameter", "SELECT * FROM ds.tbl WHERE name = @unknown", nil, "", 0, true},
+		{"positional parameter count", "SELECT * FROM ds.tbl WHERE name = ? AND age = ?", nil, "", 0, true},
+		{"trailing token", "SELECT * FROM ds.tbl ORDER BY name", nil, "", 0, true},
+		{"unterminated IN", "SELECT * FROM ds.tbl WHERE age IN (1, 2", nil, "", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stmt, err := parseQuery(tt.sql, qc)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got %#v", stmt)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+
+			if !reflect.DeepEqual(stmt.columns, tt.wantColumns) {
+				t.Errorf("columns: got %v, want %v", stmt.columns, tt.wantColumns)
+			}
+			table := stmt.table.ProjectId + "." + stmt.table.DatasetId + "." + stmt.table.TableId
+			if table != tt.wantTable {
+				t.Errorf("table: got %s, want %s", table, tt.wantTable)
+			}
+			if stmt.limit != tt.wantLimit {
+				t.Errorf("limit: got %d, want %d", stmt.limit, tt.wantLimit)
+			}
+		})
+	}
+}
+
+func TestParseQueryWhere(t *testing.T) {
+	row := map[string]interface{}{
+		"name":   "alice",
+		"age":    int64(20),
+		"score":  1.5,
+		"active": true,
+		"note":   nil,
+		"ts":     time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+		"rec":    map[string]interface{}{"city": "tokyo"},
+	}
+	params := []*SDK.QueryParameter{
+		{ParameterType: &SDK.QueryParameterType{Type: "INT64"}, ParameterValue: &SDK.QueryParameterValue{Value: "20"}},
+		{Name: "min", ParameterType: &SDK.QueryParameterType{Type: "NUMERIC"}, ParameterValue: &SDK.QueryParameterValue{Value: "1.25"}},
+	}
+
+	tests := []struct {
+		where string
+		want  interface{}
+	}{
+		{"age = 20", true},
+		{"age >= 21", false},
+		{"age = ?", true},
+		{"score > @min", true},
+		{"name = 'alice' AND active", true},
+		{"name = 'bob' OR NOT active", false},
+		{"note IS NULL", true},
+		{"note IS NOT NULL", false},
+		{"note = 'x'", nil},
+		{"note = 'x' OR age = 20", true},
+		{"note = 'x' AND age = 20", nil},
+		{"NOT (note = 'x')", nil},
+		{"age IN (1, 20)", true},
+		{"age NOT IN (1, 2)", true},
+		{"ts > '2020-01-01 00:00:00'", true},
+		{"ts < TIMESTAMP '2020-01-01'", false},
+		{"rec.city = 'tokyo'", true},
+		{"age = -20", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.where, func(t *testing.T) {
+			stmt, err := parseQuery("SELECT * FROM ds.tbl WHERE "+tt.where, queryContext{
+				projectID: "project",
+				params:    params,
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+			got, err := stmt.where.eval(row)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+			if got != tt.want {
+				t.Errorf("got %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompareExprEval(t *testing.T) {
+	lit := func(v interface{}) expr { return &literalExpr{value: v} }
+	ts := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		op      string
+		left    interface{}
+		right   interface{}
+		want    interface{}
+		wantErr bool
+	}{
+		{"int equal", "=", int64(1), int64(1), true, false},
+		{"int and float", "<", int64(1), 1.5, true, false},
+		{"float and numeric", ">=", 1.5, big.NewRat(3, 2), true, false},
+		{"not equal", "!=", "a", "b", true, false},
+		{"not equal alias", "<>", "a", "a", false, false},
+		{"string less", "<", "a", "b", true, false},
+		{"string greater", ">", "a", "b", false, false},
+		{"less or equal", "<=", int64(2), int64(2), true, false},
+		{"bool", ">", true, false, true, false},
+		{"timestamp and string", "=", ts, "2020-01-02 03:04:05", true, false},
+		{"string and timestamp", "<", "2020-01-01", ts, true, false},
+		{"left NULL", "=", nil, int64(1), nil, false},
+		{"right NULL", "!=", int64(1), nil, nil, false},
+		{"mismatched types", "=", "1", int64(1), nil, true},
+		{"invalid timestamp literal", "=", ts, "not a time", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &compareExpr{op: tt.op, left: lit(tt.left), right: lit(tt.right)}
+			got, err := e.eval(nil)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got %#v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+			if got != tt.want {
+				t.Errorf("got %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInExprEval(t *testing.T) {
+	lits := func(values ...interface{}) []expr {
+		list := make([]expr, len(values))
+		for i, v := range values {
+			list[i] = &literalExpr{value: v}
+		}
+		return list
+	}
+
+	tests := []struct {
+		name    string
+		x       interface{}
+		list    []expr
+		not     bool
+		want    interface{}
+		wantErr bool
+	}{
+		{"found", int64(2), lits(int64(1), int64(2)), false, true, false},
+		{"not found", int64(3), lits(int64(1), int64(2)), false, false, false},
+		{"NOT IN found", "a", lits("a", "b"), true, false, false},
+		{"NOT IN not found", "c", lits("a", "b"), true, true, false},
+		{"NULL value", nil, lits(int64(1)), false, nil, false},
+		{"found with NULL in list", int64(1), lits(nil, int64(1)), false, true, false},
+		{"not found with NULL in list", int64(3), lits(int64(1), nil), false, nil, false},
+		{"NOT IN with NULL in list", int64(3), lits(int64(1), nil), true, nil, false},
+		{"mismatched types", "a", lits(int64(1)), false, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &inExpr{x: &literalExpr{value: tt.x}, list: tt.list, not: tt.not}
+			got, err := e.eval(nil)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got %#v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+			if got != tt.want {
+				t.Errorf("got %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package bigquery
+
+import (
+	"context"
+
+	SDK "google.golang.org/api/bigquery/v2"
+)
+
+// Client is the interface of dataset, table, routine, query and job operations of BigQuery.
+// *BigQuery implements it, and the code depending on Client can be tested with *BigQuery
+// connected to the in-memory fake. (see bigquerytest package)
+//
+// Client is not designed for hand-written mocks. DatasetAPI, the iterators and *Job returned from Client
+// are bound to *BigQuery, so other implementations cannot return working values for them,
+// and methods may be added to Client when new operations are supported.
+//
+//	srv := bigquerytest.NewServer("my-project")
+//	cli, err := srv.Client() // *BigQuery connected to the fake.
+//	...
+//	err = RunMyCode(cli)
+type Client interface {
+	DatasetAPI(datasetID string) *DatasetAPI
+
+	// dataset
+	CreateDatasetWithContext(ctx context.Context, dataset *SDK.Dataset) (*Dataset, error)
+	PatchDatasetWithContext(ctx context.Context, datasetID string, dataset *SDK.Dataset) (*Dataset, error)
+	UpdateDatasetWithContext(ctx context.Context, datasetID string, dataset *SDK.Dataset) (*Dataset, error)
+	DeleteDatasetWithContext(ctx context.Context, datasetID string) error
+	GetDatasetWithContext(ctx context.Context, datasetID string) (*Dataset, error)
+	ListDatasetsWithContext(ctx context.Context) (*SDK.DatasetList, error)
+
+	// table
+	CreateTableWithContext(ctx context.Context, datasetID string, tbl *SDK.Table) (*Table, error)
+	PatchTableWithContext(ctx context.Context, datasetID string, tableID string, tbl *SDK.Table) (*Table, error)
+	UpdateTableWithContext(ctx context.Context, datasetID string, tableID string, tbl *SDK.Table) (*Table, error)
+	DropTableWithContext(ctx context.Context, datasetID string, tableID string) error
+	GetTableWithContext(ctx context.Context, datasetID string, tableID string) (*Table, error)
+	ListTablesWithContext(ctx context.Context, datasetID string, tableID string) (*SDK.TableList, error)
+	InsertAllWithContext(ctx context.Context, datasetID string, tableID string, rows *SDK.TableDataInsertAllRequest) (*SDK.TableDataInsertAllResponse, error)
+	GetTableDataWithContext(ctx context.Context, datasetID string, tableID string) (*SDK.TableDataList, error)
+	TableDataIterator(ctx context.Context, datasetID, tableID string, opt PageOption) *RowIterator
+
+	// routine
+	CreateRoutineWithContext(ctx context.Context, datasetID string, routine *SDK.Routine) (*Routine, error)
+	UpdateRoutineWithContext(ctx context.Context, datasetID string, routineID string, routine *SDK.Routine) (*Routine, error)
+	DeleteRoutineWithContext(ctx context.Context, datasetID string, routineID string) error
+	GetRoutineWithContext(ctx context.Context, datasetID string, routineID string) (*Routine, error)
+	ListRoutinesWithContext(ctx context.Context, datasetID string) (*SDK.ListRoutinesResponse, error)
+	RoutinesIterator(ctx context.Context, datasetID string, opt PageOption) *RoutineIterator
+
+	// query
+	QueryWithContext(ctx context.Context, opt QueryOption) (*QueryResponse, error)
+	EstimateQueryWithContext(ctx context.Context, opt QueryOption) (*QueryEstimate, error)
+	RunQueryWithContext(ctx context.Context, query *SDK.QueryRequest) (*SDK.QueryResponse, error)
+	GetQueryResultsWithContext(ctx context.Context, jobID string) (*SDK.GetQueryResultsResponse, error)
+	QueryResultsIterator(ctx context.Context, jobID string, opt PageOption) *RowIterator
+
+	// job
+	RunJobWithContext(ctx context.Context, job *SDK.Job) (*SDK.Job, error)
+	StartJobWithContext(ctx context.Context, job *SDK.Job) (*Job, error)
+	GetJobInLocationWithContext(ctx context.Context, jobID, location string) (*SDK.Job, error)
+	CancelJobInLocationWithContext(ctx context.Context, jobID, location string) (*SDK.JobCancelResponse, error)
+	ListJobsWithContext(ctx context.Context) (*SDK.JobList, error)
+	JobFromReference(jobID, location string) *Job
+}
+
+var _ Client = (*BigQuery)(nil)
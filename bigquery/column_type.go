@@ -0,0 +1,252 @@
+package bigquery
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/civil"
+	SDK "google.golang.org/api/bigquery/v2"
+)
+
+const (
+	layoutDate     = "2006-01-02"
+	layoutDateTime = "2006-01-02T15:04:05.999999999"
+)
+
+var (
+	errSchemaMismatch = errors.New("the number of columns does not match with schema")
+)
+
+// columnType is the column definition from table schema and decodes raw value from API response.
+type columnType struct {
+	Name   string
+	Type   string
+	Mode   string
+	Fields []columnType
+}
+
+func newColumnType(f *SDK.TableFieldSchema) columnType {
+	return columnType{
+		Name:   f.Name,
+		Type:   strings.ToUpper(f.Type),
+		Mode:   strings.ToUpper(f.Mode),
+		Fields: newColumnTypes(f.Fields),
+	}
+}
+
+func newColumnTypes(fields []*SDK.TableFieldSchema) []columnType {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	list := make([]columnType, len(fields))
+	for i, f := range fields {
+		list[i] = newColumnType(f)
+	}
+	return list
+}
+
+// AssignData decodes the value and sets it into the row.
+func (c columnType) AssignData(row map[string]interface{}, value interface{}) error {
+	v, err := c.Decode(value)
+	if err != nil {
+		return err
+	}
+	row[c.Name] = v
+	return nil
+}
+
+// Decode decodes raw cell value into Go value.
+// NULL is decoded as nil, REPEATED as []interface{} and RECORD as map[string]interface{}.
+func (c columnType) Decode(value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+	if !c.IsRepeated() {
+		return c.decodeSingle(value)
+	}
+
+	list, ok := value.([]interface{})
+	if !ok {
+		return nil, c.newDecodeError(value)
+	}
+	results := make([]interface{}, len(list))
+	for i, elem := range list {
+		v, err := c.decodeSingle(cellValue(elem))
+		if err != nil {
+			return nil, err
+		}
+		results[i] = v
+	}
+	return results, nil
+}
+
+func (c columnType) decodeSingle(value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+	if c.IsRecord() {
+		cells, ok := recordCells(value)
+		if !ok {
+			return nil, c.newDecodeError(value)
+		}
+		if len(cells) > len(c.Fields) {
+			return nil, errSchemaMismatch
+		}
+
+		row := make(map[string]interface{}, len(cells))
+		for i, cell := range cells {
+			if err := c.Fields[i].AssignData(row, cell); err != nil {
+				return nil, err
+			}
+		}
+		return row, nil
+	}
+
+	v, ok := value.(string)
+	if !ok {
+		return nil, c.newDecodeError(value)
+	}
+
+	switch {
+	case c.IsString(), c.IsGeography(), c.IsInterval():
+		return v, nil
+	case c.IsInt():
+		return strconv.ParseInt(v, 10, 64)
+	case c.IsFloat():
+		return strconv.ParseFloat(v, 64)
+	case c.IsBool():
+		return strconv.ParseBool(v)
+	case c.IsNumeric():
+		r, ok := new(big.Rat).SetString(v)
+		if !ok {
+			return nil, c.newDecodeError(value)
+		}
+		return r, nil
+	case c.IsBytes():
+		return base64.StdEncoding.DecodeString(v)
+	case c.IsTimestamp():
+		return parseTimestamp(v)
+	case c.IsDate():
+		return time.Parse(layoutDate, v)
+	case c.IsDateTime():
+		return time.Parse(layoutDateTime, strings.Replace(v, " ", "T", 1))
+	case c.IsTime():
+		return civil.ParseTime(v)
+	case c.IsJSON():
+		var data interface{}
+		err := json.Unmarshal([]byte(v), &data)
+		return data, err
+	}
+	// unknown type is returned as it is.
+	return v, nil
+}
+
+func (c columnType) newDecodeError(value interface{}) error {
+	return fmt.Errorf("cannot decode the value of column; name=[%s] type=[%s] value=[%v]", c.Name, c.Type, value)
+}
+
+func (c columnType) IsRepeated() bool {
+	return c.Mode == "REPEATED"
+}
+
+func (c columnType) IsRecord() bool {
+	return c.Type == "RECORD" || c.Type == "STRUCT"
+}
+
+func (c columnType) IsString() bool {
+	return c.Type == "STRING"
+}
+
+func (c columnType) IsInt() bool {
+	return c.Type == "INTEGER" || c.Type == "INT64"
+}
+
+func (c columnType) IsFloat() bool {
+	return c.Type == "FLOAT" || c.Type == "FLOAT64"
+}
+
+func (c columnType) IsBool() bool {
+	return c.Type == "BOOLEAN" || c.Type == "BOOL"
+}
+
+func (c columnType) IsNumeric() bool {
+	return c.Type == "NUMERIC" || c.Type == "BIGNUMERIC"
+}
+
+func (c columnType) IsBytes() bool {
+	return c.Type == "BYTES"
+}
+
+func (c columnType) IsTimestamp() bool {
+	return c.Type == "TIMESTAMP"
+}
+
+func (c columnType) IsDate() bool {
+	return c.Type == "DATE"
+}
+
+func (c columnType) IsDateTime() bool {
+	return c.Type == "DATETIME"
+}
+
+func (c columnType) IsTime() bool {
+	return c.Type == "TIME"
+}
+
+func (c columnType) IsGeography() bool {
+	return c.Type == "GEOGRAPHY"
+}
+
+func (c columnType) IsJSON() bool {
+	return c.Type == "JSON"
+}
+
+func (c columnType) IsInterval() bool {
+	return c.Type == "INTERVAL"
+}
+
+// parseTimestamp parses TIMESTAMP value formatted as floating point seconds from epoch. (e.g. "1.5E9")
+func parseTimestamp(v string) (time.Time, error) {
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	secs := math.Trunc(f)
+	nanos := math.Round((f - secs) * 1e9)
+	return time.Unix(int64(secs), int64(nanos)).Round(time.Microsecond).UTC(), nil
+}
+
+// cellValue returns the value of {"v": value} object.
+func cellValue(value interface{}) interface{} {
+	if m, ok := value.(map[string]interface{}); ok {
+		return m["v"]
+	}
+	return value
+}
+
+// recordCells returns the list of the values in {"f": [{"v": value}, ...]} object.
+func recordCells(value interface{}) ([]interface{}, bool) {
+	m, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	list, ok := m["f"].([]interface{})
+	if !ok {
+		return nil, false
+	}
+
+	cells := make([]interface{}, len(list))
+	for i, v := range list {
+		cells[i] = cellValue(v)
+	}
+	return cells, true
+}
@@ -0,0 +1,113 @@
+package bigquery
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/civil"
+	SDK "google.golang.org/api/bigquery/v2"
+)
+
+func TestColumnTypeDecode(t *testing.T) {
+	tests := []struct {
+		name    string
+		column  columnType
+		value   interface{}
+		want    interface{}
+		wantErr bool
+	}{
+		{"null", columnType{Type: "STRING"}, nil, nil, false},
+		{"string", columnType{Type: "STRING"}, "abc", "abc", false},
+		{"integer", columnType{Type: "INTEGER"}, "-10", int64(-10), false},
+		{"int64", columnType{Type: "INT64"}, "9223372036854775807", int64(9223372036854775807), false},
+		{"float", columnType{Type: "FLOAT"}, "1.5", 1.5, false},
+		{"bool", columnType{Type: "BOOLEAN"}, "true", true, false},
+		{"numeric", columnType{Type: "NUMERIC"}, "1.25", big.NewRat(5, 4), false},
+		{"bignumeric", columnType{Type: "BIGNUMERIC"}, "0.00000000000000000000000000000000000001", new(big.Rat).SetFrac(big.NewInt(1), new(big.Int).Exp(big.NewInt(10), big.NewInt(38), nil)), false},
+		{"bytes", columnType{Type: "BYTES"}, "YWJj", []byte("abc"), false},
+		{"timestamp", columnType{Type: "TIMESTAMP"}, "1.5E9", time.Unix(1500000000, 0).UTC(), false},
+		{"timestamp micro", columnType{Type: "TIMESTAMP"}, "1500000000.123456", time.Unix(1500000000, 123456000).UTC(), false},
+		{"date", columnType{Type: "DATE"}, "2020-01-02", time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC), false},
+		{"datetime", columnType{Type: "DATETIME"}, "2020-01-02 03:04:05.123456", time.Date(2020, 1, 2, 3, 4, 5, 123456000, time.UTC), false},
+		{"time", columnType{Type: "TIME"}, "03:04:05", civil.Time{Hour: 3, Minute: 4, Second: 5}, false},
+		{"json", columnType{Type: "JSON"}, `{"a":1}`, map[string]interface{}{"a": float64(1)}, false},
+		{"json null", columnType{Type: "JSON"}, "null", nil, false},
+		{"repeated", columnType{Type: "INTEGER", Mode: "REPEATED"}, []interface{}{
+			map[string]interface{}{"v": "1"},
+			map[string]interface{}{"v": "2"},
+		}, []interface{}{int64(1), int64(2)}, false},
+		{"record", columnType{Type: "RECORD", Fields: []columnType{
+			{Name: "a", Type: "STRING"},
+			{Name: "b", Type: "INTEGER"},
+		}}, map[string]interface{}{"f": []interface{}{
+			map[string]interface{}{"v": "x"},
+			map[string]interface{}{"v": nil},
+		}}, map[string]interface{}{"a": "x", "b": nil}, false},
+		{"invalid integer", columnType{Type: "INTEGER"}, "x", nil, true},
+		{"invalid numeric", columnType{Type: "NUMERIC"}, "x", nil, true},
+		{"invalid repeated", columnType{Type: "INTEGER", Mode: "REPEATED"}, "1", nil, true},
+		{"invalid record", columnType{Type: "RECORD"}, "x", nil, true},
+		{"too many record cells", columnType{Type: "RECORD", Fields: []columnType{
+			{Name: "a", Type: "STRING"},
+		}}, map[string]interface{}{"f": []interface{}{
+			map[string]interface{}{"v": "x"},
+			map[string]interface{}{"v": "y"},
+		}}, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.column.Decode(tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %#v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+
+			if want, ok := tt.want.(*big.Rat); ok {
+				if r, ok := got.(*big.Rat); !ok || r.Cmp(want) != 0 {
+					t.Errorf("got %#v, want %s", got, want.String())
+				}
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeRow(t *testing.T) {
+	schema := &SDK.TableSchema{
+		Fields: []*SDK.TableFieldSchema{
+			{Name: "name", Type: "STRING"},
+			{Name: "age", Type: "INTEGER"},
+		},
+	}
+
+	got, err := DecodeRow(schema, &SDK.TableRow{
+		F: []*SDK.TableCell{{V: "alice"}, {V: "20"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	want := map[string]interface{}{"name": "alice", "age": int64(20)}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %#v, want %#v", got, want)
+	}
+
+	if _, err := DecodeRow(nil, &SDK.TableRow{}); err != errNilSchema {
+		t.Errorf("expected errNilSchema, got %v", err)
+	}
+	if _, err := DecodeRow(schema, &SDK.TableRow{
+		F: []*SDK.TableCell{{V: "alice"}, {V: "20"}, {V: "x"}},
+	}); err != errSchemaMismatch {
+		t.Errorf("expected errSchemaMismatch, got %v", err)
+	}
+}
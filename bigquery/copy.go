@@ -0,0 +1,159 @@
+package bigquery
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	SDK "google.golang.org/api/bigquery/v2"
+)
+
+var errCopySource = errors.New("at least one source table is required for copy job")
+
+// operation types for copy jobs.
+const (
+	CopyOperationCopy     = "COPY"
+	CopyOperationSnapshot = "SNAPSHOT"
+	CopyOperationRestore  = "RESTORE"
+	CopyOperationClone    = "CLONE"
+)
+
+// table types.
+const (
+	TableTypeTable            = "TABLE"
+	TableTypeView             = "VIEW"
+	TableTypeMaterializedView = "MATERIALIZED_VIEW"
+	TableTypeExternal         = "EXTERNAL"
+	TableTypeSnapshot         = "SNAPSHOT"
+)
+
+// CopyOption is optional parameters used for copy job.
+type CopyOption struct {
+	WriteDisposition  string
+	CreateDisposition string
+
+	// OperationType is the type of copy operation. (default: COPY)
+	// SNAPSHOT and CLONE accept only single source table.
+	OperationType string
+	// DestinationExpirationTime is the expiration time of the destination table, used for SNAPSHOT.
+	DestinationExpirationTime time.Time
+
+	Location string
+	Labels   map[string]string
+
+	// NoWait returns the job right after it's created.
+	NoWait bool
+}
+
+func (o CopyOption) toJob(sources []*SDK.TableReference, dest *SDK.TableReference) (*SDK.Job, error) {
+	if len(sources) == 0 {
+		return nil, errCopySource
+	}
+
+	cp := &SDK.JobConfigurationTableCopy{
+		DestinationTable:  dest,
+		WriteDisposition:  o.WriteDisposition,
+		CreateDisposition: o.CreateDisposition,
+		OperationType:     o.OperationType,
+	}
+	if len(sources) == 1 {
+		cp.SourceTable = sources[0]
+	} else {
+		cp.SourceTables = sources
+	}
+	if !o.DestinationExpirationTime.IsZero() {
+		cp.DestinationExpirationTime = o.DestinationExpirationTime.UTC().Format(time.RFC3339)
+	}
+
+	return &SDK.Job{
+		Configuration: &SDK.JobConfiguration{
+			Copy:   cp,
+			Labels: o.Labels,
+		},
+		JobReference: &SDK.JobReference{
+			ProjectId: dest.ProjectId,
+			Location:  o.Location,
+		},
+	}, nil
+}
+
+// CopyTables copies the source tables into the destination table and waits for the job completion.
+// Multiple source tables are appended into the destination table.
+func (b *BigQuery) CopyTables(sources []*SDK.TableReference, dest *SDK.TableReference, opt CopyOption) (*Job, error) {
+	return b.CopyTablesWithContext(context.Background(), sources, dest, opt)
+}
+
+// CopyTablesWithContext copies the source tables into the destination table with the given context.
+func (b *BigQuery) CopyTablesWithContext(ctx context.Context, sources []*SDK.TableReference, dest *SDK.TableReference, opt CopyOption) (*Job, error) {
+	req, err := opt.toJob(sources, dest)
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := b.StartJobWithContext(ctx, req)
+	switch {
+	case err != nil:
+		return nil, err
+	case opt.NoWait:
+		return job, nil
+	}
+	return job, job.Wait(ctx)
+}
+
+// CopyTo copies the table into the destination table in the same project and waits for the job completion.
+func (t *TableAPI) CopyTo(destDatasetID, destTableID string, opt CopyOption) (*Job, error) {
+	return t.CopyToWithContext(context.Background(), destDatasetID, destTableID, opt)
+}
+
+// CopyToWithContext copies the table into the destination table in the same project with the given context.
+func (t *TableAPI) CopyToWithContext(ctx context.Context, destDatasetID, destTableID string, opt CopyOption) (*Job, error) {
+	cli := t.dataset.client
+	dest := &SDK.TableReference{
+		ProjectId: cli.projectID,
+		DatasetId: destDatasetID,
+		TableId:   destTableID,
+	}
+	return cli.CopyTablesWithContext(ctx, []*SDK.TableReference{t.tableReference()}, dest, opt)
+}
+
+// CopyAllTables copies all of the tables in the dataset into the destination dataset in the same project.
+// Views, materialized views and external tables are skipped because they cannot be copied.
+// All of the copy jobs are started first and waited unless NoWait is set.
+func (ds *DatasetAPI) CopyAllTables(destDatasetID string, opt CopyOption) ([]*Job, error) {
+	return ds.CopyAllTablesWithContext(context.Background(), destDatasetID, opt)
+}
+
+// CopyAllTablesWithContext copies all of the tables in the dataset into the destination dataset with the given context.
+func (ds *DatasetAPI) CopyAllTablesWithContext(ctx context.Context, destDatasetID string, opt CopyOption) ([]*Job, error) {
+	noWait := opt.NoWait
+	opt.NoWait = true
+
+	var jobs []*Job
+	it := ds.TablesIterator(ctx, PageOption{})
+	for it.Next() {
+		tbl := it.Table()
+		if tbl.Type != "" && tbl.Type != TableTypeTable {
+			continue
+		}
+
+		tableID := tbl.TableReference.TableId
+		job, err := ds.TableAPI(tableID).CopyToWithContext(ctx, destDatasetID, tableID, opt)
+		if err != nil {
+			return jobs, err
+		}
+		jobs = append(jobs, job)
+	}
+	if err := it.Err(); err != nil {
+		return jobs, err
+	}
+
+	if noWait {
+		return jobs, nil
+	}
+	for _, job := range jobs {
+		if err := job.Wait(ctx); err != nil {
+			return jobs, err
+		}
+	}
+	return jobs, nil
+}
@@ -0,0 +1,134 @@
+package bigquery
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	SDK "google.golang.org/api/bigquery/v2"
+)
+
+func TestTableAPICopyTo(t *testing.T) {
+	src := &SDK.TableReference{ProjectId: "project", DatasetId: "ds", TableId: "tbl"}
+	dest := &SDK.TableReference{ProjectId: "project", DatasetId: "backup", TableId: "tbl_20200102"}
+
+	tests := []struct {
+		name string
+		opt  CopyOption
+		want *SDK.JobConfigurationTableCopy
+	}{
+		{"copy", CopyOption{WriteDisposition: WriteTruncate}, &SDK.JobConfigurationTableCopy{
+			SourceTable:      src,
+			DestinationTable: dest,
+			WriteDisposition: "WRITE_TRUNCATE",
+		}},
+		{"snapshot", CopyOption{
+			OperationType:             CopyOperationSnapshot,
+			DestinationExpirationTime: time.Date(2020, 1, 2, 12, 0, 0, 0, time.FixedZone("JST", 9*60*60)),
+		}, &SDK.JobConfigurationTableCopy{
+			SourceTable:               src,
+			DestinationTable:          dest,
+			OperationType:             "SNAPSHOT",
+			DestinationExpirationTime: "2020-01-02T03:00:00Z",
+		}},
+		{"clone", CopyOption{OperationType: CopyOperationClone}, &SDK.JobConfigurationTableCopy{
+			SourceTable:      src,
+			DestinationTable: dest,
+			OperationType:    "CLONE",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := &jobTestServer{states: []string{JobStateDone}}
+			tbl := newTestBigQuery(t, srv).DatasetAPI("ds").TableAPI("tbl")
+
+			tt.opt.NoWait = true
+			if _, err := tbl.CopyTo("backup", "tbl_20200102", tt.opt); err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+			if got := srv.inserted.Configuration.Copy; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBigQueryCopyTables(t *testing.T) {
+	srv := &jobTestServer{states: []string{JobStateDone}}
+	b := newTestBigQuery(t, srv)
+
+	sources := []*SDK.TableReference{
+		{ProjectId: "project", DatasetId: "ds", TableId: "a"},
+		{ProjectId: "other", DatasetId: "ds", TableId: "b"},
+	}
+	dest := &SDK.TableReference{ProjectId: "project", DatasetId: "ds", TableId: "all"}
+	job, err := b.CopyTables(sources, dest, CopyOption{Location: "EU"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if !job.IsDone() {
+		t.Errorf("state: got %s, want %s", job.State(), JobStateDone)
+	}
+
+	want := &SDK.JobConfigurationTableCopy{
+		SourceTables:     sources,
+		DestinationTable: dest,
+	}
+	if got := srv.inserted.Configuration.Copy; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %#v, want %#v", got, want)
+	}
+	if loc := srv.inserted.JobReference.Location; loc != "EU" {
+		t.Errorf("location: got %s, want EU", loc)
+	}
+
+	if _, err := b.CopyTables(nil, dest, CopyOption{}); err != errCopySource {
+		t.Errorf("got %v, want %v", err, errCopySource)
+	}
+}
+
+func TestDatasetAPICopyAllTables(t *testing.T) {
+	srv := &jobTestServer{states: []string{JobStateDone}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/bigquery/v2/projects/project/datasets/ds/tables", func(w http.ResponseWriter, r *http.Request) {
+		list := &SDK.TableList{}
+		for _, tbl := range []struct{ id, typ string }{{"a", "TABLE"}, {"v", "VIEW"}, {"mv", "MATERIALIZED_VIEW"}, {"b", ""}} {
+			list.Tables = append(list.Tables, &SDK.TableListTables{
+				TableReference: &SDK.TableReference{ProjectId: "project", DatasetId: "ds", TableId: tbl.id},
+				Type:           tbl.typ,
+			})
+		}
+		json.NewEncoder(w).Encode(list)
+	})
+	mux.Handle("/", srv)
+	ds := newTestBigQuery(t, mux).DatasetAPI("ds")
+
+	jobs, err := ds.CopyAllTables("backup", CopyOption{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if len(jobs) != 2 {
+		t.Fatalf("got %d jobs, want 2", len(jobs))
+	}
+
+	var copied []string
+	for _, job := range srv.jobs {
+		cp := job.Configuration.Copy
+		copied = append(copied, cp.SourceTable.TableId+"->"+cp.DestinationTable.DatasetId+"."+cp.DestinationTable.TableId)
+	}
+	if want := []string{"a->backup.a", "b->backup.b"}; !reflect.DeepEqual(copied, want) {
+		t.Errorf("got %v, want %v", copied, want)
+	}
+
+	// all of the jobs are started before waiting.
+	var inserts []string
+	for _, req := range srv.requests {
+		inserts = append(inserts, strings.Fields(req)[0])
+	}
+	if want := []string{"POST", "POST", "GET", "GET"}; !reflect.DeepEqual(inserts, want) {
+		t.Errorf("got %v, want %v", srv.requests, want)
+	}
+}
@@ -1,6 +1,8 @@
 package bigquery
 
 import (
+	"context"
+
 	"github.com/evalphobia/google-api-go-wrapper/config"
 )
 
@@ -32,16 +34,44 @@ func (ds *DatasetAPI) TableAPI(tableID string) *TableAPI {
 	}
 }
 
+// RoutineAPI returns initialized RoutineAPI.
+func (ds *DatasetAPI) RoutineAPI(routineID string) *RoutineAPI {
+	return &RoutineAPI{
+		dataset:   ds,
+		routineID: routineID,
+	}
+}
+
 // Get gets the dataset.
 func (ds *DatasetAPI) Get() (*Dataset, error) {
+	return ds.GetWithContext(context.Background())
+}
+
+// GetWithContext gets the dataset with the given context.
+func (ds *DatasetAPI) GetWithContext(ctx context.Context) (*Dataset, error) {
 	cli := ds.client
-	return cli.GetDataset(ds.datasetID)
+	return cli.GetDatasetWithContext(ctx, ds.datasetID)
 }
 
 // Delete deletes the dataset.
 func (ds *DatasetAPI) Delete() error {
+	return ds.DeleteWithContext(context.Background())
+}
+
+// DeleteWithContext deletes the dataset with the given context.
+func (ds *DatasetAPI) DeleteWithContext(ctx context.Context) error {
 	cli := ds.client
-	return cli.DeleteDataset(ds.datasetID)
+	return cli.DeleteDatasetWithContext(ctx, ds.datasetID)
+}
+
+// TablesIterator returns iterator for all of the tables in the dataset.
+func (ds *DatasetAPI) TablesIterator(ctx context.Context, opt PageOption) *TableIterator {
+	return ds.client.TablesIterator(ctx, ds.datasetID, opt)
+}
+
+// RoutinesIterator returns iterator for all of the routines in the dataset.
+func (ds *DatasetAPI) RoutinesIterator(ctx context.Context, opt PageOption) *RoutineIterator {
+	return ds.client.RoutinesIterator(ctx, ds.datasetID, opt)
 }
 
 // CreateTable creates the table with schema defined from given struct
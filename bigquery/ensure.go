@@ -0,0 +1,249 @@
+package bigquery
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	SDK "google.golang.org/api/bigquery/v2"
+	"google.golang.org/api/googleapi"
+)
+
+// DatasetOption is settings of the dataset used for Ensure.
+type DatasetOption struct {
+	// Location is the geographic location of the dataset. (e.g. US, asia-northeast1)
+	// It cannot be changed after creation.
+	Location     string
+	Description  string
+	FriendlyName string
+	Labels       map[string]string
+
+	// DefaultTableExpiration is the default lifetime of new tables in the dataset.
+	DefaultTableExpiration time.Duration
+	// DefaultPartitionExpiration is the default lifetime of partitions of new partitioned tables in the dataset.
+	DefaultPartitionExpiration time.Duration
+
+	// Reconcile updates the settings of the existing dataset when they are different from the option.
+	// Empty settings are not compared, and labels are added or updated but not removed.
+	Reconcile bool
+}
+
+func (o DatasetOption) toSDK(ref *SDK.DatasetReference) *SDK.Dataset {
+	return &SDK.Dataset{
+		DatasetReference:             ref,
+		Location:                     o.Location,
+		Description:                  o.Description,
+		FriendlyName:                 o.FriendlyName,
+		Labels:                       o.Labels,
+		DefaultTableExpirationMs:     durationToMillis(o.DefaultTableExpiration),
+		DefaultPartitionExpirationMs: durationToMillis(o.DefaultPartitionExpiration),
+	}
+}
+
+// patch returns the dataset for patch operation which contains only changed settings.
+func (o DatasetOption) patch(current *SDK.Dataset) (*SDK.Dataset, bool) {
+	patch := &SDK.Dataset{}
+	changed := false
+	if o.Description != "" && o.Description != current.Description {
+		patch.Description = o.Description
+		changed = true
+	}
+	if o.FriendlyName != "" && o.FriendlyName != current.FriendlyName {
+		patch.FriendlyName = o.FriendlyName
+		changed = true
+	}
+	if labels, ok := diffLabels(current.Labels, o.Labels); ok {
+		patch.Labels = labels
+		changed = true
+	}
+	if ms := durationToMillis(o.DefaultTableExpiration); ms != 0 && ms != current.DefaultTableExpirationMs {
+		patch.DefaultTableExpirationMs = ms
+		changed = true
+	}
+	if ms := durationToMillis(o.DefaultPartitionExpiration); ms != 0 && ms != current.DefaultPartitionExpirationMs {
+		patch.DefaultPartitionExpirationMs = ms
+		changed = true
+	}
+	return patch, changed
+}
+
+// Ensure creates the dataset when it does not exist.
+// It returns true when the dataset is created or updated.
+func (ds *DatasetAPI) Ensure(opt DatasetOption) (changed bool, err error) {
+	return ds.EnsureWithContext(context.Background(), opt)
+}
+
+// EnsureWithContext creates the dataset when it does not exist with the given context.
+// Already Exists error caused by other processes is treated as success.
+func (ds *DatasetAPI) EnsureWithContext(ctx context.Context, opt DatasetOption) (changed bool, err error) {
+	cli := ds.client
+	current, err := ds.GetWithContext(ctx)
+	switch {
+	case err == nil:
+		// dataset exists.
+	case !isNotFound(err):
+		return false, err
+	default:
+		ref := &SDK.DatasetReference{
+			ProjectId: cli.projectID,
+			DatasetId: ds.datasetID,
+		}
+		_, err = cli.CreateDatasetWithContext(ctx, opt.toSDK(ref))
+		switch {
+		case err == nil:
+			return true, nil
+		case isAlreadyExists(err):
+			// created by others.
+			return false, nil
+		}
+		return false, err
+	}
+
+	if !opt.Reconcile {
+		return false, nil
+	}
+	patch, changed := opt.patch(current.Dataset)
+	if !changed {
+		return false, nil
+	}
+	if _, err := cli.PatchDatasetIfMatchWithContext(ctx, ds.datasetID, patch, current.Etag); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// EnsureTableOption is settings of the table used for Ensure.
+type EnsureTableOption struct {
+	TableOption
+
+	// Reconcile updates description, friendly name, labels and expiration time of the existing table
+	// when they are different from the option.
+	// Empty settings are not compared, and labels are added or updated but not removed.
+	Reconcile bool
+	// SyncSchema applies additive schema changes to the existing table. see SyncSchema.
+	SyncSchema bool
+}
+
+func (o EnsureTableOption) patch(current *SDK.Table) (*SDK.Table, bool) {
+	patch := &SDK.Table{}
+	if !o.Reconcile {
+		return patch, false
+	}
+
+	changed := false
+	if o.Description != "" && o.Description != current.Description {
+		patch.Description = o.Description
+		changed = true
+	}
+	if o.FriendlyName != "" && o.FriendlyName != current.FriendlyName {
+		patch.FriendlyName = o.FriendlyName
+		changed = true
+	}
+	if labels, ok := diffLabels(current.Labels, o.Labels); ok {
+		patch.Labels = labels
+		changed = true
+	}
+	if !o.ExpirationTime.IsZero() {
+		if ms := o.ExpirationTime.UnixNano() / int64(time.Millisecond); ms != current.ExpirationTime {
+			patch.ExpirationTime = ms
+			changed = true
+		}
+	}
+	return patch, changed
+}
+
+// Ensure creates the table with schema defined from given struct when it does not exist.
+// It returns true when the table is created or updated.
+func (t *TableAPI) Ensure(schemaStruct interface{}, opt EnsureTableOption) (changed bool, err error) {
+	return t.EnsureWithContext(context.Background(), schemaStruct, opt)
+}
+
+// EnsureWithContext creates the table when it does not exist with the given context.
+// Already Exists error caused by other processes is treated as success.
+// When SyncSchema is set and the schema has incompatible changes, additive changes are applied and *IncompatibleSchemaError is returned.
+func (t *TableAPI) EnsureWithContext(ctx context.Context, schemaStruct interface{}, opt EnsureTableOption) (changed bool, err error) {
+	current, err := t.GetWithContext(ctx)
+	switch {
+	case err == nil:
+		// table exists.
+	case !isNotFound(err):
+		return false, err
+	default:
+		err = t.CreateWithOption(ctx, schemaStruct, opt.TableOption)
+		switch {
+		case err == nil:
+			return true, nil
+		case isAlreadyExists(err):
+			// created by others.
+			return false, nil
+		}
+		return false, err
+	}
+
+	patch, changed := opt.patch(current.Table)
+	var diff *SchemaDiff
+	if opt.SyncSchema {
+		schema, err := convertToSchema(schemaStruct)
+		if err != nil {
+			return false, err
+		}
+		diff = DiffSchema(current.Schema, schema)
+		if diff.HasAdditive() {
+			patch.Schema = diff.Schema
+			changed = true
+		}
+	}
+
+	if changed {
+		cli := t.dataset.client
+		if _, err := cli.PatchTableWithContext(ctx, t.dataset.datasetID, t.tableID, patch); err != nil {
+			return false, err
+		}
+	}
+	if diff != nil && diff.HasIncompatible() {
+		return changed, &IncompatibleSchemaError{Diff: diff}
+	}
+	return changed, nil
+}
+
+// IncompatibleSchemaError is returned from TableAPI.Ensure when the table schema has incompatible changes.
+type IncompatibleSchemaError struct {
+	// Diff is the schema difference, and Diff.Incompatible has the changes which are not applied.
+	Diff *SchemaDiff
+}
+
+func (e *IncompatibleSchemaError) Error() string {
+	names := make([]string, len(e.Diff.Incompatible))
+	for i, c := range e.Diff.Incompatible {
+		names[i] = fmt.Sprintf("%s(%s)", c.Name, c.Type)
+	}
+	return fmt.Sprintf("the table schema has incompatible changes; columns=[%s]", strings.Join(names, ", "))
+}
+
+// diffLabels returns the labels to add or update.
+func diffLabels(current, labels map[string]string) (map[string]string, bool) {
+	result := make(map[string]string, len(labels))
+	for k, v := range labels {
+		if cv, ok := current[k]; !ok || cv != v {
+			result[k] = v
+		}
+	}
+	return result, len(result) != 0
+}
+
+func durationToMillis(d time.Duration) int64 {
+	return int64(d / time.Millisecond)
+}
+
+// isNotFound checks if the error is Not Found error of the API.
+func isNotFound(err error) bool {
+	apiErr, ok := err.(*googleapi.Error)
+	return ok && apiErr.Code == http.StatusNotFound
+}
+
+func isAlreadyExists(err error) bool {
+	apiErr, ok := err.(*googleapi.Error)
+	return ok && apiErr.Code == http.StatusConflict
+}
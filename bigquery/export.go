@@ -0,0 +1,295 @@
+package bigquery
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"math/big"
+	"strconv"
+	"time"
+
+	"cloud.google.com/go/civil"
+	SDK "google.golang.org/api/bigquery/v2"
+)
+
+const (
+	layoutExportTimestamp = "2006-01-02 15:04:05.999999 UTC"
+	layoutExportDateTime  = "2006-01-02T15:04:05.999999"
+)
+
+var errExportFormat = errors.New("unsupported export format")
+
+// RowEncoder writes decoded rows into io.Writer.
+type RowEncoder interface {
+	Encode(row map[string]interface{}) error
+	// Close flushes the buffered rows and writes the footer. It does not close io.Writer.
+	Close() error
+}
+
+// NewRowEncoderFunc creates RowEncoder for the schema of the rows.
+type NewRowEncoderFunc func(w io.Writer, schema *SDK.TableSchema) (RowEncoder, error)
+
+// ExportOption is optional parameters used for exporting rows into io.Writer.
+type ExportOption struct {
+	// Format is the output format, FormatCSV or FormatJSON. (default: CSV)
+	// For Parquet, use NewEncoder of bigquery/export/parquet package.
+	Format string
+	// NewEncoder creates the encoder of the custom format. Format is ignored when it's set.
+	NewEncoder NewRowEncoderFunc
+
+	// for CSV
+	NoHeader       bool
+	FieldDelimiter rune // (default: ',')
+
+	// PageOption is used for fetching rows.
+	PageOption PageOption
+}
+
+func (o ExportOption) getFormat() string {
+	if o.Format != "" {
+		return o.Format
+	}
+	return FormatCSV
+}
+
+// ExportQueryResults writes the results of the query job into w.
+// Rows are fetched page by page, so all of the rows are not loaded into memory at once.
+// It returns the number of written rows.
+func (b *BigQuery) ExportQueryResults(ctx context.Context, jobID string, w io.Writer, opt ExportOption) (int64, error) {
+	return ExportRows(w, b.QueryResultsIterator(ctx, jobID, opt.PageOption), opt)
+}
+
+// Export writes the results of the query into w.
+// It returns the number of written rows.
+func (r *QueryResponse) Export(ctx context.Context, w io.Writer, opt ExportOption) (int64, error) {
+	if r.JobReference == nil {
+		return 0, errors.New("the query response has no job reference")
+	}
+	if opt.PageOption.Location == "" {
+		opt.PageOption.Location = r.JobReference.Location
+	}
+	return r.client.ExportQueryResults(ctx, r.JobReference.JobId, w, opt)
+}
+
+// Export writes all of the rows in the table into w.
+// It returns the number of written rows.
+func (t *TableAPI) Export(ctx context.Context, w io.Writer, opt ExportOption) (int64, error) {
+	return ExportRows(w, t.RowsIterator(ctx, opt.PageOption), opt)
+}
+
+// ExportRows writes the rows of the iterator into w by the format.
+// It returns the number of written rows.
+func ExportRows(w io.Writer, it *RowIterator, opt ExportOption) (int64, error) {
+	var enc RowEncoder
+	var n int64
+	for it.Next() {
+		if enc == nil {
+			var err error
+			enc, err = newRowEncoder(w, it.Schema(), opt)
+			if err != nil {
+				return n, err
+			}
+		}
+
+		row, err := it.Map()
+		if err != nil {
+			return n, err
+		}
+		if err := enc.Encode(row); err != nil {
+			return n, err
+		}
+		n++
+	}
+	if err := it.Err(); err != nil {
+		return n, err
+	}
+
+	if enc == nil {
+		// no rows. write header or empty file by the schema.
+		if it.Schema() == nil {
+			return 0, nil
+		}
+		var err error
+		enc, err = newRowEncoder(w, it.Schema(), opt)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return n, enc.Close()
+}
+
+func newRowEncoder(w io.Writer, schema *SDK.TableSchema, opt ExportOption) (RowEncoder, error) {
+	if opt.NewEncoder != nil {
+		return opt.NewEncoder(w, schema)
+	}
+
+	columns := newColumnTypes(schema.Fields)
+	switch opt.getFormat() {
+	case FormatCSV:
+		return newCSVEncoder(w, columns, opt)
+	case FormatJSON:
+		return newJSONEncoder(w, columns), nil
+	}
+	return nil, errExportFormat
+}
+
+// csvEncoder writes rows as CSV. RECORD and REPEATED columns are written as JSON text.
+type csvEncoder struct {
+	w       *csv.Writer
+	columns []columnType
+	record  []string
+}
+
+func newCSVEncoder(w io.Writer, columns []columnType, opt ExportOption) (*csvEncoder, error) {
+	e := &csvEncoder{
+		w:       csv.NewWriter(w),
+		columns: columns,
+		record:  make([]string, len(columns)),
+	}
+	if opt.FieldDelimiter != 0 {
+		e.w.Comma = opt.FieldDelimiter
+	}
+
+	if !opt.NoHeader {
+		for i, c := range columns {
+			e.record[i] = c.Name
+		}
+		if err := e.w.Write(e.record); err != nil {
+			return nil, err
+		}
+	}
+	return e, nil
+}
+
+func (e *csvEncoder) Encode(row map[string]interface{}) error {
+	for i, c := range e.columns {
+		s, err := csvValue(c.exportValue(row[c.Name]))
+		if err != nil {
+			return err
+		}
+		e.record[i] = s
+	}
+	return e.w.Write(e.record)
+}
+
+func (e *csvEncoder) Close() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+func csvValue(v interface{}) (string, error) {
+	switch vv := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return vv, nil
+	case int64:
+		return strconv.FormatInt(vv, 10), nil
+	case float64:
+		return strconv.FormatFloat(vv, 'g', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(vv), nil
+	}
+
+	byt, err := json.Marshal(v)
+	return string(byt), err
+}
+
+// jsonEncoder writes rows as newline-delimited JSON.
+type jsonEncoder struct {
+	w       *bufio.Writer
+	enc     *json.Encoder
+	columns []columnType
+}
+
+func newJSONEncoder(w io.Writer, columns []columnType) *jsonEncoder {
+	bw := bufio.NewWriter(w)
+	return &jsonEncoder{
+		w:       bw,
+		enc:     json.NewEncoder(bw),
+		columns: columns,
+	}
+}
+
+func (e *jsonEncoder) Encode(row map[string]interface{}) error {
+	return e.enc.Encode(exportRecord(e.columns, row))
+}
+
+func (e *jsonEncoder) Close() error {
+	return e.w.Flush()
+}
+
+// exportRecord converts the decoded row into the value for JSON encoding.
+func exportRecord(columns []columnType, row map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(columns))
+	for _, c := range columns {
+		v, ok := row[c.Name]
+		if !ok {
+			continue
+		}
+		result[c.Name] = c.exportValue(v)
+	}
+	return result
+}
+
+// exportValue converts the decoded value into the value for JSON encoding.
+func (c columnType) exportValue(v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+
+	if c.IsRepeated() {
+		list, ok := v.([]interface{})
+		if !ok {
+			return nil
+		}
+		single := c
+		single.Mode = ""
+		results := make([]interface{}, len(list))
+		for i, elem := range list {
+			results[i] = single.exportValue(elem)
+		}
+		return results
+	}
+
+	if c.IsRecord() {
+		row, ok := v.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		return exportRecord(c.Fields, row)
+	}
+	return formatExportScalar(c, v)
+}
+
+// formatExportScalar formats the value in the same format as BigQuery export.
+func formatExportScalar(c columnType, v interface{}) interface{} {
+	switch vv := v.(type) {
+	case time.Time:
+		switch {
+		case c.IsDate():
+			return vv.Format(layoutDate)
+		case c.IsDateTime():
+			return vv.Format(layoutExportDateTime)
+		}
+		return vv.UTC().Format(layoutExportTimestamp)
+	case civil.Time:
+		return civilTimeString(vv)
+	case *big.Rat:
+		return decimalString(vv)
+	case []byte:
+		return base64.StdEncoding.EncodeToString(vv)
+	}
+	return v
+}
+
+// FormatExportValue formats the decoded value of the column in the same format as BigQuery export.
+// (e.g. TIMESTAMP is "2006-01-02 15:04:05.999999 UTC", BYTES is base64 string)
+// RECORD and REPEATED values are formatted recursively. This is used for custom RowEncoder.
+func FormatExportValue(f *SDK.TableFieldSchema, v interface{}) interface{} {
+	return newColumnType(f).exportValue(v)
+}
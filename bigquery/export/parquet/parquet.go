@@ -0,0 +1,169 @@
+// Package parquet provides the encoder to export BigQuery rows as Parquet.
+//
+//	n, err := cli.ExportQueryResults(ctx, jobID, w, bigquery.ExportOption{
+//		NewEncoder: parquet.NewEncoder,
+//	})
+package parquet
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/civil"
+	"github.com/xitongsys/parquet-go/writer"
+	SDK "google.golang.org/api/bigquery/v2"
+
+	"github.com/evalphobia/google-api-go-wrapper/bigquery"
+)
+
+const parquetConcurrency = 4
+
+// Encoder writes rows as Parquet.
+// TIMESTAMP and DATE are written as logical types,
+// and NUMERIC, DATETIME, TIME, BYTES(base64) and JSON are written as UTF8 string.
+type Encoder struct {
+	w      *writer.JSONWriter
+	fields []*SDK.TableFieldSchema
+}
+
+// NewEncoder creates Encoder for the schema. It can be used as bigquery.ExportOption.NewEncoder.
+func NewEncoder(w io.Writer, schema *SDK.TableSchema) (bigquery.RowEncoder, error) {
+	root, err := json.Marshal(schemaNode{
+		Tag:    "name=root, repetitiontype=REQUIRED",
+		Fields: newSchemaNodes(schema.Fields),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	pw, err := writer.NewJSONWriterFromWriter(string(root), w, parquetConcurrency)
+	if err != nil {
+		return nil, err
+	}
+	return &Encoder{
+		w:      pw,
+		fields: schema.Fields,
+	}, nil
+}
+
+// Encode writes the decoded row.
+func (e *Encoder) Encode(row map[string]interface{}) error {
+	byt, err := json.Marshal(record(e.fields, row))
+	if err != nil {
+		return err
+	}
+	return e.w.Write(string(byt))
+}
+
+// Close writes the buffered rows and the footer. It does not close io.Writer.
+func (e *Encoder) Close() error {
+	return e.w.WriteStop()
+}
+
+// schemaNode is JSON schema definition of parquet-go.
+type schemaNode struct {
+	Tag    string
+	Fields []schemaNode `json:",omitempty"`
+}
+
+func newSchemaNodes(fields []*SDK.TableFieldSchema) []schemaNode {
+	list := make([]schemaNode, len(fields))
+	for i, f := range fields {
+		list[i] = newSchemaNode(f)
+	}
+	return list
+}
+
+func newSchemaNode(f *SDK.TableFieldSchema) schemaNode {
+	repetition := "OPTIONAL"
+	switch strings.ToUpper(f.Mode) {
+	case "REPEATED":
+		repetition = "REPEATED"
+	case "REQUIRED":
+		repetition = "REQUIRED"
+	}
+
+	tag := fmt.Sprintf("name=%s, repetitiontype=%s", f.Name, repetition)
+	switch strings.ToUpper(f.Type) {
+	case "RECORD", "STRUCT":
+		return schemaNode{
+			Tag:    tag,
+			Fields: newSchemaNodes(f.Fields),
+		}
+	case "INTEGER", "INT64":
+		tag += ", type=INT64"
+	case "FLOAT", "FLOAT64":
+		tag += ", type=DOUBLE"
+	case "BOOLEAN", "BOOL":
+		tag += ", type=BOOLEAN"
+	case "TIMESTAMP":
+		tag += ", type=INT64, convertedtype=TIMESTAMP_MICROS"
+	case "DATE":
+		tag += ", type=INT32, convertedtype=DATE"
+	default:
+		tag += ", type=BYTE_ARRAY, convertedtype=UTF8"
+	}
+	return schemaNode{
+		Tag: tag,
+	}
+}
+
+// record converts the decoded row into the value for the parquet schema.
+func record(fields []*SDK.TableFieldSchema, row map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(fields))
+	for _, f := range fields {
+		v, ok := row[f.Name]
+		if !ok {
+			continue
+		}
+		result[f.Name] = value(f, v)
+	}
+	return result
+}
+
+func value(f *SDK.TableFieldSchema, v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+
+	if strings.ToUpper(f.Mode) == "REPEATED" {
+		list, ok := v.([]interface{})
+		if !ok {
+			return nil
+		}
+		single := *f
+		single.Mode = ""
+		results := make([]interface{}, len(list))
+		for i, elem := range list {
+			results[i] = value(&single, elem)
+		}
+		return results
+	}
+
+	switch strings.ToUpper(f.Type) {
+	case "RECORD", "STRUCT":
+		row, ok := v.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		return record(f.Fields, row)
+	case "TIMESTAMP":
+		if t, ok := v.(time.Time); ok {
+			return t.UnixNano() / int64(time.Microsecond)
+		}
+	case "DATE":
+		if t, ok := v.(time.Time); ok {
+			return civil.DateOf(t).DaysSince(civil.Date{Year: 1970, Month: time.January, Day: 1})
+		}
+	case "JSON":
+		byt, err := json.Marshal(v)
+		if err != nil {
+			return nil
+		}
+		return string(byt)
+	}
+	return bigquery.FormatExportValue(f, v)
+}
@@ -0,0 +1,46 @@
+package bigquery
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/civil"
+	SDK "google.golang.org/api/bigquery/v2"
+)
+
+func TestFormatExportValue(t *testing.T) {
+	bigNumeric, _ := new(big.Rat).SetString("0.12345678901234567890123456789012345678")
+	ts := time.Date(2020, 1, 2, 3, 4, 5, 123456000, time.UTC)
+
+	tests := []struct {
+		name  string
+		field *SDK.TableFieldSchema
+		value interface{}
+		want  interface{}
+	}{
+		{"null", &SDK.TableFieldSchema{Type: "STRING"}, nil, nil},
+		{"string", &SDK.TableFieldSchema{Type: "STRING"}, "a", "a"},
+		{"numeric", &SDK.TableFieldSchema{Type: "NUMERIC"}, big.NewRat(3, 2), "1.5"},
+		{"bignumeric", &SDK.TableFieldSchema{Type: "BIGNUMERIC"}, bigNumeric, "0.12345678901234567890123456789012345678"},
+		{"bytes", &SDK.TableFieldSchema{Type: "BYTES"}, []byte("abc"), "YWJj"},
+		{"timestamp", &SDK.TableFieldSchema{Type: "TIMESTAMP"}, ts, "2020-01-02 03:04:05.123456 UTC"},
+		{"date", &SDK.TableFieldSchema{Type: "DATE"}, ts, "2020-01-02"},
+		{"datetime", &SDK.TableFieldSchema{Type: "DATETIME"}, ts, "2020-01-02T03:04:05.123456"},
+		{"time", &SDK.TableFieldSchema{Type: "TIME"}, civil.Time{Hour: 3, Minute: 4, Second: 5, Nanosecond: 1000}, "03:04:05.000001"},
+		{"repeated", &SDK.TableFieldSchema{Type: "NUMERIC", Mode: "REPEATED"}, []interface{}{big.NewRat(1, 4), nil}, []interface{}{"0.25", nil}},
+		{"record", &SDK.TableFieldSchema{Type: "RECORD", Fields: []*SDK.TableFieldSchema{
+			{Name: "a", Type: "BYTES"},
+			{Name: "b", Type: "STRING"},
+		}}, map[string]interface{}{"a": []byte("abc")}, map[string]interface{}{"a": "YWJj"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatExportValue(tt.field, tt.value); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package bigquery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	SDK "google.golang.org/api/bigquery/v2"
+)
+
+var (
+	errExtractDestination = errors.New("DestinationURIs is required for extract job")
+	errNoDestinationTable = errors.New("the job has no destination table")
+)
+
+// ExtractOption is optional parameters used for extract job.
+type ExtractOption struct {
+	// DestinationURIs are Cloud Storage URIs of the output files. (e.g. gs://bucket/path/file-*.csv)
+	// Use a wildcard `*` to export the table larger than 1GB into multiple files.
+	DestinationURIs []string
+
+	// DestinationFormat is the format of the output files. (default: CSV)
+	DestinationFormat string
+	// Compression is the compression type of the output files. (default: NONE)
+	Compression string
+
+	// for CSV
+	FieldDelimiter string
+	NoHeader       bool
+
+	// for Avro
+	UseAvroLogicalTypes bool
+
+	Location string
+	Labels   map[string]string
+}
+
+func (o ExtractOption) toJob(src *SDK.TableReference) (*SDK.Job, error) {
+	if len(o.DestinationURIs) == 0 {
+		return nil, errExtractDestination
+	}
+
+	extract := &SDK.JobConfigurationExtract{
+		SourceTable:         src,
+		DestinationUris:     o.DestinationURIs,
+		DestinationFormat:   o.DestinationFormat,
+		Compression:         o.Compression,
+		FieldDelimiter:      o.FieldDelimiter,
+		UseAvroLogicalTypes: o.UseAvroLogicalTypes,
+	}
+	if o.NoHeader {
+		printHeader := false
+		extract.PrintHeader = &printHeader
+	}
+
+	return &SDK.Job{
+		Configuration: &SDK.JobConfiguration{
+			Extract: extract,
+			Labels:  o.Labels,
+		},
+		JobReference: &SDK.JobReference{
+			ProjectId: src.ProjectId,
+			Location:  o.Location,
+		},
+	}, nil
+}
+
+// Extract exports the table into Cloud Storage and waits for the job completion.
+// It returns the URIs of the exported files.
+func (t *TableAPI) Extract(opt ExtractOption) ([]string, error) {
+	return t.ExtractWithContext(context.Background(), opt)
+}
+
+// ExtractWithContext exports the table into Cloud Storage with the given context.
+func (t *TableAPI) ExtractWithContext(ctx context.Context, opt ExtractOption) ([]string, error) {
+	return t.dataset.client.extract(ctx, t.tableReference(), opt)
+}
+
+// ExtractDestination exports the destination table of the query job into Cloud Storage and waits for the job completion.
+// The query job must be finished, and the temporary table of the query results is available for about 24 hours.
+// It returns the URIs of the exported files.
+func (j *Job) ExtractDestination(ctx context.Context, opt ExtractOption) ([]string, error) {
+	if j.Configuration == nil {
+		if err := j.Refresh(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if j.Configuration.Query == nil || j.Configuration.Query.DestinationTable == nil {
+		return nil, errNoDestinationTable
+	}
+
+	if opt.Location == "" {
+		opt.Location = j.Location()
+	}
+	return j.client.extract(ctx, j.Configuration.Query.DestinationTable, opt)
+}
+
+func (b *BigQuery) extract(ctx context.Context, src *SDK.TableReference, opt ExtractOption) ([]string, error) {
+	req, err := opt.toJob(src)
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := b.StartJobWithContext(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if err := job.Wait(ctx); err != nil {
+		return nil, err
+	}
+	return job.DestinationURIs(), nil
+}
+
+// DestinationURIs returns the URIs of the files exported by the extract job.
+// The wildcard `*` in the URI is replaced with 12-digit number, starting from 000000000000.
+func (j *Job) DestinationURIs() []string {
+	if j.Configuration == nil || j.Configuration.Extract == nil {
+		return nil
+	}
+
+	uris := j.Configuration.Extract.DestinationUris
+	if len(uris) == 0 && j.Configuration.Extract.DestinationUri != "" {
+		uris = []string{j.Configuration.Extract.DestinationUri}
+	}
+
+	var counts []int64
+	if j.Statistics != nil && j.Statistics.Extract != nil {
+		counts = j.Statistics.Extract.DestinationUriFileCounts
+	}
+
+	var list []string
+	for i, uri := range uris {
+		if !strings.Contains(uri, "*") {
+			list = append(list, uri)
+			continue
+		}
+		if i >= len(counts) {
+			continue
+		}
+		for n := int64(0); n < counts[i]; n++ {
+			list = append(list, strings.Replace(uri, "*", fmt.Sprintf("%012d", n), 1))
+		}
+	}
+	return list
+}
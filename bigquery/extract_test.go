@@ -0,0 +1,97 @@
+package bigquery
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	SDK "google.golang.org/api/bigquery/v2"
+)
+
+func TestTableAPIExtract(t *testing.T) {
+	srv := &jobTestServer{
+		states: []string{JobStateDone},
+		statistics: &SDK.JobStatistics{
+			Extract: &SDK.JobStatistics4{DestinationUriFileCounts: []int64{2}},
+		},
+	}
+	tbl := newTestBigQuery(t, srv).DatasetAPI("ds").TableAPI("tbl")
+
+	uris, err := tbl.Extract(ExtractOption{
+		DestinationURIs:   []string{"gs://bucket/out-*.csv"},
+		DestinationFormat: FormatCSV,
+		Compression:       CompressionGzip,
+		NoHeader:          true,
+		Location:          "US",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	printHeader := false
+	want := &SDK.JobConfigurationExtract{
+		SourceTable:       &SDK.TableReference{ProjectId: "project", DatasetId: "ds", TableId: "tbl"},
+		DestinationUris:   []string{"gs://bucket/out-*.csv"},
+		DestinationFormat: "CSV",
+		Compression:       "GZIP",
+		PrintHeader:       &printHeader,
+	}
+	if got := srv.inserted.Configuration.Extract; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %#v, want %#v", got, want)
+	}
+	if got := srv.inserted.JobReference.Location; got != "US" {
+		t.Errorf("location: got %s, want US", got)
+	}
+
+	wantURIs := []string{"gs://bucket/out-000000000000.csv", "gs://bucket/out-000000000001.csv"}
+	if !reflect.DeepEqual(uris, wantURIs) {
+		t.Errorf("got %v, want %v", uris, wantURIs)
+	}
+}
+
+func TestJobExtractDestination(t *testing.T) {
+	srv := &jobTestServer{states: []string{JobStateDone}}
+	job := newTestBigQuery(t, srv).JobFromReference("query-job", "asia-northeast1")
+	job.Configuration = &SDK.JobConfiguration{
+		Query: &SDK.JobConfigurationQuery{
+			DestinationTable: &SDK.TableReference{ProjectId: "project", DatasetId: "_tmp", TableId: "anon"},
+		},
+	}
+
+	uris, err := job.ExtractDestination(context.Background(), ExtractOption{
+		DestinationURIs: []string{"gs://bucket/result.json"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if !reflect.DeepEqual(uris, []string{"gs://bucket/result.json"}) {
+		t.Errorf("got %v", uris)
+	}
+
+	// the destination table of the query is exported in the location of the query job.
+	got := srv.inserted.Configuration.Extract.SourceTable
+	if got.DatasetId != "_tmp" || got.TableId != "anon" {
+		t.Errorf("source table: got %#v", got)
+	}
+	if loc := srv.inserted.JobReference.Location; loc != "asia-northeast1" {
+		t.Errorf("location: got %s, want asia-northeast1", loc)
+	}
+}
+
+func TestExtractError(t *testing.T) {
+	srv := &jobTestServer{}
+	b := newTestBigQuery(t, srv)
+
+	if _, err := b.DatasetAPI("ds").TableAPI("tbl").Extract(ExtractOption{}); err != errExtractDestination {
+		t.Errorf("got %v, want %v", err, errExtractDestination)
+	}
+
+	job := b.JobFromReference("job-1", "US")
+	job.Configuration = &SDK.JobConfiguration{Load: &SDK.JobConfigurationLoad{}}
+	if _, err := job.ExtractDestination(context.Background(), ExtractOption{DestinationURIs: []string{"gs://bucket/a"}}); err != errNoDestinationTable {
+		t.Errorf("got %v, want %v", err, errNoDestinationTable)
+	}
+	if len(srv.requests) != 0 {
+		t.Errorf("got %v, want no request", srv.requests)
+	}
+}
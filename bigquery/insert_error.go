@@ -0,0 +1,95 @@
+package bigquery
+
+import (
+	"fmt"
+	"strings"
+
+	SDK "google.golang.org/api/bigquery/v2"
+)
+
+// unknownRowIndex is used for RowError whose index in the response is out of range of the rows.
+const unknownRowIndex = -1
+
+// retryable reasons of insert errors.
+// see: https://cloud.google.com/bigquery/docs/error-messages
+var retryableInsertReasons = map[string]bool{
+	"backendError":  true,
+	"internalError": true,
+	"stopped":       true,
+	"timeout":       true,
+}
+
+// RowError is an error of the row on InsertAll operation.
+type RowError struct {
+	// Index is the index of the row in the given data.
+	// It is -1 when the index in the response is out of range of the rows.
+	Index    int64
+	Reason   string
+	Location string
+	Message  string
+}
+
+func (e RowError) Error() string {
+	return fmt.Sprintf("index=[%d] reason=[%s] location=[%s] message=[%s]", e.Index, e.Reason, e.Location, e.Message)
+}
+
+// IsRetryable checks if the row can be retried.
+func (e RowError) IsRetryable() bool {
+	return retryableInsertReasons[e.Reason]
+}
+
+// InsertAllError is returned when some of rows are failed on InsertAll operation.
+type InsertAllError struct {
+	RowErrors []RowError
+}
+
+func (e *InsertAllError) Error() string {
+	msgs := make([]string, len(e.RowErrors))
+	for i, rowErr := range e.RowErrors {
+		msgs[i] = rowErr.Error()
+	}
+	return fmt.Sprintf("error occured on bigquery.InsertAll; failed=[%d] errors=[%s]", len(e.RowErrors), strings.Join(msgs, ", "))
+}
+
+// FailedIndexes returns the list of the row index which is failed.
+// It contains -1 when some of the failed rows cannot be identified.
+func (e *InsertAllError) FailedIndexes() []int64 {
+	var list []int64
+	seen := make(map[int64]struct{}, len(e.RowErrors))
+	for _, rowErr := range e.RowErrors {
+		if _, ok := seen[rowErr.Index]; ok {
+			continue
+		}
+		seen[rowErr.Index] = struct{}{}
+		list = append(list, rowErr.Index)
+	}
+	return list
+}
+
+// newRowErrors converts errors of the row in the response.
+func newRowErrors(index int64, errs []*SDK.ErrorProto) []RowError {
+	if len(errs) == 0 {
+		return []RowError{{Index: index}}
+	}
+
+	list := make([]RowError, len(errs))
+	for i, e := range errs {
+		list[i] = RowError{
+			Index:    index,
+			Reason:   e.Reason,
+			Location: e.Location,
+			Message:  e.Message,
+		}
+	}
+	return list
+}
+
+// isRetryableRowErrors checks if all of the errors of the row are retryable.
+func isRetryableRowErrors(errs []RowError) bool {
+	for _, e := range errs {
+		if !e.IsRetryable() {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,312 @@
+package bigquery
+
+import (
+	"context"
+
+	SDK "google.golang.org/api/bigquery/v2"
+)
+
+// pageIterator follows page tokens and counts the items of the current page.
+type pageIterator struct {
+	ctx     context.Context
+	opt     PageOption
+	started bool
+	err     error
+
+	index int // index of the next item in the current page.
+	size  int // number of items in the current page.
+	count int64
+
+	// fetch gets the page for the given option and returns next page token and number of items.
+	fetch func(ctx context.Context, opt PageOption) (nextPageToken string, size int, err error)
+}
+
+// next moves the cursor to the next item and fetches the next page when needed.
+func (p *pageIterator) next() bool {
+	if p.err != nil {
+		return false
+	}
+	if p.opt.MaxRows > 0 && p.count >= p.opt.MaxRows {
+		return false
+	}
+
+	for p.index >= p.size {
+		if p.started && p.opt.PageToken == "" {
+			return false // no more pages.
+		}
+		if err := p.ctx.Err(); err != nil {
+			p.err = err
+			return false
+		}
+
+		token, size, err := p.fetch(p.ctx, p.opt)
+		if err != nil {
+			p.err = err
+			return false
+		}
+		p.started = true
+		p.opt.PageToken = token
+		p.opt.StartIndex = 0 // StartIndex is only used on the first page.
+		p.index = 0
+		p.size = size
+	}
+
+	p.index++
+	p.count++
+	return true
+}
+
+// current returns index of the current item in the current page.
+func (p *pageIterator) current() int {
+	return p.index - 1
+}
+
+// Err returns the first error occured during iteration.
+func (p *pageIterator) Err() error {
+	return p.err
+}
+
+// PageToken returns the token of the next page.
+// This can be used to resume iteration by PageOption.PageToken.
+func (p *pageIterator) PageToken() string {
+	return p.opt.PageToken
+}
+
+func newPageIterator(ctx context.Context, opt PageOption) pageIterator {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return pageIterator{
+		ctx: ctx,
+		opt: opt,
+	}
+}
+
+// DatasetIterator iterates datasets in the project.
+type DatasetIterator struct {
+	pageIterator
+	items []*SDK.DatasetListDatasets
+}
+
+// DatasetsIterator returns initialized DatasetIterator.
+func (b *BigQuery) DatasetsIterator(ctx context.Context, opt PageOption) *DatasetIterator {
+	it := &DatasetIterator{
+		pageIterator: newPageIterator(ctx, opt),
+	}
+	it.fetch = func(ctx context.Context, opt PageOption) (string, int, error) {
+		list, err := b.ListDatasetsPageWithContext(ctx, opt)
+		if err != nil {
+			return "", 0, err
+		}
+		it.items = list.Datasets
+		return list.NextPageToken, len(list.Datasets), nil
+	}
+	return it
+}
+
+// Next moves to the next dataset and returns false when the iteration is finished.
+func (it *DatasetIterator) Next() bool {
+	return it.next()
+}
+
+// Dataset returns the current dataset.
+func (it *DatasetIterator) Dataset() *SDK.DatasetListDatasets {
+	return it.items[it.current()]
+}
+
+// JobIterator iterates jobs in the project.
+type JobIterator struct {
+	pageIterator
+	items []*SDK.JobListJobs
+}
+
+// JobsIterator returns initialized JobIterator.
+func (b *BigQuery) JobsIterator(ctx context.Context, opt PageOption) *JobIterator {
+	it := &JobIterator{
+		pageIterator: newPageIterator(ctx, opt),
+	}
+	it.fetch = func(ctx context.Context, opt PageOption) (string, int, error) {
+		list, err := b.ListJobsPageWithContext(ctx, opt)
+		if err != nil {
+			return "", 0, err
+		}
+		it.items = list.Jobs
+		return list.NextPageToken, len(list.Jobs), nil
+	}
+	return it
+}
+
+// Next moves to the next job and returns false when the iteration is finished.
+func (it *JobIterator) Next() bool {
+	return it.next()
+}
+
+// Job returns the current job.
+func (it *JobIterator) Job() *SDK.JobListJobs {
+	return it.items[it.current()]
+}
+
+// TableIterator iterates tables in the dataset.
+type TableIterator struct {
+	pageIterator
+	items []*SDK.TableListTables
+}
+
+// TablesIterator returns initialized TableIterator.
+func (b *BigQuery) TablesIterator(ctx context.Context, datasetID string, opt PageOption) *TableIterator {
+	it := &TableIterator{
+		pageIterator: newPageIterator(ctx, opt),
+	}
+	it.fetch = func(ctx context.Context, opt PageOption) (string, int, error) {
+		list, err := b.ListTablesPageWithContext(ctx, datasetID, opt)
+		if err != nil {
+			return "", 0, err
+		}
+		it.items = list.Tables
+		return list.NextPageToken, len(list.Tables), nil
+	}
+	return it
+}
+
+// Next moves to the next table and returns false when the iteration is finished.
+func (it *TableIterator) Next() bool {
+	return it.next()
+}
+
+// Table returns the current table.
+func (it *TableIterator) Table() *SDK.TableListTables {
+	return it.items[it.current()]
+}
+
+// RoutineIterator iterates routines in the dataset.
+type RoutineIterator struct {
+	pageIterator
+	items []*SDK.Routine
+}
+
+// RoutinesIterator returns initialized RoutineIterator.
+// Routines.List returns only the metadata, and the definition body and arguments are not contained.
+func (b *BigQuery) RoutinesIterator(ctx context.Context, datasetID string, opt PageOption) *RoutineIterator {
+	it := &RoutineIterator{
+		pageIterator: newPageIterator(ctx, opt),
+	}
+	it.fetch = func(ctx context.Context, opt PageOption) (string, int, error) {
+		list, err := b.ListRoutinesPageWithContext(ctx, datasetID, opt)
+		if err != nil {
+			return "", 0, err
+		}
+		it.items = list.Routines
+		return list.NextPageToken, len(list.Routines), nil
+	}
+	return it
+}
+
+// Next moves to the next routine and returns false when the iteration is finished.
+func (it *RoutineIterator) Next() bool {
+	return it.next()
+}
+
+// Routine returns the current routine.
+func (it *RoutineIterator) Routine() *SDK.Routine {
+	return it.items[it.current()]
+}
+
+// RowIterator iterates rows of table data or query results.
+type RowIterator struct {
+	pageIterator
+	items     []*SDK.TableRow
+	schema    *SDK.TableSchema
+	totalRows uint64
+}
+
+// TableDataIterator returns initialized RowIterator for the table data.
+func (b *BigQuery) TableDataIterator(ctx context.Context, datasetID, tableID string, opt PageOption) *RowIterator {
+	it := &RowIterator{
+		pageIterator: newPageIterator(ctx, opt),
+	}
+	it.fetch = func(ctx context.Context, opt PageOption) (string, int, error) {
+		if it.schema == nil {
+			// Tabledata.List does not return schema.
+			tbl, err := b.GetTableWithContext(ctx, datasetID, tableID)
+			if err != nil {
+				return "", 0, err
+			}
+			it.schema = tbl.Schema
+		}
+
+		list, err := b.GetTableDataPageWithContext(ctx, datasetID, tableID, opt)
+		if err != nil {
+			return "", 0, err
+		}
+		it.items = list.Rows
+		it.totalRows = uint64(list.TotalRows)
+		return list.PageToken, len(list.Rows), nil
+	}
+	return it
+}
+
+// QueryResultsIterator returns initialized RowIterator for the query results.
+// It waits for the query job to complete before returning the first row.
+func (b *BigQuery) QueryResultsIterator(ctx context.Context, jobID string, opt PageOption) *RowIterator {
+	it := &RowIterator{
+		pageIterator: newPageIterator(ctx, opt),
+	}
+	it.fetch = func(ctx context.Context, opt PageOption) (string, int, error) {
+		bo := Backoff{
+			BaseDelay: defaultJobPollBaseDelay,
+			MaxDelay:  defaultJobPollMaxDelay,
+		}
+		for {
+			resp, err := b.GetQueryResultsPageWithContext(ctx, jobID, opt)
+			if err != nil {
+				return "", 0, err
+			}
+			if !resp.JobComplete {
+				// Jobs.GetQueryResults may return before TimeoutMs, so wait and call it again.
+				if err := SleepContext(ctx, bo.Next()); err != nil {
+					return "", 0, err
+				}
+				continue
+			}
+
+			it.items = resp.Rows
+			it.schema = resp.Schema
+			it.totalRows = resp.TotalRows
+			return resp.PageToken, len(resp.Rows), nil
+		}
+	}
+	return it
+}
+
+// Next moves to the next row and returns false when the iteration is finished.
+func (it *RowIterator) Next() bool {
+	return it.next()
+}
+
+// Row returns the current row.
+func (it *RowIterator) Row() *SDK.TableRow {
+	return it.items[it.current()]
+}
+
+// Map decodes the current row into map by the schema.
+func (it *RowIterator) Map() (map[string]interface{}, error) {
+	return DecodeRow(it.schema, it.Row())
+}
+
+// ScanStruct decodes the current row into dst by the schema.
+// dst must be a pointer of struct.
+func (it *RowIterator) ScanStruct(dst interface{}) error {
+	return ScanRow(it.schema, it.Row(), dst)
+}
+
+// Schema returns the schema of the table or query results.
+// It is available after the first call of Next.
+func (it *RowIterator) Schema() *SDK.TableSchema {
+	return it.schema
+}
+
+// TotalRows returns the total number of rows in the table or query results.
+// It is available after the first call of Next.
+func (it *RowIterator) TotalRows() uint64 {
+	return it.totalRows
+}
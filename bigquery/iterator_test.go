@@ -0,0 +1,74 @@
+package bigquery
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	SDK "google.golang.org/api/bigquery/v2"
+
+	"github.com/evalphobia/google-api-go-wrapper/config"
+)
+
+func TestQueryResultsIteratorPolling(t *testing.T) {
+	tests := []struct {
+		name       string
+		incomplete int32
+		timeout    time.Duration
+		wantRows   int
+		wantErr    bool
+		maxCalls   int32
+	}{
+		{"complete", 0, time.Minute, 2, false, 1},
+		{"incomplete once", 1, time.Minute, 2, false, 2},
+		{"cancelled while waiting", 1 << 30, 100 * time.Millisecond, 0, true, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := atomic.AddInt32(&calls, 1)
+				resp := &SDK.GetQueryResultsResponse{}
+				if n > tt.incomplete {
+					resp.JobComplete = true
+					resp.Rows = []*SDK.TableRow{{}, {}}
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_ = json.NewEncoder(w).Encode(resp)
+			}))
+			defer ts.Close()
+
+			b, err := New(config.Config{
+				Endpoint:         ts.URL + "/bigquery/v2/",
+				NoAuthentication: true,
+			}, "project")
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), tt.timeout)
+			defer cancel()
+
+			it := b.QueryResultsIterator(ctx, "job", PageOption{})
+			rows := 0
+			for it.Next() {
+				rows++
+			}
+			if rows != tt.wantRows {
+				t.Errorf("rows: got %d, want %d", rows, tt.wantRows)
+			}
+			if (it.Err() != nil) != tt.wantErr {
+				t.Errorf("Err: got %v, wantErr %v", it.Err(), tt.wantErr)
+			}
+			// the incomplete job must not be polled without waiting.
+			if got := atomic.LoadInt32(&calls); got > tt.maxCalls {
+				t.Errorf("calls: got %d, want <= %d", got, tt.maxCalls)
+			}
+		})
+	}
+}
@@ -0,0 +1,233 @@
+package bigquery
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	SDK "google.golang.org/api/bigquery/v2"
+)
+
+const (
+	defaultJobPollBaseDelay = 500 * time.Millisecond
+	defaultJobPollMaxDelay  = 10 * time.Second
+)
+
+// Job is a handle of BigQuery job.
+type Job struct {
+	*SDK.Job
+	client *BigQuery
+}
+
+func newJob(client *BigQuery, job *SDK.Job) *Job {
+	if job == nil {
+		return nil
+	}
+	return &Job{
+		Job:    job,
+		client: client,
+	}
+}
+
+// JobFromReference returns the job handle from job ID and location.
+func (b *BigQuery) JobFromReference(jobID, location string) *Job {
+	return newJob(b, &SDK.Job{
+		JobReference: &SDK.JobReference{
+			ProjectId: b.projectID,
+			JobId:     jobID,
+			Location:  location,
+		},
+	})
+}
+
+// StartJob starts the job by Jobs.Insert operation and returns the job handle.
+func (b *BigQuery) StartJob(job *SDK.Job) (*Job, error) {
+	return b.StartJobWithContext(context.Background(), job)
+}
+
+// StartJobWithContext starts the job by Jobs.Insert operation with the given context and returns the job handle.
+func (b *BigQuery) StartJobWithContext(ctx context.Context, job *SDK.Job) (*Job, error) {
+	j, err := b.RunJobWithContext(ctx, job)
+	if err != nil {
+		return nil, err
+	}
+	return newJob(b, j), nil
+}
+
+// ID returns the job ID.
+func (j *Job) ID() string {
+	if j.JobReference == nil {
+		return ""
+	}
+	return j.JobReference.JobId
+}
+
+// Location returns the location of the job.
+func (j *Job) Location() string {
+	if j.JobReference == nil {
+		return ""
+	}
+	return j.JobReference.Location
+}
+
+// State returns the last fetched state of the job.
+func (j *Job) State() string {
+	if j.Job.Status == nil {
+		return ""
+	}
+	return j.Job.Status.State
+}
+
+// IsDone checks if the last fetched state is DONE.
+func (j *Job) IsDone() bool {
+	return j.State() == JobStateDone
+}
+
+// Err returns *JobError when the job is failed.
+func (j *Job) Err() error {
+	return newJobError(j.Job)
+}
+
+// Refresh fetches the latest job information by Jobs.Get operation.
+func (j *Job) Refresh(ctx context.Context) error {
+	job, err := j.client.GetJobInLocationWithContext(ctx, j.ID(), j.Location())
+	if err != nil {
+		return err
+	}
+	j.Job = job
+	return nil
+}
+
+// GetStatus fetches and returns the latest status of the job.
+func (j *Job) GetStatus(ctx context.Context) (*SDK.JobStatus, error) {
+	if err := j.Refresh(ctx); err != nil {
+		return nil, err
+	}
+	return j.Job.Status, nil
+}
+
+// Wait polls the job status with exponential backoff until the job is done.
+// It returns *JobError when the job is failed.
+func (j *Job) Wait(ctx context.Context) error {
+	bo := Backoff{
+		BaseDelay: defaultJobPollBaseDelay,
+		MaxDelay:  defaultJobPollMaxDelay,
+	}
+
+	for !j.IsDone() {
+		if err := SleepContext(ctx, bo.Next()); err != nil {
+			return err
+		}
+		if err := j.Refresh(ctx); err != nil {
+			return err
+		}
+	}
+	return j.Err()
+}
+
+// Cancel requests to cancel the job.
+// The job may not be cancelled immediately, so use Wait to confirm the cancellation.
+func (j *Job) Cancel(ctx context.Context) error {
+	resp, err := j.client.CancelJobInLocationWithContext(ctx, j.ID(), j.Location())
+	if err != nil {
+		return err
+	}
+	if resp.Job != nil {
+		j.Job = resp.Job
+	}
+	return nil
+}
+
+// BytesProcessed returns total bytes processed by the job.
+func (j *Job) BytesProcessed() int64 {
+	if j.Statistics == nil {
+		return 0
+	}
+	if j.Statistics.TotalBytesProcessed == 0 && j.Statistics.Query != nil {
+		return j.Statistics.Query.TotalBytesProcessed
+	}
+	return j.Statistics.TotalBytesProcessed
+}
+
+// BytesBilled returns total bytes billed by the query job.
+func (j *Job) BytesBilled() int64 {
+	if j.Statistics == nil || j.Statistics.Query == nil {
+		return 0
+	}
+	return j.Statistics.Query.TotalBytesBilled
+}
+
+// SlotMillis returns total slot milliseconds consumed by the job.
+func (j *Job) SlotMillis() int64 {
+	if j.Statistics == nil {
+		return 0
+	}
+	if j.Statistics.TotalSlotMs == 0 && j.Statistics.Query != nil {
+		return j.Statistics.Query.TotalSlotMs
+	}
+	return j.Statistics.TotalSlotMs
+}
+
+// CacheHit checks if the query result is fetched from the cache.
+func (j *Job) CacheHit() bool {
+	if j.Statistics == nil || j.Statistics.Query == nil {
+		return false
+	}
+	return j.Statistics.Query.CacheHit
+}
+
+// ErrorDetail is the detail of the error in BigQuery job.
+type ErrorDetail struct {
+	Reason   string
+	Location string
+	Message  string
+}
+
+func newErrorDetail(e *SDK.ErrorProto) ErrorDetail {
+	return ErrorDetail{
+		Reason:   e.Reason,
+		Location: e.Location,
+		Message:  e.Message,
+	}
+}
+
+func (e ErrorDetail) String() string {
+	return fmt.Sprintf("reason=[%s] location=[%s] message=[%s]", e.Reason, e.Location, e.Message)
+}
+
+// JobError is returned when the job is failed.
+type JobError struct {
+	JobID string
+	// Result is the final error result of the job.
+	Result ErrorDetail
+	// Errors are all of the errors encountered during the running of the job.
+	Errors []ErrorDetail
+}
+
+func (e *JobError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, detail := range e.Errors {
+		msgs[i] = detail.String()
+	}
+	return fmt.Sprintf("error occured on bigquery job; jobID=[%s] %s errors=[%s]", e.JobID, e.Result.String(), strings.Join(msgs, ", "))
+}
+
+// newJobError returns *JobError when the job is failed.
+func newJobError(job *SDK.Job) error {
+	if job.Status == nil || job.Status.ErrorResult == nil {
+		return nil
+	}
+
+	e := &JobError{
+		Result: newErrorDetail(job.Status.ErrorResult),
+		Errors: make([]ErrorDetail, len(job.Status.Errors)),
+	}
+	if job.JobReference != nil {
+		e.JobID = job.JobReference.JobId
+	}
+	for i, detail := range job.Status.Errors {
+		e.Errors[i] = newErrorDetail(detail)
+	}
+	return e
+}
@@ -0,0 +1,261 @@
+package bigquery
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	SDK "google.golang.org/api/bigquery/v2"
+)
+
+// jobTestServer is a fake jobs API which records the requests.
+type jobTestServer struct {
+	mu sync.Mutex
+	// states are returned by Jobs.Get in order, and the last one is repeated.
+	states      []string
+	errorResult *SDK.ErrorProto
+	statistics  *SDK.JobStatistics
+
+	requests []string   // "METHOD path?query"
+	inserted *SDK.Job   // the last inserted job.
+	jobs     []*SDK.Job // all of the inserted jobs.
+	media    string
+}
+
+func (s *jobTestServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := strings.TrimPrefix(r.URL.Path, "/upload")
+	path = strings.TrimPrefix(path, "/bigquery/v2/projects/project")
+	req := r.Method + " " + path
+	if q := r.URL.Query().Get("location"); q != "" {
+		req += "?location=" + q
+	}
+	s.requests = append(s.requests, req)
+
+	switch {
+	case r.Method == http.MethodPost && path == "/jobs":
+		job, err := s.readJob(r)
+		if err != nil {
+			http.Error(w, `{"error":{"code":400,"message":"invalid body"}}`, http.StatusBadRequest)
+			return
+		}
+		if job.JobReference == nil {
+			job.JobReference = &SDK.JobReference{}
+		}
+		job.JobReference.ProjectId = "project"
+		if job.JobReference.JobId == "" {
+			job.JobReference.JobId = "job-1"
+		}
+		s.inserted = job
+		s.jobs = append(s.jobs, job)
+		json.NewEncoder(w).Encode(s.job(JobStateRunning))
+	case r.Method == http.MethodGet && strings.HasPrefix(path, "/jobs/"):
+		state := s.states[0]
+		if len(s.states) > 1 {
+			s.states = s.states[1:]
+		}
+		json.NewEncoder(w).Encode(s.job(state))
+	case r.Method == http.MethodPost && strings.HasSuffix(path, "/cancel"):
+		json.NewEncoder(w).Encode(&SDK.JobCancelResponse{Job: s.job(JobStateRunning)})
+	default:
+		http.Error(w, `{"error":{"code":404,"message":"not found"}}`, http.StatusNotFound)
+	}
+}
+
+// readJob decodes the job of Jobs.Insert request, which may be multipart with the media.
+func (s *jobTestServer) readJob(r *http.Request) (*SDK.Job, error) {
+	job := &SDK.Job{}
+	mediaType, params, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if !strings.HasPrefix(mediaType, "multipart/") {
+		err := json.NewDecoder(r.Body).Decode(job)
+		return job, err
+	}
+
+	mr := multipart.NewReader(r.Body, params["boundary"])
+	part, err := mr.NextPart()
+	if err != nil {
+		return nil, err
+	}
+	if err := json.NewDecoder(part).Decode(job); err != nil {
+		return nil, err
+	}
+
+	media, err := mr.NextPart()
+	if err != nil {
+		return nil, err
+	}
+	b, err := ioutil.ReadAll(media)
+	if err != nil {
+		return nil, err
+	}
+	s.media = string(b)
+	return job, nil
+}
+
+// job returns the inserted job with the state.
+func (s *jobTestServer) job(state string) *SDK.Job {
+	job := &SDK.Job{
+		JobReference:  &SDK.JobReference{ProjectId: "project", JobId: "job-1", Location: "US"},
+		Configuration: &SDK.JobConfiguration{},
+		Status:        &SDK.JobStatus{State: state},
+		Statistics:    s.statistics,
+	}
+	if s.inserted != nil {
+		job.JobReference = s.inserted.JobReference
+		job.Configuration = s.inserted.Configuration
+	}
+	if state == JobStateDone && s.errorResult != nil {
+		job.Status.ErrorResult = s.errorResult
+		job.Status.Errors = []*SDK.ErrorProto{s.errorResult}
+	}
+	return job
+}
+
+func TestBigQueryStartJob(t *testing.T) {
+	srv := &jobTestServer{states: []string{JobStateDone}}
+	b := newTestBigQuery(t, srv)
+
+	job, err := b.StartJob(&SDK.Job{
+		JobReference: &SDK.JobReference{JobId: "my-job", Location: "asia-northeast1"},
+		Configuration: &SDK.JobConfiguration{
+			Query: &SDK.JobConfigurationQuery{Query: "SELECT 1"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if job.ID() != "my-job" || job.Location() != "asia-northeast1" || job.State() != JobStateRunning {
+		t.Errorf("got id=[%s] location=[%s] state=[%s]", job.ID(), job.Location(), job.State())
+	}
+	if got := srv.inserted.Configuration.Query.Query; got != "SELECT 1" {
+		t.Errorf("query: got %s, want SELECT 1", got)
+	}
+
+	// RunJob returns the job resource.
+	raw, err := b.RunJob(&SDK.Job{Configuration: &SDK.JobConfiguration{}})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if raw.JobReference.JobId != "job-1" {
+		t.Errorf("got %s, want job-1", raw.JobReference.JobId)
+	}
+}
+
+func TestJobWait(t *testing.T) {
+	errorResult := &SDK.ErrorProto{Reason: "invalidQuery", Location: "query", Message: "syntax error"}
+
+	tests := []struct {
+		name         string
+		states       []string
+		errorResult  *SDK.ErrorProto
+		timeout      time.Duration
+		wantErr      error
+		wantRequests int
+	}{
+		{"done", []string{JobStateRunning, JobStateDone}, nil, 0, nil, 2},
+		{"failed", []string{JobStateDone}, errorResult, 0, &JobError{
+			JobID:  "job-1",
+			Result: ErrorDetail{Reason: "invalidQuery", Location: "query", Message: "syntax error"},
+			Errors: []ErrorDetail{{Reason: "invalidQuery", Location: "query", Message: "syntax error"}},
+		}, 1},
+		{"timeout", []string{JobStateRunning}, nil, 10 * time.Millisecond, context.DeadlineExceeded, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := &jobTestServer{states: tt.states, errorResult: tt.errorResult}
+			job := newTestBigQuery(t, srv).JobFromReference("job-1", "US")
+			job.Status = &SDK.JobStatus{State: JobStateRunning}
+
+			ctx := context.Background()
+			if tt.timeout != 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.timeout)
+				defer cancel()
+			}
+
+			err := job.Wait(ctx)
+			if !reflect.DeepEqual(err, tt.wantErr) {
+				t.Errorf("got %#v, want %#v", err, tt.wantErr)
+			}
+			if len(srv.requests) != tt.wantRequests {
+				t.Fatalf("requests: got %v, want %d requests", srv.requests, tt.wantRequests)
+			}
+			for _, req := range srv.requests {
+				if req != "GET /jobs/job-1?location=US" {
+					t.Errorf("got %s, want Jobs.Get with location", req)
+				}
+			}
+		})
+	}
+}
+
+func TestJobWaitDone(t *testing.T) {
+	srv := &jobTestServer{}
+	job := newTestBigQuery(t, srv).JobFromReference("job-1", "US")
+	job.Status = &SDK.JobStatus{State: JobStateDone}
+
+	// the job fetched as DONE is not polled.
+	if err := job.Wait(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if len(srv.requests) != 0 {
+		t.Errorf("got %v, want no request", srv.requests)
+	}
+}
+
+func TestJobCancel(t *testing.T) {
+	srv := &jobTestServer{states: []string{JobStateDone}}
+	job := newTestBigQuery(t, srv).JobFromReference("job-1", "US")
+
+	if err := job.Cancel(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if job.State() != JobStateRunning {
+		t.Errorf("state: got %s, want %s", job.State(), JobStateRunning)
+	}
+
+	// Wait confirms the cancellation.
+	if err := job.Wait(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	want := []string{
+		"POST /jobs/job-1/cancel?location=US",
+		"GET /jobs/job-1?location=US",
+	}
+	if !reflect.DeepEqual(srv.requests, want) {
+		t.Errorf("got %v, want %v", srv.requests, want)
+	}
+}
+
+func TestJobStatistics(t *testing.T) {
+	job := &Job{Job: &SDK.Job{
+		Statistics: &SDK.JobStatistics{
+			TotalSlotMs: 0,
+			Query: &SDK.JobStatistics2{
+				TotalBytesProcessed: 100,
+				TotalBytesBilled:    200,
+				TotalSlotMs:         300,
+				CacheHit:            true,
+			},
+		},
+	}}
+	if job.BytesProcessed() != 100 || job.BytesBilled() != 200 || job.SlotMillis() != 300 || !job.CacheHit() {
+		t.Errorf("got processed=%d billed=%d slot=%d cache=%v", job.BytesProcessed(), job.BytesBilled(), job.SlotMillis(), job.CacheHit())
+	}
+
+	empty := &Job{Job: &SDK.Job{}}
+	if empty.BytesProcessed() != 0 || empty.BytesBilled() != 0 || empty.SlotMillis() != 0 || empty.CacheHit() {
+		t.Errorf("got non-zero statistics from the empty job")
+	}
+}
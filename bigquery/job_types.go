@@ -0,0 +1,38 @@
+package bigquery
+
+// write dispositions.
+const (
+	WriteAppend   = "WRITE_APPEND"
+	WriteTruncate = "WRITE_TRUNCATE"
+	WriteEmpty    = "WRITE_EMPTY"
+)
+
+// create dispositions.
+const (
+	CreateIfNeeded = "CREATE_IF_NEEDED"
+	CreateNever    = "CREATE_NEVER"
+)
+
+// data formats for load and extract jobs.
+const (
+	FormatCSV     = "CSV"
+	FormatJSON    = "NEWLINE_DELIMITED_JSON"
+	FormatAvro    = "AVRO"
+	FormatParquet = "PARQUET"
+)
+
+// job states.
+const (
+	JobStatePending = "PENDING"
+	JobStateRunning = "RUNNING"
+	JobStateDone    = "DONE"
+)
+
+// compression types for extract jobs.
+const (
+	CompressionNone    = "NONE"
+	CompressionGzip    = "GZIP"
+	CompressionDeflate = "DEFLATE"
+	CompressionSnappy  = "SNAPPY"
+	CompressionZstd    = "ZSTD"
+)
@@ -0,0 +1,130 @@
+package bigquery
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	SDK "google.golang.org/api/bigquery/v2"
+)
+
+var errLoadSource = errors.New("either SourceURIs or Reader is required for load job")
+
+// LoadOption is optional parameters used for load job.
+type LoadOption struct {
+	// source data. either one is required.
+	SourceURIs []string // Cloud Storage URIs. (e.g. gs://bucket/path/*.csv)
+	Reader     io.Reader
+
+	// SourceFormat is the format of the source data. (default: CSV)
+	SourceFormat      string
+	WriteDisposition  string
+	CreateDisposition string
+
+	// Schema is struct or map to define table schema.
+	Schema     interface{}
+	Autodetect bool
+
+	// for CSV
+	SkipLeadingRows     int64
+	FieldDelimiter      string
+	AllowQuotedNewlines bool
+	AllowJaggedRows     bool
+	NullMarker          string
+	Encoding            string
+
+	MaxBadRecords       int64
+	IgnoreUnknownValues bool
+	UseAvroLogicalTypes bool
+	SchemaUpdateOptions []string
+
+	TimePartitioning  *TimePartitioning
+	RangePartitioning *RangePartitioning
+	Clustering        []string
+
+	Location string
+	Labels   map[string]string
+
+	// NoWait returns the job right after it's created.
+	NoWait bool
+}
+
+func (o LoadOption) toJob(dest *SDK.TableReference) (*SDK.Job, error) {
+	if len(o.SourceURIs) == 0 && o.Reader == nil {
+		return nil, errLoadSource
+	}
+
+	load := &SDK.JobConfigurationLoad{
+		DestinationTable:    dest,
+		SourceUris:          o.SourceURIs,
+		SourceFormat:        o.SourceFormat,
+		WriteDisposition:    o.WriteDisposition,
+		CreateDisposition:   o.CreateDisposition,
+		Autodetect:          o.Autodetect,
+		SkipLeadingRows:     o.SkipLeadingRows,
+		FieldDelimiter:      o.FieldDelimiter,
+		AllowQuotedNewlines: o.AllowQuotedNewlines,
+		AllowJaggedRows:     o.AllowJaggedRows,
+		NullMarker:          o.NullMarker,
+		Encoding:            o.Encoding,
+		MaxBadRecords:       o.MaxBadRecords,
+		IgnoreUnknownValues: o.IgnoreUnknownValues,
+		UseAvroLogicalTypes: o.UseAvroLogicalTypes,
+		SchemaUpdateOptions: o.SchemaUpdateOptions,
+		TimePartitioning:    o.TimePartitioning.toSDK(),
+		RangePartitioning:   o.RangePartitioning.toSDK(),
+		Clustering:          newClustering(o.Clustering),
+	}
+	if o.Reader != nil {
+		load.SourceUris = nil
+	}
+
+	if o.Schema != nil {
+		schema, err := convertToSchema(o.Schema)
+		if err != nil {
+			return nil, err
+		}
+		load.Schema = schema
+	}
+
+	return &SDK.Job{
+		Configuration: &SDK.JobConfiguration{
+			Load:   load,
+			Labels: o.Labels,
+		},
+		JobReference: &SDK.JobReference{
+			ProjectId: dest.ProjectId,
+			Location:  o.Location,
+		},
+	}, nil
+}
+
+// Load loads data into the table from Cloud Storage or io.Reader and waits for the job completion.
+func (t *TableAPI) Load(opt LoadOption) (*Job, error) {
+	return t.LoadWithContext(context.Background(), opt)
+}
+
+// LoadWithContext loads data into the table from Cloud Storage or io.Reader with the given context.
+func (t *TableAPI) LoadWithContext(ctx context.Context, opt LoadOption) (*Job, error) {
+	req, err := opt.toJob(t.tableReference())
+	if err != nil {
+		return nil, err
+	}
+
+	var j *SDK.Job
+	cli := t.dataset.client
+	if opt.Reader != nil {
+		j, err = cli.RunMediaJobWithContext(ctx, req, opt.Reader)
+	} else {
+		j, err = cli.RunJobWithContext(ctx, req)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	job := newJob(cli, j)
+	if opt.NoWait {
+		return job, nil
+	}
+	return job, job.Wait(ctx)
+}
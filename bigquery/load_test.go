@@ -0,0 +1,121 @@
+package bigquery
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	SDK "google.golang.org/api/bigquery/v2"
+)
+
+func TestTableAPILoad(t *testing.T) {
+	type loadTestRow struct {
+		Name string    `bigquery:"name"`
+		TS   time.Time `bigquery:"ts"`
+	}
+	dest := &SDK.TableReference{ProjectId: "project", DatasetId: "ds", TableId: "tbl"}
+
+	tests := []struct {
+		name      string
+		opt       LoadOption
+		wantLoad  *SDK.JobConfigurationLoad
+		wantMedia string
+	}{
+		{
+			name: "Cloud Storage",
+			opt: LoadOption{
+				SourceURIs:       []string{"gs://bucket/a.csv", "gs://bucket/b.csv"},
+				SourceFormat:     FormatCSV,
+				WriteDisposition: WriteTruncate,
+				Schema:           loadTestRow{},
+				SkipLeadingRows:  1,
+				TimePartitioning: &TimePartitioning{Field: "ts", Expiration: time.Hour},
+				Clustering:       []string{"name"},
+			},
+			wantLoad: &SDK.JobConfigurationLoad{
+				DestinationTable: dest,
+				SourceUris:       []string{"gs://bucket/a.csv", "gs://bucket/b.csv"},
+				SourceFormat:     "CSV",
+				WriteDisposition: "WRITE_TRUNCATE",
+				Schema: &SDK.TableSchema{Fields: []*SDK.TableFieldSchema{
+					{Name: "name", Type: "string", Mode: modeRequired},
+					{Name: "ts", Type: "timestamp", Mode: modeRequired},
+				}},
+				SkipLeadingRows:  1,
+				TimePartitioning: &SDK.TimePartitioning{Type: "DAY", Field: "ts", ExpirationMs: 3600000},
+				Clustering:       &SDK.Clustering{Fields: []string{"name"}},
+			},
+		},
+		{
+			name: "reader",
+			opt: LoadOption{
+				SourceURIs:   []string{"gs://bucket/ignored.json"},
+				Reader:       strings.NewReader(`{"name":"a"}`),
+				SourceFormat: FormatJSON,
+				Autodetect:   true,
+			},
+			wantLoad: &SDK.JobConfigurationLoad{
+				DestinationTable: dest,
+				SourceFormat:     "NEWLINE_DELIMITED_JSON",
+				Autodetect:       true,
+			},
+			wantMedia: `{"name":"a"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := &jobTestServer{states: []string{JobStateDone}}
+			tbl := newTestBigQuery(t, srv).DatasetAPI("ds").TableAPI("tbl")
+
+			tt.opt.Location = "US"
+			tt.opt.Labels = map[string]string{"team": "data"}
+			job, err := tbl.Load(tt.opt)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+			if !job.IsDone() {
+				t.Errorf("state: got %s, want %s", job.State(), JobStateDone)
+			}
+
+			if !reflect.DeepEqual(srv.inserted.Configuration.Load, tt.wantLoad) {
+				t.Errorf("got %#v, want %#v", srv.inserted.Configuration.Load, tt.wantLoad)
+			}
+			if got := srv.inserted.Configuration.Labels; !reflect.DeepEqual(got, tt.opt.Labels) {
+				t.Errorf("labels: got %v, want %v", got, tt.opt.Labels)
+			}
+			if got := srv.inserted.JobReference.Location; got != "US" {
+				t.Errorf("location: got %s, want US", got)
+			}
+			if srv.media != tt.wantMedia {
+				t.Errorf("media: got %q, want %q", srv.media, tt.wantMedia)
+			}
+		})
+	}
+}
+
+func TestTableAPILoadNoWait(t *testing.T) {
+	srv := &jobTestServer{states: []string{JobStateDone}}
+	tbl := newTestBigQuery(t, srv).DatasetAPI("ds").TableAPI("tbl")
+
+	job, err := tbl.Load(LoadOption{SourceURIs: []string{"gs://bucket/a.csv"}, NoWait: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if job.State() != JobStateRunning || len(srv.requests) != 1 {
+		t.Errorf("got state=[%s] requests=%v, want the job without polling", job.State(), srv.requests)
+	}
+}
+
+func TestTableAPILoadWithoutSource(t *testing.T) {
+	srv := &jobTestServer{}
+	tbl := newTestBigQuery(t, srv).DatasetAPI("ds").TableAPI("tbl")
+
+	if _, err := tbl.Load(LoadOption{}); err != errLoadSource {
+		t.Errorf("got %v, want %v", err, errLoadSource)
+	}
+	if len(srv.requests) != 0 {
+		t.Errorf("got %v, want no request", srv.requests)
+	}
+}
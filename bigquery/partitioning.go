@@ -0,0 +1,74 @@
+package bigquery
+
+import (
+	"time"
+
+	SDK "google.golang.org/api/bigquery/v2"
+)
+
+// time partitioning types.
+const (
+	PartitionDay   = "DAY"
+	PartitionHour  = "HOUR"
+	PartitionMonth = "MONTH"
+	PartitionYear  = "YEAR"
+)
+
+// TimePartitioning is the setting of time-unit column or ingestion time partitioning.
+type TimePartitioning struct {
+	// Type is the partitioning unit. (default: DAY)
+	Type string
+	// Field is the column name for partitioning.
+	// When it's empty, the table is partitioned by ingestion time.
+	Field string
+	// Expiration is the storage duration of the partition. zero means no expiration.
+	Expiration time.Duration
+}
+
+func (p *TimePartitioning) toSDK() *SDK.TimePartitioning {
+	if p == nil {
+		return nil
+	}
+
+	typ := p.Type
+	if typ == "" {
+		typ = PartitionDay
+	}
+	return &SDK.TimePartitioning{
+		Type:         typ,
+		Field:        p.Field,
+		ExpirationMs: int64(p.Expiration / time.Millisecond),
+	}
+}
+
+// RangePartitioning is the setting of integer range partitioning.
+type RangePartitioning struct {
+	Field    string
+	Start    int64
+	End      int64
+	Interval int64
+}
+
+func (p *RangePartitioning) toSDK() *SDK.RangePartitioning {
+	if p == nil {
+		return nil
+	}
+
+	return &SDK.RangePartitioning{
+		Field: p.Field,
+		Range: &SDK.RangePartitioningRange{
+			Start:    p.Start,
+			End:      p.End,
+			Interval: p.Interval,
+		},
+	}
+}
+
+func newClustering(fields []string) *SDK.Clustering {
+	if len(fields) == 0 {
+		return nil
+	}
+	return &SDK.Clustering{
+		Fields: fields,
+	}
+}
@@ -0,0 +1,356 @@
+package bigquery
+
+import (
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"time"
+
+	"cloud.google.com/go/civil"
+	SDK "google.golang.org/api/bigquery/v2"
+)
+
+const (
+	parameterModeNamed      = "NAMED"
+	parameterModePositional = "POSITIONAL"
+
+	layoutTimestampParam = "2006-01-02 15:04:05.999999-07:00"
+)
+
+// QueryParameter is a parameter of the parameterized query.
+// see: https://cloud.google.com/bigquery/docs/parameterized-queries
+type QueryParameter struct {
+	// Name is used for named parameter `@name`. Keep it empty for positional parameter `?`.
+	Name string
+	// Type overrides the parameter type of the scalar value.
+	// (e.g. "DATE" for string value, "BIGNUMERIC" for *big.Rat value which has more than 9 decimal digits)
+	Type string
+	// Value supports bool, int, uint, float, string, []byte, time.Time, civil.Date, civil.DateTime, civil.Time, *big.Rat,
+	// and slice and struct of them. nil pointer is sent as NULL.
+	Value interface{}
+}
+
+func (p QueryParameter) toSDK() (*SDK.QueryParameter, error) {
+	typ, val, err := newQueryParameterTypeValue(reflect.ValueOf(p.Value))
+	if err != nil {
+		return nil, fmt.Errorf("invalid query parameter; name=[%s] error=[%s]", p.Name, err.Error())
+	}
+	if p.Type != "" {
+		typ.Type = p.Type
+	}
+
+	return &SDK.QueryParameter{
+		Name:           p.Name,
+		ParameterType:  typ,
+		ParameterValue: val,
+	}, nil
+}
+
+// newQueryParametersFromNamed converts struct or map into named query parameters.
+func newQueryParametersFromNamed(params interface{}) ([]QueryParameter, error) {
+	vv := reflect.ValueOf(params)
+	if vv.Kind() == reflect.Ptr {
+		vv = vv.Elem()
+	}
+
+	var list []QueryParameter
+	switch vv.Kind() {
+	case reflect.Map:
+		for _, key := range vv.MapKeys() {
+			name, ok := key.Interface().(string)
+			if !ok {
+				return nil, errNotMapType
+			}
+			list = append(list, QueryParameter{
+				Name:  name,
+				Value: vv.MapIndex(key).Interface(),
+			})
+		}
+	case reflect.Struct:
+		fields, err := getQueryParameterFields(vv)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range fields {
+			list = append(list, QueryParameter{
+				Name:  f.name,
+				Value: f.value.Interface(),
+			})
+		}
+	default:
+		return nil, errNotStructType
+	}
+	return list, nil
+}
+
+type queryParameterField struct {
+	name  string
+	value reflect.Value
+}
+
+// getQueryParameterFields returns the fields of the struct with the name from struct tag.
+func getQueryParameterFields(vv reflect.Value) ([]queryParameterField, error) {
+	vt := vv.Type()
+	var list []queryParameterField
+	for i, max := 0, vt.NumField(); i < max; i++ {
+		f := vt.Field(i)
+		if f.PkgPath != "" {
+			continue // skip private field
+		}
+
+		tag, opts := parseTag(f, TagName)
+		if tag == "-" {
+			continue // skip `-` tag
+		}
+
+		v := vv.Field(i)
+		if opts.has("squash") {
+			if v.Kind() == reflect.Ptr {
+				v = v.Elem()
+			}
+			if v.Kind() != reflect.Struct {
+				return nil, errNotStructType
+			}
+
+			fields, err := getQueryParameterFields(v)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, fields...)
+			continue
+		}
+
+		list = append(list, queryParameterField{
+			name:  getNameFromTag(f, TagName),
+			value: v,
+		})
+	}
+	return list, nil
+}
+
+// newQueryParameterTypeValue returns type and value of the query parameter.
+func newQueryParameterTypeValue(v reflect.Value) (*SDK.QueryParameterType, *SDK.QueryParameterValue, error) {
+	if !v.IsValid() {
+		return nil, nil, fmt.Errorf("cannot decide the type of nil")
+	}
+
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.IsNil() {
+			return nil, nil, fmt.Errorf("cannot decide the type of nil")
+		}
+		return newQueryParameterTypeValue(v.Elem())
+	case reflect.Ptr:
+		if v.IsNil() {
+			typ, err := newQueryParameterType(v.Type())
+			return typ, newNullQueryParameterValue(), err
+		}
+		if v.Type() != typeOfBigRat {
+			return newQueryParameterTypeValue(v.Elem())
+		}
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			break // BYTES
+		}
+		return newArrayQueryParameterTypeValue(v)
+	case reflect.Struct:
+		if isScalarStructType(v.Type()) {
+			break
+		}
+		return newStructQueryParameterTypeValue(v)
+	}
+
+	typ, err := newQueryParameterType(v.Type())
+	if err != nil {
+		return nil, nil, err
+	}
+	val, err := newScalarQueryParameterValue(v)
+	return typ, val, err
+}
+
+func newArrayQueryParameterTypeValue(v reflect.Value) (*SDK.QueryParameterType, *SDK.QueryParameterValue, error) {
+	size := v.Len()
+	if size == 0 {
+		typ, err := newQueryParameterType(v.Type())
+		return typ, &SDK.QueryParameterValue{ArrayValues: []*SDK.QueryParameterValue{}}, err
+	}
+
+	var elemType *SDK.QueryParameterType
+	values := make([]*SDK.QueryParameterValue, size)
+	for i := 0; i < size; i++ {
+		typ, val, err := newQueryParameterTypeValue(v.Index(i))
+		switch {
+		case err != nil:
+			return nil, nil, err
+		case typ.Type == "ARRAY":
+			return nil, nil, fmt.Errorf("array of array is not supported")
+		}
+		if elemType == nil {
+			elemType = typ
+		}
+		values[i] = val
+	}
+
+	return &SDK.QueryParameterType{
+		Type:      "ARRAY",
+		ArrayType: elemType,
+	}, &SDK.QueryParameterValue{
+		ArrayValues: values,
+	}, nil
+}
+
+func newStructQueryParameterTypeValue(v reflect.Value) (*SDK.QueryParameterType, *SDK.QueryParameterValue, error) {
+	fields, err := getQueryParameterFields(v)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	typ := &SDK.QueryParameterType{
+		Type: "STRUCT",
+	}
+	val := &SDK.QueryParameterValue{
+		StructValues: make(map[string]SDK.QueryParameterValue, len(fields)),
+	}
+	for _, f := range fields {
+		fieldType, fieldValue, err := newQueryParameterTypeValue(f.value)
+		if err != nil {
+			return nil, nil, err
+		}
+		typ.StructTypes = append(typ.StructTypes, &SDK.QueryParameterTypeStructTypes{
+			Name: f.name,
+			Type: fieldType,
+		})
+		val.StructValues[f.name] = *fieldValue
+	}
+	return typ, val, nil
+}
+
+// newQueryParameterType returns parameter type from Go type.
+func newQueryParameterType(vt reflect.Type) (*SDK.QueryParameterType, error) {
+	switch vt {
+	case typeOfBigRat:
+		return &SDK.QueryParameterType{Type: "NUMERIC"}, nil
+	case typeOfDate:
+		return &SDK.QueryParameterType{Type: "DATE"}, nil
+	case typeOfDateTime:
+		return &SDK.QueryParameterType{Type: "DATETIME"}, nil
+	case typeOfCivilTime:
+		return &SDK.QueryParameterType{Type: "TIME"}, nil
+	}
+
+	switch vt.Kind() {
+	case reflect.Bool:
+		return &SDK.QueryParameterType{Type: "BOOL"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &SDK.QueryParameterType{Type: "INT64"}, nil
+	case reflect.Float32, reflect.Float64:
+		return &SDK.QueryParameterType{Type: "FLOAT64"}, nil
+	case reflect.String:
+		return &SDK.QueryParameterType{Type: "STRING"}, nil
+	case reflect.Ptr:
+		return newQueryParameterType(vt.Elem())
+	case reflect.Slice, reflect.Array:
+		if vt.Elem().Kind() == reflect.Uint8 {
+			return &SDK.QueryParameterType{Type: "BYTES"}, nil
+		}
+
+		elemType, err := newQueryParameterType(vt.Elem())
+		switch {
+		case err != nil:
+			return nil, err
+		case elemType.Type == "ARRAY":
+			return nil, fmt.Errorf("array of array is not supported")
+		}
+		return &SDK.QueryParameterType{
+			Type:      "ARRAY",
+			ArrayType: elemType,
+		}, nil
+	case reflect.Struct:
+		if vt.ConvertibleTo(typeOfTime) {
+			return &SDK.QueryParameterType{Type: "TIMESTAMP"}, nil
+		}
+
+		typ := &SDK.QueryParameterType{
+			Type: "STRUCT",
+		}
+		for i, max := 0, vt.NumField(); i < max; i++ {
+			f := vt.Field(i)
+			if f.PkgPath != "" {
+				continue // skip private field
+			}
+			if tag, _ := parseTag(f, TagName); tag == "-" {
+				continue // skip `-` tag
+			}
+
+			fieldType, err := newQueryParameterType(f.Type)
+			if err != nil {
+				return nil, err
+			}
+			typ.StructTypes = append(typ.StructTypes, &SDK.QueryParameterTypeStructTypes{
+				Name: getNameFromTag(f, TagName),
+				Type: fieldType,
+			})
+		}
+		return typ, nil
+	}
+	return nil, fmt.Errorf("unsupported type for query parameter; type=[%s]", vt.String())
+}
+
+// newScalarQueryParameterValue returns parameter value of the scalar value.
+func newScalarQueryParameterValue(v reflect.Value) (*SDK.QueryParameterValue, error) {
+	var s string
+	switch val := v.Interface().(type) {
+	case *big.Rat:
+		s = decimalString(val)
+	case civil.Date:
+		s = val.String()
+	case civil.DateTime:
+		s = civilDateTimeString(val)
+	case civil.Time:
+		s = civilTimeString(val)
+	case []byte:
+		s = base64.StdEncoding.EncodeToString(val)
+	default:
+		switch v.Kind() {
+		case reflect.Bool:
+			s = strconv.FormatBool(v.Bool())
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			s = strconv.FormatInt(v.Int(), 10)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			s = strconv.FormatUint(v.Uint(), 10)
+		case reflect.Float32, reflect.Float64:
+			s = strconv.FormatFloat(v.Float(), 'g', -1, 64)
+		case reflect.String:
+			s = v.String()
+		case reflect.Struct:
+			if !v.Type().ConvertibleTo(typeOfTime) {
+				return nil, fmt.Errorf("unsupported type for query parameter; type=[%s]", v.Type().String())
+			}
+			s = v.Convert(typeOfTime).Interface().(time.Time).Format(layoutTimestampParam)
+		default:
+			return nil, fmt.Errorf("unsupported type for query parameter; type=[%s]", v.Type().String())
+		}
+	}
+
+	return &SDK.QueryParameterValue{
+		Value: s,
+	}, nil
+}
+
+func newNullQueryParameterValue() *SDK.QueryParameterValue {
+	return &SDK.QueryParameterValue{
+		NullFields: []string{"Value"},
+	}
+}
+
+// isScalarStructType checks if the struct type is used for scalar value.
+func isScalarStructType(vt reflect.Type) bool {
+	switch {
+	case vt == typeOfDate, vt == typeOfDateTime, vt == typeOfCivilTime,
+		vt.ConvertibleTo(typeOfTime):
+		return true
+	}
+	return false
+}
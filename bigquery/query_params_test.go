@@ -0,0 +1,118 @@
+package bigquery
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/civil"
+	SDK "google.golang.org/api/bigquery/v2"
+)
+
+func TestQueryParameterToSDK(t *testing.T) {
+	type point struct {
+		X    int64  `bigquery:"x"`
+		Y    int64  `bigquery:"y"`
+		Skip string `bigquery:"-"`
+	}
+
+	var nilInt *int64
+	bigNumeric, _ := new(big.Rat).SetString("0.12345678901234567890123456789012345678")
+	ts := time.Date(2020, 1, 2, 3, 4, 5, 123456000, time.FixedZone("JST", 9*60*60))
+
+	scalar := func(typ, value string) *SDK.QueryParameter {
+		return &SDK.QueryParameter{
+			ParameterType:  &SDK.QueryParameterType{Type: typ},
+			ParameterValue: &SDK.QueryParameterValue{Value: value},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		param   QueryParameter
+		want    *SDK.QueryParameter
+		wantErr bool
+	}{
+		{"bool", QueryParameter{Value: true}, scalar("BOOL", "true"), false},
+		{"int", QueryParameter{Value: -1}, scalar("INT64", "-1"), false},
+		{"uint", QueryParameter{Value: uint8(255)}, scalar("INT64", "255"), false},
+		{"float", QueryParameter{Value: 1.5}, scalar("FLOAT64", "1.5"), false},
+		{"string", QueryParameter{Value: "a"}, scalar("STRING", "a"), false},
+		{"bytes", QueryParameter{Value: []byte("abc")}, scalar("BYTES", "YWJj"), false},
+		{"numeric", QueryParameter{Value: big.NewRat(3, 2)}, scalar("NUMERIC", "1.5"), false},
+		{"bignumeric", QueryParameter{Type: "BIGNUMERIC", Value: bigNumeric}, scalar("BIGNUMERIC", "0.12345678901234567890123456789012345678"), false},
+		{"timestamp", QueryParameter{Value: ts}, scalar("TIMESTAMP", "2020-01-02 03:04:05.123456+09:00"), false},
+		{"date", QueryParameter{Value: civil.Date{Year: 2020, Month: time.January, Day: 2}}, scalar("DATE", "2020-01-02"), false},
+		{"datetime", QueryParameter{Value: civil.DateTime{
+			Date: civil.Date{Year: 2020, Month: time.January, Day: 2},
+			Time: civil.Time{Hour: 3, Minute: 4, Second: 5},
+		}}, scalar("DATETIME", "2020-01-02 03:04:05"), false},
+		{"time", QueryParameter{Value: civil.Time{Hour: 3, Minute: 4, Second: 5, Nanosecond: 123456000}}, scalar("TIME", "03:04:05.123456"), false},
+		{"type override", QueryParameter{Name: "d", Type: "DATE", Value: "2020-01-02"}, &SDK.QueryParameter{
+			Name:           "d",
+			ParameterType:  &SDK.QueryParameterType{Type: "DATE"},
+			ParameterValue: &SDK.QueryParameterValue{Value: "2020-01-02"},
+		}, false},
+		{"pointer", QueryParameter{Value: &ts}, scalar("TIMESTAMP", "2020-01-02 03:04:05.123456+09:00"), false},
+		{"nil pointer", QueryParameter{Value: nilInt}, &SDK.QueryParameter{
+			ParameterType:  &SDK.QueryParameterType{Type: "INT64"},
+			ParameterValue: &SDK.QueryParameterValue{NullFields: []string{"Value"}},
+		}, false},
+		{"array", QueryParameter{Value: []string{"a", "b"}}, &SDK.QueryParameter{
+			ParameterType: &SDK.QueryParameterType{
+				Type:      "ARRAY",
+				ArrayType: &SDK.QueryParameterType{Type: "STRING"},
+			},
+			ParameterValue: &SDK.QueryParameterValue{
+				ArrayValues: []*SDK.QueryParameterValue{{Value: "a"}, {Value: "b"}},
+			},
+		}, false},
+		{"empty array", QueryParameter{Value: []int64{}}, &SDK.QueryParameter{
+			ParameterType: &SDK.QueryParameterType{
+				Type:      "ARRAY",
+				ArrayType: &SDK.QueryParameterType{Type: "INT64"},
+			},
+			ParameterValue: &SDK.QueryParameterValue{
+				ArrayValues: []*SDK.QueryParameterValue{},
+			},
+		}, false},
+		{"struct", QueryParameter{Value: point{X: 1, Y: 2}}, &SDK.QueryParameter{
+			ParameterType: &SDK.QueryParameterType{
+				Type: "STRUCT",
+				StructTypes: []*SDK.QueryParameterTypeStructTypes{
+					{Name: "x", Type: &SDK.QueryParameterType{Type: "INT64"}},
+					{Name: "y", Type: &SDK.QueryParameterType{Type: "INT64"}},
+				},
+			},
+			ParameterValue: &SDK.QueryParameterValue{
+				StructValues: map[string]SDK.QueryParameterValue{
+					"x": {Value: "1"},
+					"y": {Value: "2"},
+				},
+			},
+		}, false},
+		{"nil", QueryParameter{Value: nil}, nil, true},
+		{"array of array", QueryParameter{Value: [][]int64{{1}}}, nil, true},
+		{"map", QueryParameter{Value: map[string]int{"a": 1}}, nil, true},
+		{"channel", QueryParameter{Value: make(chan int)}, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.param.toSDK()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %#v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
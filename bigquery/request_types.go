@@ -1,6 +1,8 @@
 package bigquery
 
 import (
+	"time"
+
 	SDK "google.golang.org/api/bigquery/v2"
 )
 
@@ -18,16 +20,59 @@ type QueryOption struct {
 	TimeoutMs     int64
 	UseLegacySql  bool
 	NoQueryCache  bool
+
+	// MaxBytesBilled limits the bytes billed for the query. The query exceeding the limit fails without charge.
+	MaxBytesBilled int64
+	Labels         map[string]string
+
+	// Parameters are query parameters for `@name` or `?`.
+	Parameters []QueryParameter
+	// NamedParameters is struct or map[string]interface{} whose fields are used as `@name` parameters.
+	NamedParameters interface{}
 }
 
+// ToRequest converts to *SDK.QueryRequest.
+// When the query parameters are invalid, the returned request has no query parameters.
+//
+// Deprecated: Use BuildRequest, which returns the error of the query parameters.
 func (o QueryOption) ToRequest() *SDK.QueryRequest {
+	in, err := o.BuildRequest()
+	if err != nil {
+		return o.newRequest()
+	}
+	return in
+}
+
+// BuildRequest converts to *SDK.QueryRequest with query parameters.
+// It returns the error when any of the query parameters cannot be converted.
+func (o QueryOption) BuildRequest() (*SDK.QueryRequest, error) {
+	in := o.newRequest()
+
+	params, err := o.queryParameters()
+	if err != nil {
+		return nil, err
+	}
+	if len(params) != 0 {
+		in.QueryParameters = params
+		if in.ParameterMode == "" {
+			in.ParameterMode = getParameterMode(params)
+		}
+	}
+	return in, nil
+}
+
+// newRequest converts to *SDK.QueryRequest without query parameters.
+func (o QueryOption) newRequest() *SDK.QueryRequest {
 	in := &SDK.QueryRequest{
-		Query:         o.SQL,
-		Location:      o.Location,
-		MaxResults:    o.MaxResults,
-		ParameterMode: o.ParameterMode,
-		TimeoutMs:     o.TimeoutMs,
-		UseLegacySql:  &o.UseLegacySql,
+		Query:              o.SQL,
+		DryRun:             o.DryRun,
+		Location:           o.Location,
+		MaxResults:         o.MaxResults,
+		MaximumBytesBilled: o.MaxBytesBilled,
+		Labels:             o.Labels,
+		ParameterMode:      o.ParameterMode,
+		TimeoutMs:          o.TimeoutMs,
+		UseLegacySql:       &o.UseLegacySql,
 	}
 
 	if o.ProjectID != "" && o.DatasetID != "" {
@@ -42,3 +87,120 @@ func (o QueryOption) ToRequest() *SDK.QueryRequest {
 	}
 	return in
 }
+
+// buildJob converts to *SDK.Job of the query job.
+func (o QueryOption) buildJob(projectID string) (*SDK.Job, error) {
+	req, err := o.BuildRequest()
+	if err != nil {
+		return nil, err
+	}
+
+	return &SDK.Job{
+		Configuration: &SDK.JobConfiguration{
+			DryRun: req.DryRun,
+			Labels: req.Labels,
+			Query: &SDK.JobConfigurationQuery{
+				Query:              req.Query,
+				DefaultDataset:     req.DefaultDataset,
+				MaximumBytesBilled: req.MaximumBytesBilled,
+				ParameterMode:      req.ParameterMode,
+				QueryParameters:    req.QueryParameters,
+				UseLegacySql:       req.UseLegacySql,
+				UseQueryCache:      req.UseQueryCache,
+			},
+		},
+		JobReference: &SDK.JobReference{
+			ProjectId: projectID,
+			Location:  req.Location,
+		},
+	}, nil
+}
+
+// queryParameters converts Parameters and NamedParameters into *SDK.QueryParameter.
+// It returns the error when any of the parameters cannot be converted,
+// because skipping a positional parameter shifts the bindings of the following parameters.
+func (o QueryOption) queryParameters() ([]*SDK.QueryParameter, error) {
+	params := o.Parameters
+	if o.NamedParameters != nil {
+		named, err := newQueryParametersFromNamed(o.NamedParameters)
+		if err != nil {
+			return nil, err
+		}
+		params = append(params, named...)
+	}
+
+	list := make([]*SDK.QueryParameter, 0, len(params))
+	for _, p := range params {
+		qp, err := p.toSDK()
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, qp)
+	}
+	return list, nil
+}
+
+func getParameterMode(params []*SDK.QueryParameter) string {
+	for _, p := range params {
+		if p.Name != "" {
+			return parameterModeNamed
+		}
+	}
+	return parameterModePositional
+}
+
+// InsertAllOption is optional parameters used for InsertAll operation.
+type InsertAllOption struct {
+	// SkipInvalidRows inserts all valid rows of a request, even if invalid rows exist.
+	SkipInvalidRows bool
+	// IgnoreUnknownValues accepts rows that contain values that do not match the schema.
+	IgnoreUnknownValues bool
+	// TemplateSuffix is used to create the table `{destination}{templateSuffix}` with the schema of the destination table.
+	TemplateSuffix string
+
+	// MaxRetries is the maximum number of retries for the rows failed with retryable reason.
+	// (e.g. backendError, stopped)
+	// Rows failed with other reason like `invalid` are not retried.
+	MaxRetries     int
+	RetryBaseDelay time.Duration
+	RetryMaxDelay  time.Duration
+}
+
+func (o InsertAllOption) applyTo(req *SDK.TableDataInsertAllRequest) {
+	req.SkipInvalidRows = o.SkipInvalidRows
+	req.IgnoreUnknownValues = o.IgnoreUnknownValues
+	req.TemplateSuffix = o.TemplateSuffix
+}
+
+// TableOption is optional settings of the table used for creation.
+type TableOption struct {
+	Description  string
+	FriendlyName string
+	Labels       map[string]string
+
+	// ExpirationTime is the time when the table is deleted. zero means no expiration.
+	ExpirationTime time.Time
+
+	// TimePartitioning is used for time-unit column or ingestion time partitioning.
+	TimePartitioning *TimePartitioning
+	// RangePartitioning is used for integer range partitioning.
+	RangePartitioning *RangePartitioning
+	// RequirePartitionFilter requires the filter on the partitioning column for the queries.
+	RequirePartitionFilter bool
+	// Clustering is the list of columns for clustering. up to 4 columns.
+	Clustering []string
+}
+
+func (o TableOption) applyTo(tbl *SDK.Table) {
+	tbl.Description = o.Description
+	tbl.FriendlyName = o.FriendlyName
+	tbl.Labels = o.Labels
+	if !o.ExpirationTime.IsZero() {
+		tbl.ExpirationTime = o.ExpirationTime.UnixNano() / int64(time.Millisecond)
+	}
+
+	tbl.TimePartitioning = o.TimePartitioning.toSDK()
+	tbl.RangePartitioning = o.RangePartitioning.toSDK()
+	tbl.RequirePartitionFilter = o.RequirePartitionFilter
+	tbl.Clustering = newClustering(o.Clustering)
+}
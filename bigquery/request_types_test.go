@@ -0,0 +1,82 @@
+package bigquery
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestQueryOptionBuildRequest(t *testing.T) {
+	type named struct {
+		Name string `bigquery:"name"`
+		Age  int64  `bigquery:"age"`
+	}
+
+	tests := []struct {
+		name       string
+		opt        QueryOption
+		wantMode   string
+		wantParams []string
+		wantErr    bool
+	}{
+		{"no parameter", QueryOption{SQL: "SELECT 1"}, "", nil, false},
+		{"positional", QueryOption{Parameters: []QueryParameter{
+			{Value: "a"},
+			{Value: int64(1)},
+		}}, parameterModePositional, []string{"a", "1"}, false},
+		{"named", QueryOption{Parameters: []QueryParameter{
+			{Name: "a", Value: "a"},
+		}}, parameterModeNamed, []string{"a"}, false},
+		{"named struct", QueryOption{NamedParameters: named{Name: "alice", Age: 20}}, parameterModeNamed, []string{"alice", "20"}, false},
+		{"explicit mode", QueryOption{ParameterMode: parameterModeNamed, Parameters: []QueryParameter{
+			{Value: "a"},
+		}}, parameterModeNamed, []string{"a"}, false},
+		{"invalid positional", QueryOption{Parameters: []QueryParameter{
+			{Value: "a"},
+			{Value: nil},
+			{Value: "c"},
+		}}, "", nil, true},
+		{"invalid named", QueryOption{NamedParameters: "x"}, "", nil, true},
+		{"invalid named field", QueryOption{NamedParameters: map[string]interface{}{"a": make(chan int)}}, "", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := tt.opt.BuildRequest()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %#v", req)
+				}
+				if req != nil {
+					t.Errorf("expected nil request on error, got %#v", req)
+				}
+				// ToRequest returns the request without the query parameters.
+				req := tt.opt.ToRequest()
+				if req == nil {
+					t.Fatalf("expected request from ToRequest, got nil")
+				}
+				if len(req.QueryParameters) != 0 {
+					t.Errorf("expected no query parameters from ToRequest, got %#v", req.QueryParameters)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+
+			if !reflect.DeepEqual(tt.opt.ToRequest(), req) {
+				t.Errorf("ToRequest: got %#v, want %#v", tt.opt.ToRequest(), req)
+			}
+			if req.ParameterMode != tt.wantMode {
+				t.Errorf("ParameterMode: got %s, want %s", req.ParameterMode, tt.wantMode)
+			}
+			if len(req.QueryParameters) != len(tt.wantParams) {
+				t.Fatalf("QueryParameters: got %d params, want %d", len(req.QueryParameters), len(tt.wantParams))
+			}
+			for i, p := range req.QueryParameters {
+				if p.ParameterValue.Value != tt.wantParams[i] {
+					t.Errorf("QueryParameters[%d]: got %s, want %s", i, p.ParameterValue.Value, tt.wantParams[i])
+				}
+			}
+		})
+	}
+}
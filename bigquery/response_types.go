@@ -2,67 +2,75 @@ package bigquery
 
 import (
 	"errors"
-	"strconv"
-	"time"
 
 	SDK "google.golang.org/api/bigquery/v2"
 )
 
+var errNilSchema = errors.New("Schema is nil")
+
 type QueryResponse struct {
 	*SDK.QueryResponse
+	client *BigQuery
 }
 
-func (r *QueryResponse) ToMap() ([]map[string]interface{}, error) {
-	results := make([]map[string]interface{}, 0, len(r.Rows))
-	if r.Schema == nil {
-		return nil, errors.New("Schema is nil")
+// Job returns the handle of the query job.
+// It returns nil when the response has no job reference.
+func (r *QueryResponse) Job() *Job {
+	if r.JobReference == nil {
+		return nil
 	}
+	return r.client.JobFromReference(r.JobReference.JobId, r.JobReference.Location)
+}
 
-	columnTypes := make([]columnType, len(r.Schema.Fields))
-	for i, f := range r.Schema.Fields {
-		columnTypes[i] = columnType{
-			Name: f.Name,
-			Type: f.Type,
-		}
+// ToMap decodes rows into the list of map by the schema.
+// The key is the column name and NULL value is set as nil.
+func (r *QueryResponse) ToMap() ([]map[string]interface{}, error) {
+	return DecodeRows(r.Schema, r.Rows)
+}
+
+// ScanStructs decodes rows into dst by the schema.
+// dst must be a pointer of the slice of struct or the slice of pointer of struct,
+// and struct fields are matched to the columns by `bigquery` tags.
+func (r *QueryResponse) ScanStructs(dst interface{}) error {
+	return ScanRows(r.Schema, r.Rows, dst)
+}
+
+// DecodeRows decodes rows into the list of map by the schema.
+func DecodeRows(schema *SDK.TableSchema, rows []*SDK.TableRow) ([]map[string]interface{}, error) {
+	if schema == nil {
+		return nil, errNilSchema
 	}
 
-	for _, r := range r.Rows {
-		row := make(map[string]interface{})
-		for i, col := range r.F {
-			typ := columnTypes[i]
-			typ.AssignData(row, col.V)
+	columnTypes := newColumnTypes(schema.Fields)
+	results := make([]map[string]interface{}, 0, len(rows))
+	for _, r := range rows {
+		row, err := decodeRecord(columnTypes, r.F)
+		if err != nil {
+			return nil, err
 		}
 		results = append(results, row)
 	}
-
 	return results, nil
 }
 
-type columnType struct {
-	Name string
-	Type string
-}
-
-func (c columnType) AssignData(row map[string]interface{}, value interface{}) {
-	v := value.(string)
-	switch {
-	case c.IsString():
-		row[c.Name] = v
-	case c.IsInt():
-		row[c.Name], _ = strconv.ParseInt(v, 10, 64)
-	case c.IsDate():
-		row[c.Name], _ = time.Parse("2006-01-02", v)
+// DecodeRow decodes a row into map by the schema.
+func DecodeRow(schema *SDK.TableSchema, row *SDK.TableRow) (map[string]interface{}, error) {
+	if schema == nil {
+		return nil, errNilSchema
 	}
+	return decodeRecord(newColumnTypes(schema.Fields), row.F)
 }
 
-func (c columnType) IsString() bool {
-	return c.Type == "STRING"
-}
-
-func (c columnType) IsInt() bool {
-	return c.Type == "INTEGER"
-}
+func decodeRecord(columnTypes []columnType, cells []*SDK.TableCell) (map[string]interface{}, error) {
+	if len(cells) > len(columnTypes) {
+		return nil, errSchemaMismatch
+	}
 
-func (c columnType) IsDate() bool {
-	return c.Type == "DATE"
+	row := make(map[string]interface{}, len(cells))
+	for i, col := range cells {
+		if err := columnTypes[i].AssignData(row, col.V); err != nil {
+			return nil, err
+		}
+	}
+	return row, nil
 }
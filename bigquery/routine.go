@@ -0,0 +1,295 @@
+package bigquery
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	SDK "google.golang.org/api/bigquery/v2"
+)
+
+// types of the routine.
+const (
+	RoutineTypeScalarFunction = "SCALAR_FUNCTION"
+	RoutineTypeProcedure      = "PROCEDURE"
+	RoutineTypeTableFunction  = "TABLE_VALUED_FUNCTION"
+)
+
+// languages of the routine body.
+const (
+	RoutineLanguageSQL        = "SQL"
+	RoutineLanguageJavaScript = "JAVASCRIPT"
+)
+
+const (
+	argumentKindFixedType = "FIXED_TYPE"
+	argumentKindAnyType   = "ANY_TYPE"
+	argumentModeIn        = "IN"
+)
+
+// RoutineAPI is bigquery routine client. (UDF, stored procedure and table function)
+type RoutineAPI struct {
+	dataset   *DatasetAPI
+	routineID string
+}
+
+// RoutineArgument is the argument of the routine.
+type RoutineArgument struct {
+	Name string
+	// Type is the data type in SQL syntax. (e.g. INT64, ARRAY<STRING>, STRUCT<x FLOAT64, y FLOAT64>)
+	// "ANY TYPE" makes the templated argument of SQL function.
+	Type string
+	// Mode is the argument mode of the procedure. (IN, OUT or INOUT)
+	Mode string
+}
+
+// RoutineColumn is the column of the result of the table function.
+type RoutineColumn struct {
+	Name string
+	// Type is the data type in SQL syntax. (e.g. INT64, ARRAY<STRING>)
+	Type string
+}
+
+// RoutineOption is the definition of the routine except for the body.
+type RoutineOption struct {
+	// RoutineType is the type of the routine. (default: RoutineTypeScalarFunction)
+	RoutineType string
+	// Language is the language of the body. (default: RoutineLanguageSQL)
+	Language string
+
+	Arguments []RoutineArgument
+	// ReturnType is the data type of the function result in SQL syntax.
+	// It is optional for SQL function and required for JavaScript function.
+	ReturnType string
+	// ReturnTableColumns are the columns of the table function result.
+	// When it is empty, the columns are inferred from the query.
+	ReturnTableColumns []RoutineColumn
+
+	// ImportedLibraries are Cloud Storage URIs of the libraries for JavaScript function.
+	ImportedLibraries []string
+	// DeterminismLevel is the determinism of JavaScript function. (DETERMINISTIC or NOT_DETERMINISTIC)
+	DeterminismLevel string
+	Description      string
+}
+
+func (o RoutineOption) getRoutineType() string {
+	if o.RoutineType != "" {
+		return o.RoutineType
+	}
+	return RoutineTypeScalarFunction
+}
+
+func (o RoutineOption) getLanguage() string {
+	if o.Language != "" {
+		return o.Language
+	}
+	return RoutineLanguageSQL
+}
+
+func (o RoutineOption) toSDK(ref *SDK.RoutineReference, body string) (*SDK.Routine, error) {
+	routine := &SDK.Routine{
+		RoutineReference:  ref,
+		RoutineType:       o.getRoutineType(),
+		Language:          o.getLanguage(),
+		DefinitionBody:    body,
+		ImportedLibraries: o.ImportedLibraries,
+		DeterminismLevel:  o.DeterminismLevel,
+		Description:       o.Description,
+	}
+
+	for _, arg := range o.Arguments {
+		a, err := arg.toSDK()
+		if err != nil {
+			return nil, err
+		}
+		routine.Arguments = append(routine.Arguments, a)
+	}
+
+	if o.ReturnType != "" {
+		typ, err := ParseSQLType(o.ReturnType)
+		if err != nil {
+			return nil, err
+		}
+		routine.ReturnType = typ
+	}
+
+	if len(o.ReturnTableColumns) != 0 {
+		tableType := &SDK.StandardSqlTableType{}
+		for _, col := range o.ReturnTableColumns {
+			typ, err := ParseSQLType(col.Type)
+			if err != nil {
+				return nil, err
+			}
+			tableType.Columns = append(tableType.Columns, &SDK.StandardSqlField{
+				Name: col.Name,
+				Type: typ,
+			})
+		}
+		routine.ReturnTableType = tableType
+	}
+	return routine, nil
+}
+
+func (a RoutineArgument) toSDK() (*SDK.Argument, error) {
+	arg := &SDK.Argument{
+		Name: a.Name,
+		Mode: a.Mode,
+	}
+	if strings.EqualFold(strings.Join(strings.Fields(a.Type), " "), "ANY TYPE") {
+		arg.ArgumentKind = argumentKindAnyType
+		return arg, nil
+	}
+
+	typ, err := ParseSQLType(a.Type)
+	if err != nil {
+		return nil, fmt.Errorf("invalid type of the argument; name=[%s] error=[%s]", a.Name, err.Error())
+	}
+	arg.ArgumentKind = argumentKindFixedType
+	arg.DataType = typ
+	return arg, nil
+}
+
+// Create creates the routine by the given body.
+func (r *RoutineAPI) Create(body string, opt RoutineOption) error {
+	return r.CreateWithContext(context.Background(), body, opt)
+}
+
+// CreateWithContext creates the routine by the given body with the given context.
+func (r *RoutineAPI) CreateWithContext(ctx context.Context, body string, opt RoutineOption) error {
+	routine, err := opt.toSDK(r.routineReference(), body)
+	if err != nil {
+		return err
+	}
+
+	cli := r.dataset.client
+	_, err = cli.CreateRoutineWithContext(ctx, r.dataset.datasetID, routine)
+	return err
+}
+
+// Update replaces the body and definition of the routine.
+func (r *RoutineAPI) Update(body string, opt RoutineOption) error {
+	return r.UpdateWithContext(context.Background(), body, opt)
+}
+
+// UpdateWithContext replaces the body and definition of the routine with the given context.
+func (r *RoutineAPI) UpdateWithContext(ctx context.Context, body string, opt RoutineOption) error {
+	routine, err := opt.toSDK(r.routineReference(), body)
+	if err != nil {
+		return err
+	}
+
+	cli := r.dataset.client
+	_, err = cli.UpdateRoutineWithContext(ctx, r.dataset.datasetID, r.routineID, routine)
+	return err
+}
+
+// Get gets the routine.
+func (r *RoutineAPI) Get() (*Routine, error) {
+	return r.GetWithContext(context.Background())
+}
+
+// GetWithContext gets the routine with the given context.
+func (r *RoutineAPI) GetWithContext(ctx context.Context) (*Routine, error) {
+	cli := r.dataset.client
+	return cli.GetRoutineWithContext(ctx, r.dataset.datasetID, r.routineID)
+}
+
+// Delete deletes the routine.
+func (r *RoutineAPI) Delete() error {
+	return r.DeleteWithContext(context.Background())
+}
+
+// DeleteWithContext deletes the routine with the given context.
+func (r *RoutineAPI) DeleteWithContext(ctx context.Context) error {
+	cli := r.dataset.client
+	return cli.DeleteRoutineWithContext(ctx, r.dataset.datasetID, r.routineID)
+}
+
+func (r *RoutineAPI) routineReference() *SDK.RoutineReference {
+	return &SDK.RoutineReference{
+		ProjectId: r.dataset.client.projectID,
+		DatasetId: r.dataset.datasetID,
+		RoutineId: r.routineID,
+	}
+}
+
+// EnsureRoutine creates the routine when it does not exist,
+// or updates the routine when the body or arguments are different from the existing routine.
+// It returns true when the routine is created or updated.
+func (ds *DatasetAPI) EnsureRoutine(routineID, body string, opt RoutineOption) (changed bool, err error) {
+	return ds.EnsureRoutineWithContext(context.Background(), routineID, body, opt)
+}
+
+// EnsureRoutineWithContext ensures the routine with the given context.
+// Already Exists error caused by other processes is treated as success.
+func (ds *DatasetAPI) EnsureRoutineWithContext(ctx context.Context, routineID, body string, opt RoutineOption) (changed bool, err error) {
+	r := ds.RoutineAPI(routineID)
+	current, err := r.GetWithContext(ctx)
+	switch {
+	case err == nil:
+		// routine exists.
+	case !isNotFound(err):
+		return false, err
+	default:
+		err = r.CreateWithContext(ctx, body, opt)
+		switch {
+		case err == nil:
+			return true, nil
+		case isAlreadyExists(err):
+			// created by others.
+			return false, nil
+		}
+		return false, err
+	}
+
+	routine, err := opt.toSDK(r.routineReference(), body)
+	if err != nil {
+		return false, err
+	}
+	if isSameRoutine(current.Routine, routine) {
+		return false, nil
+	}
+	if _, err := ds.client.UpdateRoutineWithContext(ctx, ds.datasetID, routineID, routine); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// isSameRoutine compares the body and arguments of the routines.
+func isSameRoutine(current, routine *SDK.Routine) bool {
+	if strings.TrimSpace(current.DefinitionBody) != strings.TrimSpace(routine.DefinitionBody) {
+		return false
+	}
+	if len(current.Arguments) != len(routine.Arguments) {
+		return false
+	}
+	for i, a := range current.Arguments {
+		if !isSameArgument(a, routine.Arguments[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func isSameArgument(a, b *SDK.Argument) bool {
+	return a.Name == b.Name &&
+		argumentKind(a) == argumentKind(b) &&
+		argumentMode(a) == argumentMode(b) &&
+		FormatSQLType(a.DataType) == FormatSQLType(b.DataType)
+}
+
+// argumentKind returns the kind of the argument. The API omits the default value.
+func argumentKind(a *SDK.Argument) string {
+	if a.ArgumentKind == "" {
+		return argumentKindFixedType
+	}
+	return a.ArgumentKind
+}
+
+// argumentMode returns the mode of the argument. The API omits the default value.
+func argumentMode(a *SDK.Argument) string {
+	if a.Mode == "" {
+		return argumentModeIn
+	}
+	return a.Mode
+}
@@ -0,0 +1,77 @@
+package bigquery
+
+import (
+	"testing"
+
+	SDK "google.golang.org/api/bigquery/v2"
+)
+
+func TestIsSameRoutine(t *testing.T) {
+	newRoutine := func(t *testing.T, body string, opt RoutineOption) *SDK.Routine {
+		r, err := opt.toSDK(&SDK.RoutineReference{ProjectId: "project", DatasetId: "ds", RoutineId: "fn"}, body)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err.Error())
+		}
+		return r
+	}
+	opt := RoutineOption{
+		Arguments: []RoutineArgument{{Name: "x", Type: "INT64"}, {Name: "y", Type: "ANY TYPE"}},
+	}
+
+	tests := []struct {
+		name    string
+		current *SDK.Routine // fetched routine, which omits the default values.
+		body    string
+		opt     RoutineOption
+		want    bool
+	}{
+		{"same", &SDK.Routine{DefinitionBody: "x + 1", Arguments: []*SDK.Argument{
+			{Name: "x", ArgumentKind: "FIXED_TYPE", Mode: "IN", DataType: &SDK.StandardSqlDataType{TypeKind: "INT64"}},
+			{Name: "y", ArgumentKind: "ANY_TYPE"},
+		}}, "x + 1", opt, true},
+		{"default kind and mode are omitted", &SDK.Routine{DefinitionBody: "x + 1", Arguments: []*SDK.Argument{
+			{Name: "x", DataType: &SDK.StandardSqlDataType{TypeKind: "INT64"}},
+			{Name: "y", ArgumentKind: "ANY_TYPE"},
+		}}, "x + 1", opt, true},
+		{"surrounding whitespace of body", &SDK.Routine{DefinitionBody: "\n  x + 1\n", Arguments: []*SDK.Argument{
+			{Name: "x", DataType: &SDK.StandardSqlDataType{TypeKind: "INT64"}},
+			{Name: "y", ArgumentKind: "ANY_TYPE"},
+		}}, "x + 1", opt, true},
+		{"type alias", &SDK.Routine{DefinitionBody: "x", Arguments: []*SDK.Argument{
+			{Name: "x", DataType: &SDK.StandardSqlDataType{TypeKind: "INT64"}},
+		}}, "x", RoutineOption{Arguments: []RoutineArgument{{Name: "x", Type: "integer"}}}, true},
+		{"different body", &SDK.Routine{DefinitionBody: "x + 1", Arguments: []*SDK.Argument{
+			{Name: "x", DataType: &SDK.StandardSqlDataType{TypeKind: "INT64"}},
+			{Name: "y", ArgumentKind: "ANY_TYPE"},
+		}}, "x + 2", opt, false},
+		{"added argument", &SDK.Routine{DefinitionBody: "x + 1", Arguments: []*SDK.Argument{
+			{Name: "x", DataType: &SDK.StandardSqlDataType{TypeKind: "INT64"}},
+		}}, "x + 1", opt, false},
+		{"renamed argument", &SDK.Routine{DefinitionBody: "x + 1", Arguments: []*SDK.Argument{
+			{Name: "z", DataType: &SDK.StandardSqlDataType{TypeKind: "INT64"}},
+			{Name: "y", ArgumentKind: "ANY_TYPE"},
+		}}, "x + 1", opt, false},
+		{"different argument type", &SDK.Routine{DefinitionBody: "x + 1", Arguments: []*SDK.Argument{
+			{Name: "x", DataType: &SDK.StandardSqlDataType{TypeKind: "FLOAT64"}},
+			{Name: "y", ArgumentKind: "ANY_TYPE"},
+		}}, "x + 1", opt, false},
+		{"templated argument becomes fixed", &SDK.Routine{DefinitionBody: "x + 1", Arguments: []*SDK.Argument{
+			{Name: "x", DataType: &SDK.StandardSqlDataType{TypeKind: "INT64"}},
+			{Name: "y", DataType: &SDK.StandardSqlDataType{TypeKind: "INT64"}},
+		}}, "x + 1", opt, false},
+		{"different mode", &SDK.Routine{DefinitionBody: "SELECT 1", Arguments: []*SDK.Argument{
+			{Name: "x", DataType: &SDK.StandardSqlDataType{TypeKind: "INT64"}},
+		}}, "SELECT 1", RoutineOption{
+			RoutineType: RoutineTypeProcedure,
+			Arguments:   []RoutineArgument{{Name: "x", Type: "INT64", Mode: "OUT"}},
+		}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isSameRoutine(tt.current, newRoutine(t, tt.body, tt.opt)); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
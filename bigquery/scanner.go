@@ -0,0 +1,315 @@
+package bigquery
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/civil"
+	SDK "google.golang.org/api/bigquery/v2"
+)
+
+var (
+	errScanDestination = errors.New("destination must be a pointer of the slice of struct")
+	errScanStruct      = errors.New("destination must be a pointer of struct")
+
+	typeOfBigRat     = reflect.TypeOf(&big.Rat{})
+	typeOfTime       = reflect.TypeOf(time.Time{})
+	typeOfDate       = reflect.TypeOf(civil.Date{})
+	typeOfDateTime   = reflect.TypeOf(civil.DateTime{})
+	typeOfCivilTime  = reflect.TypeOf(civil.Time{})
+	typeOfRawMessage = reflect.TypeOf(json.RawMessage{})
+)
+
+// ScanRows decodes rows into dst by the schema.
+// dst must be a pointer of the slice of struct or the slice of pointer of struct.
+func ScanRows(schema *SDK.TableSchema, rows []*SDK.TableRow, dst interface{}) error {
+	if schema == nil {
+		return errNilSchema
+	}
+
+	dv := reflect.ValueOf(dst)
+	if dv.Kind() != reflect.Ptr || dv.IsNil() || dv.Elem().Kind() != reflect.Slice {
+		return errScanDestination
+	}
+
+	sv := dv.Elem()
+	elemType := sv.Type().Elem()
+	isPtr := elemType.Kind() == reflect.Ptr
+	structType := elemType
+	if isPtr {
+		structType = elemType.Elem()
+	}
+	if structType.Kind() != reflect.Struct {
+		return errScanDestination
+	}
+
+	columnTypes := newColumnTypes(schema.Fields)
+	list := reflect.MakeSlice(sv.Type(), 0, len(rows))
+	for _, row := range rows {
+		v := reflect.New(structType)
+		if err := scanStruct(v.Elem(), columnTypes, rowCells(row)); err != nil {
+			return err
+		}
+
+		if isPtr {
+			list = reflect.Append(list, v)
+		} else {
+			list = reflect.Append(list, v.Elem())
+		}
+	}
+	sv.Set(list)
+	return nil
+}
+
+// ScanRow decodes a row into dst by the schema.
+// dst must be a pointer of struct.
+func ScanRow(schema *SDK.TableSchema, row *SDK.TableRow, dst interface{}) error {
+	if schema == nil {
+		return errNilSchema
+	}
+
+	dv := reflect.ValueOf(dst)
+	if dv.Kind() != reflect.Ptr || dv.IsNil() || dv.Elem().Kind() != reflect.Struct {
+		return errScanStruct
+	}
+	return scanStruct(dv.Elem(), newColumnTypes(schema.Fields), rowCells(row))
+}
+
+func rowCells(row *SDK.TableRow) []interface{} {
+	cells := make([]interface{}, len(row.F))
+	for i, c := range row.F {
+		cells[i] = c.V
+	}
+	return cells
+}
+
+func scanStruct(dst reflect.Value, columnTypes []columnType, cells []interface{}) error {
+	if len(cells) > len(columnTypes) {
+		return errSchemaMismatch
+	}
+
+	index := getStructFieldIndex(dst.Type())
+	for i, cell := range cells {
+		col := columnTypes[i]
+		idx, ok := index[strings.ToLower(col.Name)]
+		if !ok {
+			continue
+		}
+		if err := col.scan(dst.FieldByIndex(idx), cell); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getStructFieldIndex returns the map of lower-cased column name and field index.
+func getStructFieldIndex(vt reflect.Type) map[string][]int {
+	index := make(map[string][]int)
+	for i, max := 0, vt.NumField(); i < max; i++ {
+		f := vt.Field(i)
+		if f.PkgPath != "" {
+			continue // skip private field
+		}
+
+		tag, opts := parseTag(f, TagName)
+		if tag == "-" {
+			continue // skip `-` tag
+		}
+
+		if opts.has("squash") && f.Type.Kind() == reflect.Struct {
+			for name, idx := range getStructFieldIndex(f.Type) {
+				index[name] = append([]int{i}, idx...)
+			}
+			continue
+		}
+
+		index[strings.ToLower(getNameFromTag(f, TagName))] = []int{i}
+	}
+	return index
+}
+
+// scan decodes raw cell value and sets it into dst.
+func (c columnType) scan(dst reflect.Value, value interface{}) error {
+	switch {
+	case value == nil:
+		dst.Set(reflect.Zero(dst.Type()))
+		return nil
+	case dst.Kind() == reflect.Interface:
+		v, err := c.Decode(value)
+		switch {
+		case err != nil:
+			return err
+		case v == nil:
+			// JSON null is decoded as nil.
+			dst.Set(reflect.Zero(dst.Type()))
+			return nil
+		}
+		rv := reflect.ValueOf(v)
+		if !rv.Type().AssignableTo(dst.Type()) {
+			return c.newScanError(dst)
+		}
+		dst.Set(rv)
+		return nil
+	case dst.Kind() == reflect.Ptr && dst.Type() != typeOfBigRat:
+		v := reflect.New(dst.Type().Elem())
+		if err := c.scan(v.Elem(), value); err != nil {
+			return err
+		}
+		dst.Set(v)
+		return nil
+	case c.IsRepeated():
+		return c.scanRepeated(dst, value)
+	}
+	return c.scanSingle(dst, value)
+}
+
+func (c columnType) scanRepeated(dst reflect.Value, value interface{}) error {
+	list, ok := value.([]interface{})
+	if !ok {
+		return c.newDecodeError(value)
+	}
+	if dst.Kind() != reflect.Slice {
+		return c.newScanError(dst)
+	}
+
+	elem := c
+	elem.Mode = ""
+	slice := reflect.MakeSlice(dst.Type(), len(list), len(list))
+	for i, v := range list {
+		if err := elem.scan(slice.Index(i), cellValue(v)); err != nil {
+			return err
+		}
+	}
+	dst.Set(slice)
+	return nil
+}
+
+func (c columnType) scanSingle(dst reflect.Value, value interface{}) error {
+	switch {
+	case c.IsRecord() && dst.Kind() == reflect.Struct:
+		cells, ok := recordCells(value)
+		if !ok {
+			return c.newDecodeError(value)
+		}
+		return scanStruct(dst, c.Fields, cells)
+	case c.IsJSON():
+		v, ok := value.(string)
+		switch {
+		case !ok:
+			return c.newDecodeError(value)
+		case dst.Type() == typeOfRawMessage:
+			dst.SetBytes([]byte(v))
+			return nil
+		case dst.Kind() == reflect.String:
+			dst.SetString(v)
+			return nil
+		}
+		return json.Unmarshal([]byte(v), dst.Addr().Interface())
+	case dst.Kind() == reflect.String && c.hasStringRepresentation():
+		// use raw string to keep the precision and format.
+		v, ok := value.(string)
+		if !ok {
+			return c.newDecodeError(value)
+		}
+		dst.SetString(v)
+		return nil
+	}
+
+	v, err := c.decodeSingle(value)
+	if err != nil {
+		return err
+	}
+	if !assignValue(dst, v) {
+		return c.newScanError(dst)
+	}
+	return nil
+}
+
+func (c columnType) hasStringRepresentation() bool {
+	switch {
+	case c.IsString(), c.IsNumeric(), c.IsGeography(), c.IsInterval(),
+		c.IsDate(), c.IsDateTime(), c.IsTime():
+		return true
+	}
+	return false
+}
+
+func (c columnType) newScanError(dst reflect.Value) error {
+	return fmt.Errorf("cannot scan the column into the field; name=[%s] type=[%s] field_type=[%s]", c.Name, c.Type, dst.Type().String())
+}
+
+// assignValue sets decoded value into dst with the type conversion.
+func assignValue(dst reflect.Value, value interface{}) bool {
+	if value == nil {
+		dst.Set(reflect.Zero(dst.Type()))
+		return true
+	}
+
+	rv := reflect.ValueOf(value)
+	if rv.Type().AssignableTo(dst.Type()) {
+		dst.Set(rv)
+		return true
+	}
+
+	switch v := value.(type) {
+	case time.Time:
+		switch {
+		case dst.Type() == typeOfDate:
+			dst.Set(reflect.ValueOf(civil.DateOf(v)))
+			return true
+		case dst.Type() == typeOfDateTime:
+			dst.Set(reflect.ValueOf(civil.DateTimeOf(v)))
+			return true
+		case dst.Type().ConvertibleTo(typeOfTime):
+			dst.Set(rv.Convert(dst.Type()))
+			return true
+		}
+	case *big.Rat:
+		switch dst.Kind() {
+		case reflect.Float32, reflect.Float64:
+			f, _ := v.Float64()
+			dst.SetFloat(f)
+			return true
+		}
+	}
+
+	switch dst.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if rv.Kind() != reflect.Int64 || dst.OverflowInt(rv.Int()) {
+			return false
+		}
+		dst.SetInt(rv.Int())
+		return true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if rv.Kind() != reflect.Int64 || rv.Int() < 0 || dst.OverflowUint(uint64(rv.Int())) {
+			return false
+		}
+		dst.SetUint(uint64(rv.Int()))
+		return true
+	case reflect.Float32, reflect.Float64:
+		switch rv.Kind() {
+		case reflect.Float64:
+			dst.SetFloat(rv.Float())
+			return true
+		case reflect.Int64:
+			dst.SetFloat(float64(rv.Int()))
+			return true
+		}
+	case reflect.String:
+		if rv.Kind() == reflect.String {
+			dst.SetString(rv.String())
+			return true
+		}
+	case reflect.Bool:
+		if rv.Kind() == reflect.Bool {
+			dst.SetBool(rv.Bool())
+			return true
+		}
+	}
+	return false
+}
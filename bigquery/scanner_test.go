@@ -0,0 +1,216 @@
+package bigquery
+
+import (
+	"encoding/json"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/civil"
+	SDK "google.golang.org/api/bigquery/v2"
+)
+
+type scanTestAddress struct {
+	City string `bigquery:"city"`
+	Zip  *string
+}
+
+type scanTestBase struct {
+	ID int64 `bigquery:"id"`
+}
+
+type scanTestRow struct {
+	Base scanTestBase `bigquery:",squash"`
+
+	Name      string             `bigquery:"name"`
+	Age       int                `bigquery:"age"`
+	Score     *float64           `bigquery:"score"`
+	Price     *big.Rat           `bigquery:"price"`
+	PriceStr  string             `bigquery:"price_str"`
+	Raw       []byte             `bigquery:"raw"`
+	CreatedAt time.Time          `bigquery:"created_at"`
+	Birthday  civil.Date         `bigquery:"birthday"`
+	Tags      []string           `bigquery:"tags"`
+	Address   scanTestAddress    `bigquery:"address"`
+	Addresses []*scanTestAddress `bigquery:"addresses"`
+	Attrs     json.RawMessage    `bigquery:"attrs"`
+	Any       interface{}        `bigquery:"any"`
+	Ignored   string             `bigquery:"-"`
+}
+
+func TestScanRow(t *testing.T) {
+	schema := &SDK.TableSchema{
+		Fields: []*SDK.TableFieldSchema{
+			{Name: "id", Type: "INTEGER"},
+			{Name: "name", Type: "STRING"},
+			{Name: "age", Type: "INTEGER"},
+			{Name: "score", Type: "FLOAT"},
+			{Name: "price", Type: "NUMERIC"},
+			{Name: "price_str", Type: "BIGNUMERIC"},
+			{Name: "raw", Type: "BYTES"},
+			{Name: "created_at", Type: "TIMESTAMP"},
+			{Name: "birthday", Type: "DATE"},
+			{Name: "tags", Type: "STRING", Mode: "REPEATED"},
+			{Name: "address", Type: "RECORD", Fields: []*SDK.TableFieldSchema{
+				{Name: "city", Type: "STRING"},
+				{Name: "zip", Type: "STRING"},
+			}},
+			{Name: "addresses", Type: "RECORD", Mode: "REPEATED", Fields: []*SDK.TableFieldSchema{
+				{Name: "city", Type: "STRING"},
+				{Name: "zip", Type: "STRING"},
+			}},
+			{Name: "attrs", Type: "JSON"},
+			{Name: "any", Type: "JSON"},
+			{Name: "ignored", Type: "STRING"},
+		},
+	}
+	record := func(values ...interface{}) map[string]interface{} {
+		cells := make([]interface{}, len(values))
+		for i, v := range values {
+			cells[i] = map[string]interface{}{"v": v}
+		}
+		return map[string]interface{}{"f": cells}
+	}
+
+	var got scanTestRow
+	err := ScanRow(schema, &SDK.TableRow{F: []*SDK.TableCell{
+		{V: "1"},
+		{V: "alice"},
+		{V: "20"},
+		{V: "1.5"},
+		{V: "1.25"},
+		{V: "0.12345678901234567890123456789012345678"},
+		{V: "YWJj"},
+		{V: "1500000000"},
+		{V: "2020-01-02"},
+		{V: []interface{}{map[string]interface{}{"v": "a"}, map[string]interface{}{"v": "b"}}},
+		{V: record("tokyo", "100-0001")},
+		{V: []interface{}{map[string]interface{}{"v": record("osaka", nil)}}},
+		{V: `{"a":1}`},
+		{V: `[1,2]`},
+		{V: "x"},
+	}}, &got)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	score := 1.5
+	zip := "100-0001"
+	want := scanTestRow{
+		Base:      scanTestBase{ID: 1},
+		Name:      "alice",
+		Age:       20,
+		Score:     &score,
+		Price:     big.NewRat(5, 4),
+		PriceStr:  "0.12345678901234567890123456789012345678",
+		Raw:       []byte("abc"),
+		CreatedAt: time.Unix(1500000000, 0).UTC(),
+		Birthday:  civil.Date{Year: 2020, Month: time.January, Day: 2},
+		Tags:      []string{"a", "b"},
+		Address:   scanTestAddress{City: "tokyo", Zip: &zip},
+		Addresses: []*scanTestAddress{{City: "osaka"}},
+		Attrs:     json.RawMessage(`{"a":1}`),
+		Any:       []interface{}{float64(1), float64(2)},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %#v, want %#v", got, want)
+	}
+}
+
+func TestScanRowNull(t *testing.T) {
+	tests := []struct {
+		name  string
+		field *SDK.TableFieldSchema
+		value interface{}
+		dst   interface{}
+		want  interface{}
+	}{
+		{"null into interface", &SDK.TableFieldSchema{Type: "STRING"}, nil, &struct{ V interface{} }{V: "x"}, &struct{ V interface{} }{}},
+		{"json null into interface", &SDK.TableFieldSchema{Type: "JSON"}, "null", &struct{ V interface{} }{V: "x"}, &struct{ V interface{} }{}},
+		{"json null into pointer of interface", &SDK.TableFieldSchema{Type: "JSON"}, "null", &struct{ V *interface{} }{}, &struct{ V *interface{} }{V: new(interface{})}},
+		{"json null into map", &SDK.TableFieldSchema{Type: "JSON"}, "null", &struct{ V map[string]interface{} }{V: map[string]interface{}{"a": 1}}, &struct{ V map[string]interface{} }{}},
+		{"json null in repeated", &SDK.TableFieldSchema{Type: "JSON", Mode: "REPEATED"}, []interface{}{
+			map[string]interface{}{"v": "null"},
+			map[string]interface{}{"v": "1"},
+		}, &struct{ V []interface{} }{}, &struct{ V []interface{} }{V: []interface{}{nil, float64(1)}}},
+		{"json null in repeated into interface", &SDK.TableFieldSchema{Type: "JSON", Mode: "REPEATED"}, []interface{}{
+			map[string]interface{}{"v": "null"},
+		}, &struct{ V interface{} }{}, &struct{ V interface{} }{V: []interface{}{nil}}},
+		{"null into pointer", &SDK.TableFieldSchema{Type: "INTEGER"}, nil, &struct{ V *int64 }{V: new(int64)}, &struct{ V *int64 }{}},
+		{"null into record", &SDK.TableFieldSchema{Type: "RECORD", Fields: []*SDK.TableFieldSchema{{Name: "a", Type: "STRING"}}}, nil, &struct{ V interface{} }{V: "x"}, &struct{ V interface{} }{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field := *tt.field
+			field.Name = "v"
+			schema := &SDK.TableSchema{Fields: []*SDK.TableFieldSchema{&field}}
+			if err := ScanRow(schema, &SDK.TableRow{F: []*SDK.TableCell{{V: tt.value}}}, tt.dst); err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+			if !reflect.DeepEqual(tt.dst, tt.want) {
+				t.Errorf("got %#v, want %#v", tt.dst, tt.want)
+			}
+		})
+	}
+}
+
+func TestScanRowError(t *testing.T) {
+	schema := &SDK.TableSchema{
+		Fields: []*SDK.TableFieldSchema{{Name: "v", Type: "STRING"}},
+	}
+	row := &SDK.TableRow{F: []*SDK.TableCell{{V: "x"}}}
+
+	tests := []struct {
+		name   string
+		schema *SDK.TableSchema
+		dst    interface{}
+	}{
+		{"nil schema", nil, &struct{ V string }{}},
+		{"not pointer", schema, struct{ V string }{}},
+		{"not struct", schema, new(string)},
+		{"type mismatch", schema, &struct{ V bool }{}},
+		{"interface mismatch", schema, &struct{ V error }{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ScanRow(tt.schema, row, tt.dst); err == nil {
+				t.Errorf("expected error")
+			}
+		})
+	}
+}
+
+func TestScanRows(t *testing.T) {
+	schema := &SDK.TableSchema{
+		Fields: []*SDK.TableFieldSchema{{Name: "name", Type: "STRING"}},
+	}
+	rows := []*SDK.TableRow{
+		{F: []*SDK.TableCell{{V: "a"}}},
+		{F: []*SDK.TableCell{{V: "b"}}},
+	}
+
+	type row struct {
+		Name string `bigquery:"name"`
+	}
+	var list []row
+	if err := ScanRows(schema, rows, &list); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if want := []row{{"a"}, {"b"}}; !reflect.DeepEqual(list, want) {
+		t.Errorf("got %#v, want %#v", list, want)
+	}
+
+	var ptrs []*row
+	if err := ScanRows(schema, rows, &ptrs); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if want := []*row{{"a"}, {"b"}}; !reflect.DeepEqual(ptrs, want) {
+		t.Errorf("got %#v, want %#v", ptrs, want)
+	}
+
+	if err := ScanRows(schema, rows, &[]string{}); err != errScanDestination {
+		t.Errorf("expected errScanDestination, got %v", err)
+	}
+}
@@ -2,13 +2,25 @@ package bigquery
 
 import (
 	"errors"
+	"fmt"
+	"math/big"
 	"reflect"
 	"strings"
-	"time"
 
+	"cloud.google.com/go/civil"
 	SDK "google.golang.org/api/bigquery/v2"
 )
 
+const (
+	modeRequired = "required"
+	modeNullable = "nullable"
+	modeRepeated = "repeated"
+	typeRecord   = "record"
+
+	// bigNumericScale is the maximum scale of BIGNUMERIC type.
+	bigNumericScale = 38
+)
+
 var (
 	// TagName is used for struct tag which defining table column name
 	TagName = "bigquery"
@@ -35,7 +47,25 @@ func convertToSchema(schemaStruct interface{}) (*SDK.TableSchema, error) {
 		return nil, errNotStructType
 	}
 
-	schema := &SDK.TableSchema{}
+	fields, err := convertStructTypeToFields(vt, make(map[reflect.Type]bool))
+	if err != nil {
+		return nil, err
+	}
+	return &SDK.TableSchema{
+		Fields: fields,
+	}, nil
+}
+
+// convertStructTypeToFields returns field schemas from the struct type.
+// visited holds the struct types in the current path to detect recursive types.
+func convertStructTypeToFields(vt reflect.Type, visited map[reflect.Type]bool) ([]*SDK.TableFieldSchema, error) {
+	if visited[vt] {
+		return nil, fmt.Errorf("recursive type is not supported for schema; type=[%s]", vt.String())
+	}
+	visited[vt] = true
+	defer delete(visited, vt)
+
+	var fields []*SDK.TableFieldSchema
 	for i, max := 0, vt.NumField(); i < max; i++ {
 		f := vt.Field(i)
 		if f.PkgPath != "" {
@@ -47,26 +77,36 @@ func convertToSchema(schemaStruct interface{}) (*SDK.TableSchema, error) {
 			continue // skip `-` tag
 		}
 
-		v := vv.Field(i)
 		if opts.has("squash") {
-			convertToSchema(v.Interface())
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() != reflect.Struct {
+				return nil, errNotStructType
+			}
+
+			list, err := convertStructTypeToFields(ft, visited)
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, list...)
 			continue
 		}
 
-		name := getNameFromTag(f, TagName)
-		fs, err := createFieldSchema(v)
+		fs, err := createFieldSchemaFromType(f.Type, visited)
 		switch {
 		case err != nil:
 			return nil, err
-		case opts.has("nullable"):
-			fs.Mode = "nullable"
+		case opts.has("nullable") && fs.Mode != modeRepeated:
+			fs.Mode = modeNullable
 		}
 
-		fs.Name = name
-		schema.Fields = append(schema.Fields, fs)
+		fs.Name = getNameFromTag(f, TagName)
+		fields = append(fields, fs)
 	}
 
-	return schema, nil
+	return fields, nil
 }
 
 func convertToSchemaFromMap(schemaStruct interface{}) (*SDK.TableSchema, error) {
@@ -100,11 +140,44 @@ func convertToSchemaFromMap(schemaStruct interface{}) (*SDK.TableSchema, error)
 	return schema, nil
 }
 
+// createFieldSchema returns field schema from the value of map.
 func createFieldSchema(v reflect.Value) (*SDK.TableFieldSchema, error) {
+	if !v.IsValid() {
+		return nil, errInvalidType
+	}
+
+	switch {
+	case v.Kind() == reflect.Map:
+		schema, err := convertToSchemaFromMap(v.Interface())
+		if err != nil {
+			return nil, err
+		}
+		return &SDK.TableFieldSchema{
+			Mode:   modeRequired,
+			Type:   typeRecord,
+			Fields: schema.Fields,
+		}, nil
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Interface:
+		// use the first element to decide the type of []interface{}.
+		if v.Len() == 0 {
+			return nil, errInvalidType
+		}
+		fs, err := createFieldSchema(reflect.ValueOf(v.Index(0).Interface()))
+		if err != nil {
+			return nil, err
+		}
+		fs.Mode = modeRepeated
+		return fs, nil
+	}
+	return createFieldSchemaFromType(v.Type(), make(map[reflect.Type]bool))
+}
+
+// createFieldSchemaFromType returns field schema from the type of struct field.
+func createFieldSchemaFromType(vt reflect.Type, visited map[reflect.Type]bool) (*SDK.TableFieldSchema, error) {
 	fs := &SDK.TableFieldSchema{
-		Mode: "required",
+		Mode: modeRequired,
 	}
-	switch v.Kind() {
+	switch vt.Kind() {
 	case reflect.Bool:
 		fs.Type = "boolean"
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
@@ -113,14 +186,58 @@ func createFieldSchema(v reflect.Value) (*SDK.TableFieldSchema, error) {
 		fs.Type = "float"
 	case reflect.String:
 		fs.Type = "string"
+	case reflect.Ptr:
+		if vt == typeOfBigRat {
+			// the value is written with the scale of BIGNUMERIC. (see decimalString)
+			fs.Type = "bignumeric"
+			fs.Mode = modeNullable
+			return fs, nil
+		}
+
+		elem, err := createFieldSchemaFromType(vt.Elem(), visited)
+		if err != nil {
+			return nil, err
+		}
+		if elem.Mode == modeRequired {
+			elem.Mode = modeNullable
+		}
+		return elem, nil
 	case reflect.Array, reflect.Slice:
-		fs.Mode = "repeated"
+		if vt.Elem().Kind() == reflect.Uint8 {
+			fs.Type = "bytes"
+			return fs, nil
+		}
+
+		elem, err := createFieldSchemaFromType(vt.Elem(), visited)
+		switch {
+		case err != nil:
+			return nil, err
+		case elem.Mode == modeRepeated:
+			// array of array is not supported.
+			return nil, errInvalidType
+		}
+		elem.Mode = modeRepeated
+		return elem, nil
 	case reflect.Struct:
 		switch {
-		case v.Type().ConvertibleTo(reflect.TypeOf(time.Time{})):
+		case vt == typeOfDate:
+			fs.Type = "date"
+		case vt == typeOfDateTime:
+			fs.Type = "datetime"
+		case vt == typeOfCivilTime:
+			fs.Type = "time"
+		case vt.ConvertibleTo(typeOfTime):
 			fs.Type = "timestamp"
 		default:
-			return nil, errInvalidStructType
+			fields, err := convertStructTypeToFields(vt, visited)
+			switch {
+			case err != nil:
+				return nil, err
+			case len(fields) == 0:
+				return nil, errInvalidStructType
+			}
+			fs.Type = typeRecord
+			fs.Fields = fields
 		}
 	default:
 		return nil, errInvalidType
@@ -192,6 +309,10 @@ func convertStructToMap(schemaStruct interface{}) (map[string]interface{}, error
 
 		v := vv.Field(i)
 		if opts.has("squash") {
+			if v.Kind() == reflect.Ptr && v.IsNil() {
+				continue
+			}
+
 			list, err := convertStructToMap(v.Interface())
 			if err != nil {
 				return data, err
@@ -203,17 +324,158 @@ func convertStructToMap(schemaStruct interface{}) (map[string]interface{}, error
 			continue
 		}
 
-		val := v.Interface()
-		if opts.has("nullable") && isZero(val) {
+		if opts.has("nullable") && isZero(v.Interface()) {
 			continue
 		}
 
+		val, err := convertValueForRow(v)
+		switch {
+		case err != nil:
+			return data, err
+		case val == nil:
+			continue // NULL
+		}
+
 		data[getNameFromTag(f, TagName)] = val
 	}
 
 	return data, nil
 }
 
+// convertValueForRow converts the value of struct field into the value of row json.
+func convertValueForRow(v reflect.Value) (interface{}, error) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		switch {
+		case v.IsNil():
+			return nil, nil
+		case v.Type() == typeOfBigRat:
+			return decimalString(v.Interface().(*big.Rat)), nil
+		}
+		return convertValueForRow(v.Elem())
+	case reflect.Struct:
+		switch val := v.Interface().(type) {
+		case civil.Date:
+			return val.String(), nil
+		case civil.DateTime:
+			return civilDateTimeString(val), nil
+		case civil.Time:
+			return civilTimeString(val), nil
+		}
+		if v.Type().ConvertibleTo(typeOfTime) {
+			return v.Convert(typeOfTime).Interface(), nil
+		}
+		return convertStructToMap(v.Interface())
+	case reflect.Slice:
+		if v.IsNil() {
+			return nil, nil
+		}
+		fallthrough
+	case reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			if v.Kind() == reflect.Array {
+				// [N]byte is encoded as the list of numbers, so copy it into []byte.
+				b := make([]byte, v.Len())
+				reflect.Copy(reflect.ValueOf(b), v)
+				return b, nil
+			}
+			return v.Interface(), nil // encoded as base64 string.
+		}
+
+		list := make([]interface{}, v.Len())
+		for i := range list {
+			val, err := convertValueForRow(v.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			list[i] = val
+		}
+		return list, nil
+	}
+	return v.Interface(), nil
+}
+
+// decimalString returns NUMERIC or BIGNUMERIC format string without trailing zeros. (e.g. "1.5")
+// The scale of BIGNUMERIC is used to keep the precision for both types.
+func decimalString(r *big.Rat) string {
+	s := r.FloatString(bigNumericScale)
+	if strings.Contains(s, ".") {
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	}
+	return s
+}
+
+// civilDateTimeString returns DATETIME format string. (e.g. "2006-01-02 15:04:05.999999")
+func civilDateTimeString(dt civil.DateTime) string {
+	return dt.Date.String() + " " + civilTimeString(dt.Time)
+}
+
+// civilTimeString returns TIME format string with microsecond precision. (e.g. "15:04:05.999999")
+func civilTimeString(t civil.Time) string {
+	if t.Nanosecond == 0 {
+		return t.String()
+	}
+	return fmt.Sprintf("%02d:%02d:%02d.%06d", t.Hour, t.Minute, t.Second, t.Nanosecond/1000)
+}
+
+// InsertIDer is used for the insert ID of the row on InsertAll operation.
+// BigQuery uses the insert ID to de-duplicate the rows on a best-effort basis.
+type InsertIDer interface {
+	InsertID() string
+}
+
+var typeOfInsertIDer = reflect.TypeOf((*InsertIDer)(nil)).Elem()
+
+// getInsertID returns the insert ID from InsertIDer or the field with `insertid` tag option.
+func getInsertID(data interface{}) string {
+	if v, ok := data.(InsertIDer); ok {
+		return v.InsertID()
+	}
+
+	vv := reflect.ValueOf(data)
+	if vv.Kind() == reflect.Ptr {
+		if vv.IsNil() {
+			return ""
+		}
+		vv = vv.Elem()
+	}
+	if vv.Kind() != reflect.Struct {
+		return ""
+	}
+	if reflect.PtrTo(vv.Type()).Implements(typeOfInsertIDer) {
+		// InsertID has pointer receiver, and the value from interface{} is not addressable.
+		p := reflect.New(vv.Type())
+		p.Elem().Set(vv)
+		return p.Interface().(InsertIDer).InsertID()
+	}
+
+	vt := vv.Type()
+	for i, max := 0, vt.NumField(); i < max; i++ {
+		f := vt.Field(i)
+		if f.PkgPath != "" {
+			continue // skip private field
+		}
+
+		_, opts := parseTag(f, TagName)
+		switch {
+		case opts.has("insertid"):
+			v := vv.Field(i)
+			if v.Kind() == reflect.Ptr {
+				if v.IsNil() {
+					return ""
+				}
+				v = v.Elem()
+			}
+			return fmt.Sprint(v.Interface())
+		case opts.has("squash"):
+			if id := getInsertID(vv.Field(i).Interface()); id != "" {
+				return id
+			}
+		}
+	}
+	return ""
+}
+
 func isZero(value interface{}) bool {
 	switch v := value.(type) {
 	case int:
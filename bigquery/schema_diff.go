@@ -0,0 +1,215 @@
+package bigquery
+
+import (
+	"strings"
+
+	SDK "google.golang.org/api/bigquery/v2"
+)
+
+// SchemaChangeType is the kind of schema change.
+type SchemaChangeType string
+
+// schema change types.
+const (
+	// additive changes, which can be applied by Tables.Patch.
+	SchemaChangeAddColumn SchemaChangeType = "add_column"
+	SchemaChangeRelaxMode SchemaChangeType = "relax_mode"
+
+	// incompatible changes, which cannot be applied by Tables.Patch.
+	SchemaChangeRemoveColumn SchemaChangeType = "remove_column"
+	SchemaChangeChangeType   SchemaChangeType = "change_type"
+	SchemaChangeChangeMode   SchemaChangeType = "change_mode"
+)
+
+// IsAdditive checks if the change can be applied to the existing table.
+func (t SchemaChangeType) IsAdditive() bool {
+	switch t {
+	case SchemaChangeAddColumn, SchemaChangeRelaxMode:
+		return true
+	}
+	return false
+}
+
+// SchemaChange is a change of the column between the existing schema and the new schema.
+type SchemaChange struct {
+	Type SchemaChangeType
+	// Name is the column name. nested column is joined by dot. (e.g. "address.city")
+	Name string
+
+	OldType string
+	OldMode string
+	NewType string
+	NewMode string
+}
+
+// SchemaDiff is the result of comparison of the schemas.
+type SchemaDiff struct {
+	Additive     []SchemaChange
+	Incompatible []SchemaChange
+
+	// Schema is the existing schema with additive changes.
+	Schema *SDK.TableSchema
+}
+
+// HasChanges checks if there are any differences.
+func (d *SchemaDiff) HasChanges() bool {
+	return len(d.Additive) != 0 || len(d.Incompatible) != 0
+}
+
+// HasAdditive checks if there are additive changes.
+func (d *SchemaDiff) HasAdditive() bool {
+	return len(d.Additive) != 0
+}
+
+// HasIncompatible checks if there are incompatible changes.
+func (d *SchemaDiff) HasIncompatible() bool {
+	return len(d.Incompatible) != 0
+}
+
+// DiffSchema compares the existing schema and the new schema.
+// New column is added as NULLABLE and REQUIRED column is relaxed to NULLABLE,
+// and other changes are reported as incompatible changes.
+func DiffSchema(oldSchema, newSchema *SDK.TableSchema) *SchemaDiff {
+	if oldSchema == nil {
+		oldSchema = &SDK.TableSchema{}
+	}
+	if newSchema == nil {
+		newSchema = &SDK.TableSchema{}
+	}
+
+	d := &SchemaDiff{}
+	d.Schema = &SDK.TableSchema{
+		Fields: d.diffFields("", oldSchema.Fields, newSchema.Fields),
+	}
+	return d
+}
+
+// diffFields compares fields and returns merged fields.
+func (d *SchemaDiff) diffFields(prefix string, oldFields, newFields []*SDK.TableFieldSchema) []*SDK.TableFieldSchema {
+	newMap := make(map[string]*SDK.TableFieldSchema, len(newFields))
+	for _, f := range newFields {
+		newMap[strings.ToLower(f.Name)] = f
+	}
+
+	merged := make([]*SDK.TableFieldSchema, 0, len(oldFields)+len(newFields))
+	exists := make(map[string]struct{}, len(oldFields))
+	for _, oldField := range oldFields {
+		key := strings.ToLower(oldField.Name)
+		exists[key] = struct{}{}
+
+		newField, ok := newMap[key]
+		if !ok {
+			d.addIncompatible(SchemaChangeRemoveColumn, prefix, oldField, nil)
+			merged = append(merged, oldField)
+			continue
+		}
+		merged = append(merged, d.diffField(prefix, oldField, newField))
+	}
+
+	for _, newField := range newFields {
+		if _, ok := exists[strings.ToLower(newField.Name)]; ok {
+			continue
+		}
+
+		// new column must be NULLABLE or REPEATED.
+		f := relaxFieldSchema(newField)
+		d.Additive = append(d.Additive, SchemaChange{
+			Type:    SchemaChangeAddColumn,
+			Name:    prefix + f.Name,
+			NewType: f.Type,
+			NewMode: f.Mode,
+		})
+		merged = append(merged, f)
+	}
+	return merged
+}
+
+// diffField compares a column and returns merged column.
+func (d *SchemaDiff) diffField(prefix string, oldField, newField *SDK.TableFieldSchema) *SDK.TableFieldSchema {
+	oldType := normalizeFieldType(oldField.Type)
+	newType := normalizeFieldType(newField.Type)
+	if oldType != newType {
+		d.addIncompatible(SchemaChangeChangeType, prefix, oldField, newField)
+		return oldField
+	}
+
+	f := *oldField
+	oldMode := normalizeFieldMode(oldField.Mode)
+	newMode := normalizeFieldMode(newField.Mode)
+	switch {
+	case oldMode == newMode:
+		// no change
+	case oldMode == "REQUIRED" && newMode == "NULLABLE":
+		f.Mode = "NULLABLE"
+		d.Additive = append(d.Additive, SchemaChange{
+			Type:    SchemaChangeRelaxMode,
+			Name:    prefix + oldField.Name,
+			OldType: oldField.Type,
+			OldMode: oldField.Mode,
+			NewType: newField.Type,
+			NewMode: f.Mode,
+		})
+	case oldMode == "NULLABLE" && newMode == "REQUIRED":
+		// NULLABLE column accepts the value of REQUIRED.
+	default:
+		d.addIncompatible(SchemaChangeChangeMode, prefix, oldField, newField)
+	}
+
+	if oldType == "RECORD" {
+		f.Fields = d.diffFields(prefix+oldField.Name+".", oldField.Fields, newField.Fields)
+	}
+	return &f
+}
+
+func (d *SchemaDiff) addIncompatible(typ SchemaChangeType, prefix string, oldField, newField *SDK.TableFieldSchema) {
+	c := SchemaChange{
+		Type:    typ,
+		Name:    prefix + oldField.Name,
+		OldType: oldField.Type,
+		OldMode: oldField.Mode,
+	}
+	if newField != nil {
+		c.NewType = newField.Type
+		c.NewMode = newField.Mode
+	}
+	d.Incompatible = append(d.Incompatible, c)
+}
+
+// relaxFieldSchema returns copied field schema whose REQUIRED modes are changed to NULLABLE.
+func relaxFieldSchema(field *SDK.TableFieldSchema) *SDK.TableFieldSchema {
+	f := *field
+	if normalizeFieldMode(f.Mode) == "REQUIRED" {
+		f.Mode = "NULLABLE"
+	}
+	if len(field.Fields) != 0 {
+		f.Fields = make([]*SDK.TableFieldSchema, len(field.Fields))
+		for i, child := range field.Fields {
+			f.Fields[i] = relaxFieldSchema(child)
+		}
+	}
+	return &f
+}
+
+// normalizeFieldType returns legacy type name of standard SQL type.
+func normalizeFieldType(typ string) string {
+	typ = strings.ToUpper(typ)
+	switch typ {
+	case "INT64":
+		return "INTEGER"
+	case "FLOAT64":
+		return "FLOAT"
+	case "BOOL":
+		return "BOOLEAN"
+	case "STRUCT":
+		return "RECORD"
+	}
+	return typ
+}
+
+// normalizeFieldMode returns upper-cased mode and default mode is NULLABLE.
+func normalizeFieldMode(mode string) string {
+	if mode == "" {
+		return "NULLABLE"
+	}
+	return strings.ToUpper(mode)
+}
@@ -0,0 +1,156 @@
+package bigquery
+
+import (
+	"reflect"
+	"testing"
+
+	SDK "google.golang.org/api/bigquery/v2"
+)
+
+func TestDiffSchema(t *testing.T) {
+	field := func(name, typ, mode string, children ...*SDK.TableFieldSchema) *SDK.TableFieldSchema {
+		return &SDK.TableFieldSchema{Name: name, Type: typ, Mode: mode, Fields: children}
+	}
+
+	tests := []struct {
+		name             string
+		oldFields        []*SDK.TableFieldSchema
+		newFields        []*SDK.TableFieldSchema
+		wantAdditive     []SchemaChange
+		wantIncompatible []SchemaChange
+		wantFields       []*SDK.TableFieldSchema
+	}{
+		{
+			name:       "no change",
+			oldFields:  []*SDK.TableFieldSchema{field("id", "INTEGER", "REQUIRED")},
+			newFields:  []*SDK.TableFieldSchema{field("ID", "int64", "required")},
+			wantFields: []*SDK.TableFieldSchema{field("id", "INTEGER", "REQUIRED")},
+		},
+		{
+			name:       "default mode",
+			oldFields:  []*SDK.TableFieldSchema{field("name", "STRING", "")},
+			newFields:  []*SDK.TableFieldSchema{field("name", "STRING", "NULLABLE")},
+			wantFields: []*SDK.TableFieldSchema{field("name", "STRING", "")},
+		},
+		{
+			name:      "add column as NULLABLE",
+			oldFields: []*SDK.TableFieldSchema{field("id", "INTEGER", "REQUIRED")},
+			newFields: []*SDK.TableFieldSchema{field("id", "INTEGER", "REQUIRED"), field("name", "STRING", "REQUIRED")},
+			wantAdditive: []SchemaChange{
+				{Type: SchemaChangeAddColumn, Name: "name", NewType: "STRING", NewMode: "NULLABLE"},
+			},
+			wantFields: []*SDK.TableFieldSchema{field("id", "INTEGER", "REQUIRED"), field("name", "STRING", "NULLABLE")},
+		},
+		{
+			name:      "relax mode",
+			oldFields: []*SDK.TableFieldSchema{field("id", "INTEGER", "REQUIRED")},
+			newFields: []*SDK.TableFieldSchema{field("id", "INTEGER", "NULLABLE")},
+			wantAdditive: []SchemaChange{
+				{Type: SchemaChangeRelaxMode, Name: "id", OldType: "INTEGER", OldMode: "REQUIRED", NewType: "INTEGER", NewMode: "NULLABLE"},
+			},
+			wantFields: []*SDK.TableFieldSchema{field("id", "INTEGER", "NULLABLE")},
+		},
+		{
+			name:       "NULLABLE accepts REQUIRED",
+			oldFields:  []*SDK.TableFieldSchema{field("id", "INTEGER", "NULLABLE")},
+			newFields:  []*SDK.TableFieldSchema{field("id", "INTEGER", "REQUIRED")},
+			wantFields: []*SDK.TableFieldSchema{field("id", "INTEGER", "NULLABLE")},
+		},
+		{
+			name:      "remove column",
+			oldFields: []*SDK.TableFieldSchema{field("id", "INTEGER", "REQUIRED"), field("name", "STRING", "")},
+			newFields: []*SDK.TableFieldSchema{field("id", "INTEGER", "REQUIRED")},
+			wantIncompatible: []SchemaChange{
+				{Type: SchemaChangeRemoveColumn, Name: "name", OldType: "STRING"},
+			},
+			wantFields: []*SDK.TableFieldSchema{field("id", "INTEGER", "REQUIRED"), field("name", "STRING", "")},
+		},
+		{
+			name:      "change type",
+			oldFields: []*SDK.TableFieldSchema{field("id", "INTEGER", "")},
+			newFields: []*SDK.TableFieldSchema{field("id", "STRING", "")},
+			wantIncompatible: []SchemaChange{
+				{Type: SchemaChangeChangeType, Name: "id", OldType: "INTEGER", NewType: "STRING"},
+			},
+			wantFields: []*SDK.TableFieldSchema{field("id", "INTEGER", "")},
+		},
+		{
+			name:      "change mode to REPEATED",
+			oldFields: []*SDK.TableFieldSchema{field("tags", "STRING", "NULLABLE")},
+			newFields: []*SDK.TableFieldSchema{field("tags", "STRING", "REPEATED")},
+			wantIncompatible: []SchemaChange{
+				{Type: SchemaChangeChangeMode, Name: "tags", OldType: "STRING", OldMode: "NULLABLE", NewType: "STRING", NewMode: "REPEATED"},
+			},
+			wantFields: []*SDK.TableFieldSchema{field("tags", "STRING", "NULLABLE")},
+		},
+		{
+			name: "nested record",
+			oldFields: []*SDK.TableFieldSchema{
+				field("address", "RECORD", "", field("city", "STRING", "REQUIRED"), field("zip", "STRING", "")),
+			},
+			newFields: []*SDK.TableFieldSchema{
+				field("address", "STRUCT", "", field("city", "STRING", ""), field("street", "RECORD", "REQUIRED", field("no", "INT64", "REQUIRED"))),
+			},
+			wantAdditive: []SchemaChange{
+				{Type: SchemaChangeRelaxMode, Name: "address.city", OldType: "STRING", OldMode: "REQUIRED", NewType: "STRING", NewMode: "NULLABLE"},
+				{Type: SchemaChangeAddColumn, Name: "address.street", NewType: "RECORD", NewMode: "NULLABLE"},
+			},
+			wantIncompatible: []SchemaChange{
+				{Type: SchemaChangeRemoveColumn, Name: "address.zip", OldType: "STRING"},
+			},
+			wantFields: []*SDK.TableFieldSchema{
+				field("address", "RECORD", "",
+					field("city", "STRING", "NULLABLE"),
+					field("zip", "STRING", ""),
+					field("street", "RECORD", "NULLABLE", field("no", "INT64", "NULLABLE")),
+				),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := DiffSchema(&SDK.TableSchema{Fields: tt.oldFields}, &SDK.TableSchema{Fields: tt.newFields})
+			if !reflect.DeepEqual(d.Additive, tt.wantAdditive) {
+				t.Errorf("Additive: got %#v, want %#v", d.Additive, tt.wantAdditive)
+			}
+			if !reflect.DeepEqual(d.Incompatible, tt.wantIncompatible) {
+				t.Errorf("Incompatible: got %#v, want %#v", d.Incompatible, tt.wantIncompatible)
+			}
+			if !reflect.DeepEqual(d.Schema.Fields, tt.wantFields) {
+				t.Errorf("Schema: got %#v, want %#v", d.Schema.Fields, tt.wantFields)
+			}
+			if got, want := d.HasChanges(), len(tt.wantAdditive)+len(tt.wantIncompatible) != 0; got != want {
+				t.Errorf("HasChanges: got %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestDiffSchemaNil(t *testing.T) {
+	d := DiffSchema(nil, &SDK.TableSchema{Fields: []*SDK.TableFieldSchema{{Name: "id", Type: "INTEGER", Mode: "REQUIRED"}}})
+	if !d.HasAdditive() || d.HasIncompatible() {
+		t.Errorf("got %#v, want only additive changes", d)
+	}
+
+	d = DiffSchema(&SDK.TableSchema{Fields: []*SDK.TableFieldSchema{{Name: "id", Type: "INTEGER"}}}, nil)
+	if d.HasAdditive() || !d.HasIncompatible() {
+		t.Errorf("got %#v, want only incompatible changes", d)
+	}
+}
+
+func TestDiffSchemaDoesNotModifyInput(t *testing.T) {
+	oldSchema := &SDK.TableSchema{Fields: []*SDK.TableFieldSchema{{Name: "id", Type: "INTEGER", Mode: "REQUIRED"}}}
+	newSchema := &SDK.TableSchema{Fields: []*SDK.TableFieldSchema{
+		{Name: "id", Type: "INTEGER", Mode: "NULLABLE"},
+		{Name: "name", Type: "STRING", Mode: "REQUIRED"},
+	}}
+	DiffSchema(oldSchema, newSchema)
+
+	if oldSchema.Fields[0].Mode != "REQUIRED" {
+		t.Errorf("old schema is modified: %#v", oldSchema.Fields[0])
+	}
+	if newSchema.Fields[1].Mode != "REQUIRED" {
+		t.Errorf("new schema is modified: %#v", newSchema.Fields[1])
+	}
+}
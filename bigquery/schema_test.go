@@ -0,0 +1,273 @@
+package bigquery
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	SDK "google.golang.org/api/bigquery/v2"
+)
+
+func TestDecimalString(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"0", "0"},
+		{"1.5", "1.5"},
+		{"-1.25", "-1.25"},
+		{"100", "100"},
+		{"0.123456789", "0.123456789"},
+		// BIGNUMERIC keeps the scale up to 38.
+		{"0.12345678901234567890123456789012345678", "0.12345678901234567890123456789012345678"},
+		{"-578960446186580977117854925043439539266.34992332820282019728792003956564819967", "-578960446186580977117854925043439539266.34992332820282019728792003956564819967"},
+		{"1/3", "0.33333333333333333333333333333333333333"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			r, ok := new(big.Rat).SetString(tt.value)
+			if !ok {
+				t.Fatalf("invalid test value: %s", tt.value)
+			}
+			if got := decimalString(r); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConvertStructToMap(t *testing.T) {
+	type nested struct {
+		Value *big.Rat `bigquery:"value"`
+	}
+	type row struct {
+		Name   string   `bigquery:"name"`
+		Price  *big.Rat `bigquery:"price"`
+		Nil    *big.Rat `bigquery:"nil"`
+		Nested nested   `bigquery:"nested"`
+		List   []*big.Rat
+		NoTag  string
+		Skip   string `bigquery:"-"`
+	}
+
+	price, _ := new(big.Rat).SetString("0.12345678901234567890123456789012345678")
+	got, err := convertStructToMap(row{
+		Name:   "a",
+		Price:  price,
+		Nested: nested{Value: big.NewRat(3, 2)},
+		List:   []*big.Rat{big.NewRat(1, 4)},
+		Skip:   "x",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	want := map[string]interface{}{
+		"name":   "a",
+		"price":  "0.12345678901234567890123456789012345678",
+		"nested": map[string]interface{}{"value": "1.5"},
+		"List":   []interface{}{"0.25"},
+		"NoTag":  "",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %#v, want %#v", got, want)
+	}
+}
+
+type schemaTestNode struct {
+	Name     string            `bigquery:"name"`
+	Next     *schemaTestNode   `bigquery:"next"`
+	Children []*schemaTestNode `bigquery:"children"`
+}
+
+type schemaTestSquash struct {
+	Self *schemaTestSquash `bigquery:",squash"`
+}
+
+type schemaTestIndirect struct {
+	Items []schemaTestIndirectItem `bigquery:"items"`
+}
+
+type schemaTestIndirectItem struct {
+	Parent *schemaTestIndirect `bigquery:"parent"`
+}
+
+func TestConvertToSchema(t *testing.T) {
+	type address struct {
+		City string `bigquery:"city"`
+	}
+	type base struct {
+		ID int64 `bigquery:"id"`
+	}
+	type row struct {
+		Base      base       `bigquery:",squash"`
+		Name      string     `bigquery:"name"`
+		Score     *float64   `bigquery:"score"`
+		Price     *big.Rat   `bigquery:"price"`
+		Hash      [4]byte    `bigquery:"hash"`
+		Tags      []string   `bigquery:"tags"`
+		Home      address    `bigquery:"home"`
+		Addresses []*address `bigquery:"addresses"`
+		Nullable  string     `bigquery:"nullable,nullable"`
+		Skip      string     `bigquery:"-"`
+	}
+
+	tests := []struct {
+		name    string
+		value   interface{}
+		want    []*SDK.TableFieldSchema
+		wantErr bool
+	}{
+		{"struct", row{}, []*SDK.TableFieldSchema{
+			{Name: "id", Type: "integer", Mode: modeRequired},
+			{Name: "name", Type: "string", Mode: modeRequired},
+			{Name: "score", Type: "float", Mode: modeNullable},
+			{Name: "price", Type: "bignumeric", Mode: modeNullable},
+			{Name: "hash", Type: "bytes", Mode: modeRequired},
+			{Name: "tags", Type: "string", Mode: modeRepeated},
+			{Name: "home", Type: typeRecord, Mode: modeRequired, Fields: []*SDK.TableFieldSchema{
+				{Name: "city", Type: "string", Mode: modeRequired},
+			}},
+			{Name: "addresses", Type: typeRecord, Mode: modeRepeated, Fields: []*SDK.TableFieldSchema{
+				{Name: "city", Type: "string", Mode: modeRequired},
+			}},
+			{Name: "nullable", Type: "string", Mode: modeNullable},
+		}, false},
+		{"same struct in siblings", struct {
+			A address `bigquery:"a"`
+			B address `bigquery:"b"`
+		}{}, []*SDK.TableFieldSchema{
+			{Name: "a", Type: typeRecord, Mode: modeRequired, Fields: []*SDK.TableFieldSchema{
+				{Name: "city", Type: "string", Mode: modeRequired},
+			}},
+			{Name: "b", Type: typeRecord, Mode: modeRequired, Fields: []*SDK.TableFieldSchema{
+				{Name: "city", Type: "string", Mode: modeRequired},
+			}},
+		}, false},
+		{"recursive pointer", schemaTestNode{}, nil, true},
+		{"recursive squash", schemaTestSquash{}, nil, true},
+		{"indirect recursive", &schemaTestIndirect{}, nil, true},
+		{"array of array", struct{ V [][]string }{}, nil, true},
+		{"not struct", "x", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := convertToSchema(tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %#v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+			if !reflect.DeepEqual(got.Fields, tt.want) {
+				t.Errorf("got %#v, want %#v", got.Fields, tt.want)
+			}
+		})
+	}
+}
+
+func TestConvertValueForRowBytes(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		want  interface{}
+	}{
+		{"slice", []byte("abc"), []byte("abc")},
+		{"array", [3]byte{'a', 'b', 'c'}, []byte("abc")},
+		{"pointer of array", &[3]byte{'a', 'b', 'c'}, []byte("abc")},
+		{"nil slice", []byte(nil), nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := convertValueForRow(reflect.ValueOf(tt.value))
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+type insertIDValueRow struct {
+	ID string
+}
+
+func (r insertIDValueRow) InsertID() string {
+	return "value-" + r.ID
+}
+
+type insertIDPointerRow struct {
+	ID string
+}
+
+func (r *insertIDPointerRow) InsertID() string {
+	return "pointer-" + r.ID
+}
+
+func TestGetInsertID(t *testing.T) {
+	type tagRow struct {
+		ID   int64  `bigquery:"id,insertid"`
+		Name string `bigquery:"name"`
+	}
+	type squashRow struct {
+		Row  tagRow `bigquery:",squash"`
+		Name string `bigquery:"name"`
+	}
+	type embeddedRow struct {
+		Row insertIDPointerRow `bigquery:",squash"`
+	}
+	id := "x"
+
+	tests := []struct {
+		name string
+		data interface{}
+		want string
+	}{
+		{"value receiver", insertIDValueRow{ID: "a"}, "value-a"},
+		{"value receiver of pointer", &insertIDValueRow{ID: "a"}, "value-a"},
+		{"pointer receiver", &insertIDPointerRow{ID: "a"}, "pointer-a"},
+		{"pointer receiver of value", insertIDPointerRow{ID: "a"}, "pointer-a"},
+		{"tag", tagRow{ID: 10}, "10"},
+		{"pointer tag", struct {
+			ID *string `bigquery:"id,insertid"`
+		}{ID: &id}, "x"},
+		{"nil pointer tag", struct {
+			ID *string `bigquery:"id,insertid"`
+		}{}, ""},
+		{"squash", squashRow{Row: tagRow{ID: 20}}, "20"},
+		{"squash pointer receiver", embeddedRow{Row: insertIDPointerRow{ID: "b"}}, "pointer-b"},
+		{"no insert id", struct{ Name string }{}, ""},
+		{"nil pointer", (*tagRow)(nil), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getInsertID(tt.data); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildTableDataInsertAllRequestInsertID(t *testing.T) {
+	req, err := buildTableDataInsertAllRequest([]insertIDPointerRow{{ID: "a"}, {ID: "b"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	var got []string
+	for _, row := range req.Rows {
+		got = append(got, row.InsertId)
+	}
+	if want := []string{"pointer-a", "pointer-b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
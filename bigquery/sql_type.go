@@ -0,0 +1,203 @@
+package bigquery
+
+import (
+	"fmt"
+	"strings"
+
+	SDK "google.golang.org/api/bigquery/v2"
+)
+
+// sqlTypeAliases maps the type names of legacy SQL and aliases into the type kinds of standard SQL.
+var sqlTypeAliases = map[string]string{
+	"INTEGER":    "INT64",
+	"INT":        "INT64",
+	"SMALLINT":   "INT64",
+	"BIGINT":     "INT64",
+	"TINYINT":    "INT64",
+	"BYTEINT":    "INT64",
+	"FLOAT":      "FLOAT64",
+	"BOOLEAN":    "BOOL",
+	"DECIMAL":    "NUMERIC",
+	"BIGDECIMAL": "BIGNUMERIC",
+}
+
+// ParseSQLType parses the data type of standard SQL. (e.g. INT64, ARRAY<STRING>, STRUCT<x FLOAT64, y FLOAT64>)
+// The parameters of the type are ignored. (e.g. STRING(10), NUMERIC(10, 2))
+func ParseSQLType(s string) (*SDK.StandardSqlDataType, error) {
+	p := &sqlTypeParser{
+		tokens: tokenizeSQLType(s),
+	}
+	typ, err := p.parseType()
+	if err != nil {
+		return nil, fmt.Errorf("invalid sql type; type=[%s] error=[%s]", s, err.Error())
+	}
+	if p.pos != len(p.tokens) {
+		return nil, fmt.Errorf("invalid sql type; type=[%s] error=[unexpected token: %s]", s, p.tokens[p.pos])
+	}
+	return typ, nil
+}
+
+// FormatSQLType returns the data type in standard SQL syntax.
+func FormatSQLType(typ *SDK.StandardSqlDataType) string {
+	if typ == nil {
+		return ""
+	}
+
+	switch typ.TypeKind {
+	case "ARRAY":
+		return "ARRAY<" + FormatSQLType(typ.ArrayElementType) + ">"
+	case "STRUCT":
+		var fields []string
+		if typ.StructType != nil {
+			for _, f := range typ.StructType.Fields {
+				fields = append(fields, strings.TrimSpace(f.Name+" "+FormatSQLType(f.Type)))
+			}
+		}
+		return "STRUCT<" + strings.Join(fields, ", ") + ">"
+	}
+	return typ.TypeKind
+}
+
+func tokenizeSQLType(s string) []string {
+	var tokens []string
+	start := -1
+	for i, r := range s {
+		switch {
+		case strings.ContainsRune("<>(),", r):
+			if start >= 0 {
+				tokens = append(tokens, s[start:i])
+				start = -1
+			}
+			tokens = append(tokens, string(r))
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			if start >= 0 {
+				tokens = append(tokens, s[start:i])
+				start = -1
+			}
+		case start < 0:
+			start = i
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, s[start:])
+	}
+	return tokens
+}
+
+type sqlTypeParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *sqlTypeParser) peek() string {
+	if p.pos >= len(p.tokens) {
+		return ""
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *sqlTypeParser) next() string {
+	tok := p.peek()
+	if tok != "" {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *sqlTypeParser) expect(tok string) error {
+	if got := p.next(); got != tok {
+		if got == "" {
+			return fmt.Errorf("expected %s but the type is ended", tok)
+		}
+		return fmt.Errorf("expected %s but got %s", tok, got)
+	}
+	return nil
+}
+
+func isSQLTypeSymbol(tok string) bool {
+	return len(tok) == 1 && strings.Contains("<>(),", tok)
+}
+
+func (p *sqlTypeParser) parseType() (*SDK.StandardSqlDataType, error) {
+	tok := p.next()
+	if tok == "" || isSQLTypeSymbol(tok) {
+		return nil, fmt.Errorf("expected type but got %q", tok)
+	}
+
+	kind := strings.ToUpper(tok)
+	if alias, ok := sqlTypeAliases[kind]; ok {
+		kind = alias
+	}
+	switch kind {
+	case "ARRAY":
+		if err := p.expect("<"); err != nil {
+			return nil, err
+		}
+		elem, err := p.parseType()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(">"); err != nil {
+			return nil, err
+		}
+		return &SDK.StandardSqlDataType{
+			TypeKind:         kind,
+			ArrayElementType: elem,
+		}, nil
+	case "STRUCT":
+		if err := p.expect("<"); err != nil {
+			return nil, err
+		}
+		fields, err := p.parseStructFields()
+		if err != nil {
+			return nil, err
+		}
+		return &SDK.StandardSqlDataType{
+			TypeKind: kind,
+			StructType: &SDK.StandardSqlStructType{
+				Fields: fields,
+			},
+		}, nil
+	}
+
+	// skip parameters of the type. (e.g. STRING(10))
+	if p.peek() == "(" {
+		for tok := p.next(); tok != ")"; tok = p.next() {
+			if tok == "" {
+				return nil, fmt.Errorf("parameters of %s are not closed", kind)
+			}
+		}
+	}
+	return &SDK.StandardSqlDataType{
+		TypeKind: kind,
+	}, nil
+}
+
+// parseStructFields parses the fields of STRUCT until the closing bracket.
+// The field name is optional. (e.g. STRUCT<INT64, name STRING>)
+func (p *sqlTypeParser) parseStructFields() ([]*SDK.StandardSqlField, error) {
+	var fields []*SDK.StandardSqlField
+	for {
+		field := &SDK.StandardSqlField{}
+		if p.pos+1 < len(p.tokens) && !isSQLTypeSymbol(p.tokens[p.pos+1]) {
+			field.Name = strings.Trim(p.next(), "`")
+		}
+		typ, err := p.parseType()
+		if err != nil {
+			return nil, err
+		}
+		field.Type = typ
+		fields = append(fields, field)
+
+		switch tok := p.next(); tok {
+		case ",":
+			continue
+		case ">":
+			return fields, nil
+		case "":
+			return nil, fmt.Errorf("STRUCT is not closed")
+		default:
+			return nil, fmt.Errorf("expected , or > but got %s", tok)
+		}
+	}
+}
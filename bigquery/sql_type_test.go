@@ -0,0 +1,84 @@
+package bigquery
+
+import (
+	"reflect"
+	"testing"
+
+	SDK "google.golang.org/api/bigquery/v2"
+)
+
+func TestParseSQLType(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{"scalar", "INT64", "INT64", false},
+		{"lower case", "string", "STRING", false},
+		{"alias", "INTEGER", "INT64", false},
+		{"legacy types", "STRUCT<a FLOAT, b BOOLEAN, c DECIMAL, d BIGDECIMAL>", "STRUCT<a FLOAT64, b BOOL, c NUMERIC, d BIGNUMERIC>", false},
+		{"parameterized string", "STRING(10)", "STRING", false},
+		{"parameterized numeric", "NUMERIC(10, 2)", "NUMERIC", false},
+		{"array", "ARRAY<STRING>", "ARRAY<STRING>", false},
+		{"struct", "STRUCT<x FLOAT64, y FLOAT64>", "STRUCT<x FLOAT64, y FLOAT64>", false},
+		{"struct without field name", "STRUCT<INT64, name STRING>", "STRUCT<INT64, name STRING>", false},
+		{"quoted field name", "STRUCT<`from` STRING>", "STRUCT<from STRING>", false},
+		{"array of struct", "ARRAY<STRUCT<id INT64, tags ARRAY<STRING(10)>, point STRUCT<x FLOAT64, y FLOAT64>>>",
+			"ARRAY<STRUCT<id INT64, tags ARRAY<STRING>, point STRUCT<x FLOAT64, y FLOAT64>>>", false},
+		{"whitespace", " ARRAY <\n\tSTRUCT< id  INT64 ,name STRING >\r\n> ", "ARRAY<STRUCT<id INT64, name STRING>>", false},
+		{"empty", "", "", true},
+		{"only symbol", "<", "", true},
+		{"array without element", "ARRAY<>", "", true},
+		{"array not closed", "ARRAY<STRING", "", true},
+		{"struct not closed", "STRUCT<x INT64", "", true},
+		{"struct with extra token", "STRUCT<x INT64 y>", "", true},
+		{"parameters not closed", "STRING(10", "", true},
+		{"trailing token", "INT64 STRING", "", true},
+		{"trailing bracket", "ARRAY<STRING>>", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			typ, err := ParseSQLType(tt.value)
+			switch {
+			case tt.wantErr:
+				if err == nil {
+					t.Errorf("expected error, got %#v", typ)
+				}
+				return
+			case err != nil:
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+			if got := FormatSQLType(typ); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSQLTypeNested(t *testing.T) {
+	got, err := ParseSQLType("ARRAY<STRUCT<id INT64, tags ARRAY<STRING>>>")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	want := &SDK.StandardSqlDataType{
+		TypeKind: "ARRAY",
+		ArrayElementType: &SDK.StandardSqlDataType{
+			TypeKind: "STRUCT",
+			StructType: &SDK.StandardSqlStructType{
+				Fields: []*SDK.StandardSqlField{
+					{Name: "id", Type: &SDK.StandardSqlDataType{TypeKind: "INT64"}},
+					{Name: "tags", Type: &SDK.StandardSqlDataType{
+						TypeKind:         "ARRAY",
+						ArrayElementType: &SDK.StandardSqlDataType{TypeKind: "STRING"},
+					}},
+				},
+			},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %#v, want %#v", got, want)
+	}
+}
@@ -0,0 +1,234 @@
+package storageread
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"time"
+
+	"cloud.google.com/go/bigquery/storage/apiv1/storagepb"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	SDK "google.golang.org/api/bigquery/v2"
+)
+
+const arrowExtensionName = "ARROW:extension:name"
+
+// arrowDecoder decodes Arrow record batches of Storage Read API.
+type arrowDecoder struct {
+	serializedSchema []byte
+	table            *SDK.TableSchema
+}
+
+func newArrowDecoder(serializedSchema []byte) (*arrowDecoder, error) {
+	r, err := ipc.NewReader(bytes.NewReader(serializedSchema))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Release()
+
+	fields, err := arrowFieldSchemas(r.Schema().Fields())
+	if err != nil {
+		return nil, err
+	}
+	return &arrowDecoder{
+		serializedSchema: serializedSchema,
+		table: &SDK.TableSchema{
+			Fields: fields,
+		},
+	}, nil
+}
+
+func (d *arrowDecoder) schema() *SDK.TableSchema {
+	return d.table
+}
+
+func (d *arrowDecoder) decode(resp *storagepb.ReadRowsResponse) ([]*SDK.TableRow, error) {
+	batch := resp.GetArrowRecordBatch()
+	if batch == nil {
+		return nil, nil
+	}
+
+	// record batch message is not self-describing, so it is read with the schema message.
+	buf := make([]byte, 0, len(d.serializedSchema)+len(batch.SerializedRecordBatch))
+	buf = append(buf, d.serializedSchema...)
+	buf = append(buf, batch.SerializedRecordBatch...)
+	r, err := ipc.NewReader(bytes.NewReader(buf))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Release()
+
+	var result []*SDK.TableRow
+	for r.Next() {
+		rec := r.Record()
+		columns := rec.Columns()
+		for i := 0; i < int(rec.NumRows()); i++ {
+			values := make([]interface{}, len(columns))
+			for j, col := range columns {
+				v, err := arrowCellValue(d.table.Fields[j], col, i)
+				if err != nil {
+					return nil, err
+				}
+				values[j] = v
+			}
+			result = append(result, newTableRow(values))
+		}
+	}
+	return result, r.Err()
+}
+
+func arrowFieldSchemas(fields []arrow.Field) ([]*SDK.TableFieldSchema, error) {
+	list := make([]*SDK.TableFieldSchema, len(fields))
+	for i, f := range fields {
+		mode := "REQUIRED"
+		if f.Nullable {
+			mode = "NULLABLE"
+		}
+
+		dt := f.Type
+		if lt, ok := dt.(*arrow.ListType); ok {
+			mode = "REPEATED"
+			dt = lt.Elem()
+		}
+
+		schema := &SDK.TableFieldSchema{
+			Name: f.Name,
+			Mode: mode,
+		}
+		switch t := dt.(type) {
+		case *arrow.StructType:
+			children, err := arrowFieldSchemas(t.Fields())
+			if err != nil {
+				return nil, err
+			}
+			schema.Type = "RECORD"
+			schema.Fields = children
+		case *arrow.Decimal128Type:
+			schema.Type = "NUMERIC"
+			schema.Scale = int64(t.Scale)
+		case *arrow.Decimal256Type:
+			schema.Type = "BIGNUMERIC"
+			schema.Scale = int64(t.Scale)
+		default:
+			typ, err := arrowColumnType(f, dt)
+			if err != nil {
+				return nil, err
+			}
+			schema.Type = typ
+		}
+		list[i] = schema
+	}
+	return list, nil
+}
+
+func arrowColumnType(f arrow.Field, dt arrow.DataType) (string, error) {
+	if idx := f.Metadata.FindKey(arrowExtensionName); idx >= 0 {
+		switch f.Metadata.Values()[idx] {
+		case "google:sqlType:geography":
+			return "GEOGRAPHY", nil
+		case "google:sqlType:json":
+			return "JSON", nil
+		}
+	}
+
+	switch t := dt.(type) {
+	case *arrow.Int64Type:
+		return "INTEGER", nil
+	case *arrow.Float64Type:
+		return "FLOAT", nil
+	case *arrow.BooleanType:
+		return "BOOLEAN", nil
+	case *arrow.StringType:
+		return "STRING", nil
+	case *arrow.BinaryType:
+		return "BYTES", nil
+	case *arrow.Date32Type:
+		return "DATE", nil
+	case *arrow.Time64Type:
+		return "TIME", nil
+	case *arrow.TimestampType:
+		if t.TimeZone == "" {
+			return "DATETIME", nil
+		}
+		return "TIMESTAMP", nil
+	}
+	return "", fmt.Errorf("unsupported arrow type; name=[%s] type=[%s]", f.Name, dt)
+}
+
+// arrowCellValue converts the value of Arrow array into the cell value of Tabledata.List.
+func arrowCellValue(f *SDK.TableFieldSchema, arr arrow.Array, i int) (interface{}, error) {
+	if arr.IsNull(i) {
+		return nil, nil
+	}
+	if f.Mode != "REPEATED" {
+		return arrowSingleValue(f, arr, i)
+	}
+
+	list, ok := arr.(*array.List)
+	if !ok {
+		return nil, fmt.Errorf("the arrow value is not list; name=[%s]", f.Name)
+	}
+	start, end := list.ValueOffsets(i)
+	elems := list.ListValues()
+	values := make([]interface{}, 0, end-start)
+	for j := start; j < end; j++ {
+		v, err := arrowSingleValue(f, elems, int(j))
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+	return newRepeatedCell(values), nil
+}
+
+func arrowSingleValue(f *SDK.TableFieldSchema, arr arrow.Array, i int) (interface{}, error) {
+	if arr.IsNull(i) {
+		return nil, nil
+	}
+
+	var v interface{}
+	switch a := arr.(type) {
+	case *array.Struct:
+		values := make([]interface{}, a.NumField())
+		for j := range values {
+			value, err := arrowCellValue(f.Fields[j], a.Field(j), i)
+			if err != nil {
+				return nil, err
+			}
+			values[j] = value
+		}
+		return newRecordCell(values), nil
+	case *array.Int64:
+		v = a.Value(i)
+	case *array.Float64:
+		v = a.Value(i)
+	case *array.Boolean:
+		v = a.Value(i)
+	case *array.String:
+		v = a.Value(i)
+	case *array.Binary:
+		v = a.Value(i)
+	case *array.Date32:
+		v = a.Value(i).ToTime()
+	case *array.Time64:
+		unit := a.DataType().(*arrow.Time64Type).Unit
+		v = time.Duration(a.Value(i)) * unit.Multiplier()
+	case *array.Timestamp:
+		unit := a.DataType().(*arrow.TimestampType).Unit
+		v = a.Value(i).ToTime(unit)
+	case *array.Decimal128:
+		v = decimalToRat(a.Value(i).BigInt(), f.Scale)
+	case *array.Decimal256:
+		v = decimalToRat(a.Value(i).BigInt(), f.Scale)
+	default:
+		return nil, fmt.Errorf("unsupported arrow array; name=[%s] type=[%s]", f.Name, arr.DataType())
+	}
+	return newScalarCell(f.Type, int32(f.Scale), v)
+}
+
+func decimalToRat(unscaled *big.Int, scale int64) *big.Rat {
+	denom := new(big.Int).Exp(big.NewInt(10), big.NewInt(scale), nil)
+	return new(big.Rat).SetFrac(unscaled, denom)
+}
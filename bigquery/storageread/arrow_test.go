@@ -0,0 +1,223 @@
+package storageread
+
+import (
+	"bytes"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/bigquery/storage/apiv1/storagepb"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/decimal128"
+	"github.com/apache/arrow-go/v18/arrow/decimal256"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	SDK "google.golang.org/api/bigquery/v2"
+)
+
+// arrowEOSLength is the length of the end-of-stream marker of Arrow IPC stream.
+const arrowEOSLength = 8
+
+// newArrowTestData serializes the schema and the record batch separately, like the read session of BigQuery.
+func newArrowTestData(t *testing.T, schema *arrow.Schema, rec arrow.Record) (serializedSchema []byte, resp *storagepb.ReadRowsResponse) {
+	var schemaBuf bytes.Buffer
+	w := ipc.NewWriter(&schemaBuf, ipc.WithSchema(schema))
+	if err := w.Close(); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	var streamBuf bytes.Buffer
+	w = ipc.NewWriter(&streamBuf, ipc.WithSchema(schema))
+	if err := w.Write(rec); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	schemaLen := schemaBuf.Len() - arrowEOSLength
+	stream := streamBuf.Bytes()
+	return schemaBuf.Bytes()[:schemaLen], &storagepb.ReadRowsResponse{
+		Rows: &storagepb.ReadRowsResponse_ArrowRecordBatch{
+			ArrowRecordBatch: &storagepb.ArrowRecordBatch{
+				SerializedRecordBatch: stream[schemaLen : len(stream)-arrowEOSLength],
+			},
+		},
+		RowCount: rec.NumRows(),
+	}
+}
+
+func TestArrowDecoder(t *testing.T) {
+	ts := time.Date(2020, 1, 2, 3, 4, 5, 123456000, time.UTC)
+	geography := arrow.NewMetadata([]string{arrowExtensionName}, []string{"google:sqlType:geography"})
+	bigNumeric := new(big.Int).Mul(big.NewInt(25), new(big.Int).Exp(big.NewInt(10), big.NewInt(36), nil))
+
+	tests := []struct {
+		name      string
+		field     arrow.Field
+		appendFn  func(b array.Builder)
+		wantType  string
+		wantMode  string
+		wantScale int64
+		wantCell  interface{}
+	}{
+		{"int64", arrow.Field{Type: arrow.PrimitiveTypes.Int64}, func(b array.Builder) {
+			b.(*array.Int64Builder).Append(42)
+		}, "INTEGER", "REQUIRED", 0, "42"},
+		{"nullable string", arrow.Field{Type: arrow.BinaryTypes.String, Nullable: true}, func(b array.Builder) {
+			b.(*array.StringBuilder).Append("alice")
+		}, "STRING", "NULLABLE", 0, "alice"},
+		{"null", arrow.Field{Type: arrow.BinaryTypes.String, Nullable: true}, func(b array.Builder) {
+			b.AppendNull()
+		}, "STRING", "NULLABLE", 0, nil},
+		{"float64", arrow.Field{Type: arrow.PrimitiveTypes.Float64, Nullable: true}, func(b array.Builder) {
+			b.(*array.Float64Builder).Append(1.5)
+		}, "FLOAT", "NULLABLE", 0, "1.5"},
+		{"boolean", arrow.Field{Type: arrow.FixedWidthTypes.Boolean}, func(b array.Builder) {
+			b.(*array.BooleanBuilder).Append(true)
+		}, "BOOLEAN", "REQUIRED", 0, "true"},
+		{"binary", arrow.Field{Type: arrow.BinaryTypes.Binary}, func(b array.Builder) {
+			b.(*array.BinaryBuilder).Append([]byte("abc"))
+		}, "BYTES", "REQUIRED", 0, "YWJj"},
+		{"decimal128", arrow.Field{Type: &arrow.Decimal128Type{Precision: 38, Scale: 9}}, func(b array.Builder) {
+			b.(*array.Decimal128Builder).Append(decimal128.FromI64(1500000000))
+		}, "NUMERIC", "REQUIRED", 9, "1.500000000"},
+		{"decimal256", arrow.Field{Type: &arrow.Decimal256Type{Precision: 76, Scale: 38}}, func(b array.Builder) {
+			b.(*array.Decimal256Builder).Append(decimal256.FromBigInt(bigNumeric))
+		}, "BIGNUMERIC", "REQUIRED", 38, "0.25000000000000000000000000000000000000"},
+		{"timestamp", arrow.Field{Type: &arrow.TimestampType{Unit: arrow.Microsecond, TimeZone: "UTC"}}, func(b array.Builder) {
+			b.(*array.TimestampBuilder).Append(arrow.Timestamp(ts.UnixNano() / 1000))
+		}, "TIMESTAMP", "REQUIRED", 0, "1577934245.123456"},
+		{"datetime", arrow.Field{Type: &arrow.TimestampType{Unit: arrow.Microsecond}}, func(b array.Builder) {
+			b.(*array.TimestampBuilder).Append(arrow.Timestamp(ts.UnixNano() / 1000))
+		}, "DATETIME", "REQUIRED", 0, "2020-01-02 03:04:05.123456"},
+		{"date32", arrow.Field{Type: arrow.FixedWidthTypes.Date32}, func(b array.Builder) {
+			b.(*array.Date32Builder).Append(arrow.Date32FromTime(ts))
+		}, "DATE", "REQUIRED", 0, "2020-01-02"},
+		{"time64", arrow.Field{Type: arrow.FixedWidthTypes.Time64us}, func(b array.Builder) {
+			b.(*array.Time64Builder).Append(arrow.Time64((3*time.Hour + 4*time.Minute + 5*time.Second + 123456*time.Microsecond) / time.Microsecond))
+		}, "TIME", "REQUIRED", 0, "03:04:05.123456"},
+		{"geography", arrow.Field{Type: arrow.BinaryTypes.String, Metadata: geography}, func(b array.Builder) {
+			b.(*array.StringBuilder).Append("POINT(1 2)")
+		}, "GEOGRAPHY", "REQUIRED", 0, "POINT(1 2)"},
+		{"list", arrow.Field{Type: arrow.ListOf(arrow.BinaryTypes.String)}, func(b array.Builder) {
+			lb := b.(*array.ListBuilder)
+			lb.Append(true)
+			lb.ValueBuilder().(*array.StringBuilder).AppendValues([]string{"a", "b"}, nil)
+		}, "STRING", "REPEATED", 0, []interface{}{map[string]interface{}{"v": "a"}, map[string]interface{}{"v": "b"}}},
+		{"struct", arrow.Field{Type: arrow.StructOf(arrow.Field{Name: "city", Type: arrow.BinaryTypes.String, Nullable: true}), Nullable: true}, func(b array.Builder) {
+			sb := b.(*array.StructBuilder)
+			sb.Append(true)
+			sb.FieldBuilder(0).(*array.StringBuilder).Append("tokyo")
+		}, "RECORD", "NULLABLE", 0, map[string]interface{}{"f": []interface{}{map[string]interface{}{"v": "tokyo"}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+			defer mem.AssertSize(t, 0)
+
+			tt.field.Name = "col"
+			schema := arrow.NewSchema([]arrow.Field{tt.field}, nil)
+			b := array.NewRecordBuilder(mem, schema)
+			defer b.Release()
+			tt.appendFn(b.Field(0))
+			rec := b.NewRecord()
+			defer rec.Release()
+
+			serializedSchema, resp := newArrowTestData(t, schema, rec)
+			dec, err := newArrowDecoder(serializedSchema)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+
+			field := dec.schema().Fields[0]
+			if field.Type != tt.wantType || field.Mode != tt.wantMode || field.Scale != tt.wantScale {
+				t.Errorf("schema: got %s %s %d, want %s %s %d", field.Type, field.Mode, field.Scale, tt.wantType, tt.wantMode, tt.wantScale)
+			}
+
+			rows, err := dec.decode(resp)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+			if len(rows) != 1 {
+				t.Fatalf("got %d rows, want 1", len(rows))
+			}
+			if got := rows[0].F[0].V; !reflect.DeepEqual(got, tt.wantCell) {
+				t.Errorf("got %#v, want %#v", got, tt.wantCell)
+			}
+		})
+	}
+}
+
+func TestArrowDecoderRows(t *testing.T) {
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "name", Type: arrow.BinaryTypes.String, Nullable: true},
+	}, nil)
+	b := array.NewRecordBuilder(memory.NewGoAllocator(), schema)
+	defer b.Release()
+	b.Field(0).(*array.Int64Builder).AppendValues([]int64{1, 2}, nil)
+	b.Field(1).(*array.StringBuilder).AppendValues([]string{"a", ""}, []bool{true, false})
+	rec := b.NewRecord()
+	defer rec.Release()
+
+	serializedSchema, resp := newArrowTestData(t, schema, rec)
+	dec, err := newArrowDecoder(serializedSchema)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	wantSchema := []*SDK.TableFieldSchema{
+		{Name: "id", Type: "INTEGER", Mode: "REQUIRED"},
+		{Name: "name", Type: "STRING", Mode: "NULLABLE"},
+	}
+	if !reflect.DeepEqual(dec.schema().Fields, wantSchema) {
+		t.Errorf("schema: got %#v, want %#v", dec.schema().Fields, wantSchema)
+	}
+
+	rows, err := dec.decode(resp)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	want := []*SDK.TableRow{
+		{F: []*SDK.TableCell{{V: "1"}, {V: "a"}}},
+		{F: []*SDK.TableCell{{V: "2"}, {V: nil}}},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("got %#v, want %#v", rows, want)
+	}
+
+	// the response without rows.
+	rows, err = dec.decode(&storagepb.ReadRowsResponse{})
+	if err != nil || len(rows) != 0 {
+		t.Errorf("got %#v, %v, want no rows", rows, err)
+	}
+}
+
+func TestNewArrowDecoderError(t *testing.T) {
+	schema := arrow.NewSchema([]arrow.Field{{Name: "col", Type: arrow.PrimitiveTypes.Int32}}, nil)
+	b := array.NewRecordBuilder(memory.NewGoAllocator(), schema)
+	defer b.Release()
+	rec := b.NewRecord()
+	defer rec.Release()
+	unsupported, _ := newArrowTestData(t, schema, rec)
+
+	tests := []struct {
+		name   string
+		schema []byte
+	}{
+		{"invalid schema", []byte("invalid")},
+		{"unsupported type", unsupported},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := newArrowDecoder(tt.schema); err == nil {
+				t.Errorf("expected error")
+			}
+		})
+	}
+}
@@ -0,0 +1,303 @@
+package storageread
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"cloud.google.com/go/bigquery/storage/apiv1/storagepb"
+	"github.com/linkedin/goavro/v2"
+	SDK "google.golang.org/api/bigquery/v2"
+)
+
+// avroDecoder decodes Avro rows of Storage Read API.
+type avroDecoder struct {
+	codec  *goavro.Codec
+	fields []avroField
+	table  *SDK.TableSchema
+}
+
+func newAvroDecoder(schemaJSON string) (*avroDecoder, error) {
+	codec, err := goavro.NewCodec(schemaJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	var root interface{}
+	if err := json.Unmarshal([]byte(schemaJSON), &root); err != nil {
+		return nil, err
+	}
+	typ, err := parseAvroType(root, map[string]avroType{})
+	if err != nil {
+		return nil, err
+	}
+	if typ.bqType != "RECORD" {
+		return nil, fmt.Errorf("the avro schema of the read session is not record; type=[%s]", typ.bqType)
+	}
+
+	return &avroDecoder{
+		codec:  codec,
+		fields: typ.fields,
+		table: &SDK.TableSchema{
+			Fields: avroFieldSchemas(typ.fields),
+		},
+	}, nil
+}
+
+func (d *avroDecoder) schema() *SDK.TableSchema {
+	return d.table
+}
+
+func (d *avroDecoder) decode(resp *storagepb.ReadRowsResponse) ([]*SDK.TableRow, error) {
+	rows := resp.GetAvroRows()
+	if rows == nil {
+		return nil, nil
+	}
+
+	buf := rows.SerializedBinaryRows
+	result := make([]*SDK.TableRow, 0, resp.RowCount)
+	for len(buf) != 0 {
+		native, rest, err := d.codec.NativeFromBinary(buf)
+		if err != nil {
+			return nil, err
+		}
+		buf = rest
+
+		m, ok := native.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("the avro row is not record; value=[%v]", native)
+		}
+		values, err := avroRecordValues(d.fields, m)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, newTableRow(values))
+	}
+	return result, nil
+}
+
+// avroType is Avro type mapped into BigQuery column type.
+type avroType struct {
+	bqType   string
+	scale    int32
+	nullable bool
+	repeated bool
+	fields   []avroField
+}
+
+type avroField struct {
+	name string
+	typ  avroType
+}
+
+// parseAvroType parses Avro type definition generated by BigQuery.
+// named holds the record types defined before to resolve the reference by name.
+func parseAvroType(v interface{}, named map[string]avroType) (avroType, error) {
+	switch vv := v.(type) {
+	case string:
+		if t, ok := named[vv]; ok {
+			return t, nil
+		}
+		return avroPrimitiveType(vv, "", "")
+	case []interface{}:
+		// union of null and the type.
+		var result *avroType
+		for _, elem := range vv {
+			if elem == "null" {
+				continue
+			}
+			if result != nil {
+				return avroType{}, fmt.Errorf("unsupported avro union type; type=[%v]", vv)
+			}
+			t, err := parseAvroType(elem, named)
+			if err != nil {
+				return avroType{}, err
+			}
+			result = &t
+		}
+		if result == nil {
+			return avroType{}, fmt.Errorf("unsupported avro union type; type=[%v]", vv)
+		}
+		result.nullable = true
+		return *result, nil
+	case map[string]interface{}:
+		return parseAvroComplexType(vv, named)
+	}
+	return avroType{}, fmt.Errorf("unsupported avro type; type=[%v]", v)
+}
+
+func parseAvroComplexType(m map[string]interface{}, named map[string]avroType) (avroType, error) {
+	typeName, _ := m["type"].(string)
+	switch typeName {
+	case "record":
+		list, _ := m["fields"].([]interface{})
+		fields := make([]avroField, 0, len(list))
+		for _, f := range list {
+			fm, ok := f.(map[string]interface{})
+			if !ok {
+				return avroType{}, fmt.Errorf("unsupported avro record field; field=[%v]", f)
+			}
+			name, _ := fm["name"].(string)
+			t, err := parseAvroType(fm["type"], named)
+			if err != nil {
+				return avroType{}, err
+			}
+			fields = append(fields, avroField{name: name, typ: t})
+		}
+		t := avroType{
+			bqType: "RECORD",
+			fields: fields,
+		}
+		if name, ok := m["name"].(string); ok {
+			named[name] = t
+		}
+		return t, nil
+	case "array":
+		t, err := parseAvroType(m["items"], named)
+		if err != nil {
+			return avroType{}, err
+		}
+		t.repeated = true
+		return t, nil
+	case "":
+		// nested type definition. (e.g. {"type": {"type": "long", "logicalType": "timestamp-micros"}})
+		return parseAvroType(m["type"], named)
+	}
+
+	logicalType, _ := m["logicalType"].(string)
+	sqlType, _ := m["sqlType"].(string)
+	t, err := avroPrimitiveType(typeName, logicalType, sqlType)
+	if err != nil {
+		return avroType{}, err
+	}
+	if scale, ok := m["scale"].(float64); ok {
+		t.scale = int32(scale)
+	}
+	if precision, ok := m["precision"].(float64); ok && t.bqType == "NUMERIC" && (precision > 38 || t.scale > 9) {
+		t.bqType = "BIGNUMERIC"
+	}
+	return t, nil
+}
+
+func avroPrimitiveType(typeName, logicalType, sqlType string) (avroType, error) {
+	switch logicalType {
+	case "timestamp-micros", "timestamp-millis":
+		return avroType{bqType: "TIMESTAMP"}, nil
+	case "time-micros", "time-millis":
+		return avroType{bqType: "TIME"}, nil
+	case "date":
+		return avroType{bqType: "DATE"}, nil
+	case "datetime", "local-timestamp-micros":
+		return avroType{bqType: "DATETIME"}, nil
+	case "decimal":
+		return avroType{bqType: "NUMERIC"}, nil
+	}
+
+	switch sqlType {
+	case "DATETIME", "GEOGRAPHY", "JSON":
+		return avroType{bqType: sqlType}, nil
+	}
+
+	switch typeName {
+	case "string":
+		return avroType{bqType: "STRING"}, nil
+	case "long", "int":
+		return avroType{bqType: "INTEGER"}, nil
+	case "double", "float":
+		return avroType{bqType: "FLOAT"}, nil
+	case "boolean":
+		return avroType{bqType: "BOOLEAN"}, nil
+	case "bytes":
+		return avroType{bqType: "BYTES"}, nil
+	}
+	return avroType{}, fmt.Errorf("unsupported avro type; type=[%s] logicalType=[%s]", typeName, logicalType)
+}
+
+func avroFieldSchemas(fields []avroField) []*SDK.TableFieldSchema {
+	list := make([]*SDK.TableFieldSchema, len(fields))
+	for i, f := range fields {
+		mode := "REQUIRED"
+		switch {
+		case f.typ.repeated:
+			mode = "REPEATED"
+		case f.typ.nullable:
+			mode = "NULLABLE"
+		}
+
+		list[i] = &SDK.TableFieldSchema{
+			Name: f.name,
+			Type: f.typ.bqType,
+			Mode: mode,
+		}
+		if f.typ.bqType == "RECORD" {
+			list[i].Fields = avroFieldSchemas(f.typ.fields)
+		}
+	}
+	return list
+}
+
+func avroRecordValues(fields []avroField, m map[string]interface{}) ([]interface{}, error) {
+	values := make([]interface{}, len(fields))
+	for i, f := range fields {
+		v, err := f.typ.cellValue(m[f.name])
+		if err != nil {
+			return nil, err
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
+// cellValue converts native value of goavro into the cell value of Tabledata.List.
+func (t avroType) cellValue(v interface{}) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+	if !t.repeated {
+		return t.singleValue(v)
+	}
+
+	list, ok := v.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("the avro value is not array; value=[%v]", v)
+	}
+	values := make([]interface{}, len(list))
+	for i, elem := range list {
+		value, err := t.singleValue(elem)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+	}
+	return newRepeatedCell(values), nil
+}
+
+func (t avroType) singleValue(v interface{}) (interface{}, error) {
+	if t.nullable {
+		// goavro returns union value as {"type name": value}.
+		if m, ok := v.(map[string]interface{}); ok && len(m) == 1 {
+			for _, value := range m {
+				v = value
+			}
+		}
+	}
+	if v == nil {
+		return nil, nil
+	}
+
+	if t.bqType != "RECORD" {
+		if f, ok := v.(float32); ok {
+			v = float64(f)
+		}
+		return newScalarCell(t.bqType, t.scale, v)
+	}
+
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("the avro value is not record; value=[%v]", v)
+	}
+	values, err := avroRecordValues(t.fields, m)
+	if err != nil {
+		return nil, err
+	}
+	return newRecordCell(values), nil
+}
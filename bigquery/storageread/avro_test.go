@@ -0,0 +1,163 @@
+package storageread
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/bigquery/storage/apiv1/storagepb"
+	"github.com/linkedin/goavro/v2"
+	SDK "google.golang.org/api/bigquery/v2"
+)
+
+// newAvroTestSchema returns Avro schema of the record with a column, like the read session of BigQuery.
+func newAvroTestSchema(fieldType string) string {
+	return `{"type": "record", "name": "__root__", "fields": [{"name": "col", "type": ` + fieldType + `}]}`
+}
+
+// newAvroTestRows encodes the rows by the schema.
+func newAvroTestRows(t *testing.T, schema string, rows ...map[string]interface{}) *storagepb.ReadRowsResponse {
+	codec, err := goavro.NewCodec(schema)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	var buf []byte
+	for _, row := range rows {
+		buf, err = codec.BinaryFromNative(buf, row)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err.Error())
+		}
+	}
+	return &storagepb.ReadRowsResponse{
+		Rows: &storagepb.ReadRowsResponse_AvroRows{
+			AvroRows: &storagepb.AvroRows{SerializedBinaryRows: buf},
+		},
+		RowCount: int64(len(rows)),
+	}
+}
+
+func TestAvroDecoder(t *testing.T) {
+	ts := time.Date(2020, 1, 2, 3, 4, 5, 123456000, time.UTC)
+
+	tests := []struct {
+		name      string
+		fieldType string
+		value     interface{}
+		wantType  string
+		wantMode  string
+		wantCell  interface{}
+	}{
+		{"long", `"long"`, int64(42), "INTEGER", "REQUIRED", "42"},
+		{"nullable string", `["null", "string"]`, goavro.Union("string", "alice"), "STRING", "NULLABLE", "alice"},
+		{"null", `["null", "string"]`, nil, "STRING", "NULLABLE", nil},
+		{"double", `["null", "double"]`, goavro.Union("double", 1.5), "FLOAT", "NULLABLE", "1.5"},
+		{"boolean", `"boolean"`, true, "BOOLEAN", "REQUIRED", "true"},
+		{"bytes", `"bytes"`, []byte("abc"), "BYTES", "REQUIRED", "YWJj"},
+		{"numeric", `{"type": "bytes", "logicalType": "decimal", "precision": 38, "scale": 9}`,
+			big.NewRat(3, 2), "NUMERIC", "REQUIRED", "1.500000000"},
+		{"bignumeric", `{"type": "bytes", "logicalType": "decimal", "precision": 77, "scale": 38}`,
+			big.NewRat(1, 4), "BIGNUMERIC", "REQUIRED", "0.25000000000000000000000000000000000000"},
+		{"timestamp", `["null", {"type": "long", "logicalType": "timestamp-micros"}]`,
+			goavro.Union("long.timestamp-micros", ts), "TIMESTAMP", "NULLABLE", "1577934245.123456"},
+		{"date", `{"type": "int", "logicalType": "date"}`,
+			time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC), "DATE", "REQUIRED", "2020-01-02"},
+		{"time", `{"type": "long", "logicalType": "time-micros"}`,
+			3*time.Hour + 4*time.Minute + 5*time.Second + 123456*time.Microsecond, "TIME", "REQUIRED", "03:04:05.123456"},
+		{"datetime", `{"type": "string", "sqlType": "DATETIME"}`, "2020-01-02T03:04:05", "DATETIME", "REQUIRED", "2020-01-02T03:04:05"},
+		{"json", `{"type": "string", "sqlType": "JSON"}`, `{"a":1}`, "JSON", "REQUIRED", `{"a":1}`},
+		{"repeated", `{"type": "array", "items": "string"}`, []interface{}{"a", "b"}, "STRING", "REPEATED",
+			[]interface{}{map[string]interface{}{"v": "a"}, map[string]interface{}{"v": "b"}}},
+		{"empty repeated", `{"type": "array", "items": "long"}`, []interface{}{}, "INTEGER", "REPEATED", []interface{}{}},
+		{"record", `["null", {"type": "record", "name": "address", "fields": [{"name": "city", "type": ["null", "string"]}]}]`,
+			goavro.Union("address", map[string]interface{}{"city": goavro.Union("string", "tokyo")}), "RECORD", "NULLABLE",
+			map[string]interface{}{"f": []interface{}{map[string]interface{}{"v": "tokyo"}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema := newAvroTestSchema(tt.fieldType)
+			dec, err := newAvroDecoder(schema)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+
+			field := dec.schema().Fields[0]
+			if field.Type != tt.wantType || field.Mode != tt.wantMode {
+				t.Errorf("schema: got %s %s, want %s %s", field.Type, field.Mode, tt.wantType, tt.wantMode)
+			}
+
+			rows, err := dec.decode(newAvroTestRows(t, schema, map[string]interface{}{"col": tt.value}))
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+			if len(rows) != 1 {
+				t.Fatalf("got %d rows, want 1", len(rows))
+			}
+			if got := rows[0].F[0].V; !reflect.DeepEqual(got, tt.wantCell) {
+				t.Errorf("got %#v, want %#v", got, tt.wantCell)
+			}
+		})
+	}
+}
+
+func TestAvroDecoderRows(t *testing.T) {
+	schema := `{"type": "record", "name": "__root__", "fields": [
+		{"name": "id", "type": "long"},
+		{"name": "name", "type": ["null", "string"]}
+	]}`
+	dec, err := newAvroDecoder(schema)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	wantSchema := []*SDK.TableFieldSchema{
+		{Name: "id", Type: "INTEGER", Mode: "REQUIRED"},
+		{Name: "name", Type: "STRING", Mode: "NULLABLE"},
+	}
+	if !reflect.DeepEqual(dec.schema().Fields, wantSchema) {
+		t.Errorf("schema: got %#v, want %#v", dec.schema().Fields, wantSchema)
+	}
+
+	rows, err := dec.decode(newAvroTestRows(t, schema,
+		map[string]interface{}{"id": int64(1), "name": goavro.Union("string", "a")},
+		map[string]interface{}{"id": int64(2), "name": nil},
+	))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	want := []*SDK.TableRow{
+		{F: []*SDK.TableCell{{V: "1"}, {V: "a"}}},
+		{F: []*SDK.TableCell{{V: "2"}, {V: nil}}},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("got %#v, want %#v", rows, want)
+	}
+
+	// the response without rows.
+	rows, err = dec.decode(&storagepb.ReadRowsResponse{})
+	if err != nil || len(rows) != 0 {
+		t.Errorf("got %#v, %v, want no rows", rows, err)
+	}
+}
+
+func TestNewAvroDecoderError(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+	}{
+		{"invalid json", `{`},
+		{"not record", `"string"`},
+		{"multiple types in union", newAvroTestSchema(`["null", "string", "long"]`)},
+		{"unsupported type", newAvroTestSchema(`{"type": "map", "values": "string"}`)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := newAvroDecoder(tt.schema); err == nil {
+				t.Errorf("expected error")
+			}
+		})
+	}
+}
@@ -0,0 +1,474 @@
+// Package storageread reads BigQuery tables via Storage Read API.
+// The rows are decoded into the same format as Tabledata.List of REST API.
+package storageread
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+	"sync"
+	"time"
+
+	GCP "cloud.google.com/go/bigquery/storage/apiv1"
+	"cloud.google.com/go/bigquery/storage/apiv1/storagepb"
+	"cloud.google.com/go/civil"
+	SDK "google.golang.org/api/bigquery/v2"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	"github.com/evalphobia/google-api-go-wrapper/bigquery"
+	"github.com/evalphobia/google-api-go-wrapper/config"
+	"github.com/evalphobia/google-api-go-wrapper/log"
+)
+
+const (
+	serviceName = "BigQuery"
+
+	// data formats of Storage Read API.
+	FormatAvro  = "AVRO"
+	FormatArrow = "ARROW"
+
+	defaultMaxStreams   = 4
+	defaultReadRetries  = 3
+	defaultReadBaseWait = 500 * time.Millisecond
+	defaultReadMaxWait  = 10 * time.Second
+)
+
+var errIteratorClosed = errors.New("RowIterator is already closed")
+
+// Reader is BigQuery Storage Read API client for fast bulk reads.
+type Reader struct {
+	client    *GCP.BigQueryReadClient
+	logger    log.Logger
+	projectID string
+}
+
+// New returns initialized Reader.
+func New(ctx context.Context, conf config.Config, projectID string) (*Reader, error) {
+	if len(conf.Scopes) == 0 {
+		conf.Scopes = append(conf.Scopes, SDK.CloudPlatformScope)
+	}
+
+	var opts []option.ClientOption
+	if conf.GRPCEndpoint != "" {
+		opts = append(opts, option.WithEndpoint(conf.GRPCEndpoint))
+	}
+	if conf.NoAuthentication {
+		opts = append(opts,
+			option.WithoutAuthentication(),
+			option.WithGRPCDialOption(grpc.WithTransportCredentials(insecure.NewCredentials())),
+		)
+		return NewWithOptions(ctx, projectID, opts...)
+	}
+
+	ts, err := conf.NewTokenSource(ctx)
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts, option.WithTokenSource(ts))
+	return NewWithOptions(ctx, projectID, opts...)
+}
+
+// NewWithOptions returns initialized Reader with client options.
+// This can be used for the local fake server with option.WithEndpoint and option.WithoutAuthentication.
+func NewWithOptions(ctx context.Context, projectID string, opts ...option.ClientOption) (*Reader, error) {
+	cli, err := GCP.NewBigQueryReadClient(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Reader{
+		client:    cli,
+		logger:    log.DefaultLogger,
+		projectID: projectID,
+	}, nil
+}
+
+// SetLogger sets internal API logger.
+func (r *Reader) SetLogger(logger log.Logger) {
+	r.logger = logger
+}
+
+// Errorf logging error information.
+func (r *Reader) Errorf(format string, v ...interface{}) {
+	r.logger.Errorf(serviceName, format, v...)
+}
+
+// Close closes the connection of the API client.
+func (r *Reader) Close() error {
+	return r.client.Close()
+}
+
+// ReadOption is optional parameters used for the read session.
+type ReadOption struct {
+	// SelectedFields is the list of column names to read. empty means all of the columns.
+	// Nested column is joined by dot. (e.g. "address.city")
+	SelectedFields []string
+	// RowRestriction is SQL filter for rows. (e.g. "age > 20 AND country = 'JP'")
+	RowRestriction string
+
+	// DataFormat is FormatAvro or FormatArrow. (default: Avro)
+	DataFormat string
+	// MaxStreams is the maximum number of streams read in parallel. (default: 4)
+	MaxStreams int
+
+	// MaxRetries is the maximum number of retries to resume the stream on transient errors. (default: 3)
+	MaxRetries int
+}
+
+func (o ReadOption) getDataFormat() storagepb.DataFormat {
+	if o.DataFormat == FormatArrow {
+		return storagepb.DataFormat_ARROW
+	}
+	return storagepb.DataFormat_AVRO
+}
+
+func (o ReadOption) getMaxStreams() int {
+	if o.MaxStreams > 0 {
+		return o.MaxStreams
+	}
+	return defaultMaxStreams
+}
+
+func (o ReadOption) getMaxRetries() int {
+	if o.MaxRetries > 0 {
+		return o.MaxRetries
+	}
+	return defaultReadRetries
+}
+
+// Read opens the read session of the table and reads all of the streams in parallel.
+// The order of rows is not guaranteed.
+// Close of the iterator is required, because the streams are blocked until the rows are read or the iterator is closed.
+func (r *Reader) Read(ctx context.Context, datasetID, tableID string, opt ReadOption) (*RowIterator, error) {
+	req := &storagepb.CreateReadSessionRequest{
+		Parent: "projects/" + r.projectID,
+		ReadSession: &storagepb.ReadSession{
+			Table:      fmt.Sprintf("projects/%s/datasets/%s/tables/%s", r.projectID, datasetID, tableID),
+			DataFormat: opt.getDataFormat(),
+			ReadOptions: &storagepb.ReadSession_TableReadOptions{
+				SelectedFields: opt.SelectedFields,
+				RowRestriction: opt.RowRestriction,
+			},
+		},
+		MaxStreamCount: int32(opt.getMaxStreams()),
+	}
+
+	session, err := r.client.CreateReadSession(ctx, req)
+	if err != nil {
+		r.Errorf("error on `BigQueryRead.CreateReadSession` operation; error=[%s] projectID=[%s], datasetID=[%s] tableID=[%s]", err.Error(), r.projectID, datasetID, tableID)
+		return nil, err
+	}
+
+	dec, err := newDecoder(session)
+	if err != nil {
+		return nil, err
+	}
+	return r.newRowIterator(ctx, session, dec, opt), nil
+}
+
+// RowIterator iterates rows of the read session.
+type RowIterator struct {
+	schema *SDK.TableSchema
+
+	cancel  context.CancelFunc
+	batches chan []*SDK.TableRow
+	done    chan struct{}
+
+	items []*SDK.TableRow
+	index int
+
+	mu     sync.Mutex
+	err    error
+	closed bool
+}
+
+func (r *Reader) newRowIterator(ctx context.Context, session *storagepb.ReadSession, dec decoder, opt ReadOption) *RowIterator {
+	ctx, cancel := context.WithCancel(ctx)
+	it := &RowIterator{
+		schema:  dec.schema(),
+		cancel:  cancel,
+		batches: make(chan []*SDK.TableRow, len(session.Streams)),
+		done:    make(chan struct{}),
+	}
+
+	var wg sync.WaitGroup
+	for _, stream := range session.Streams {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			if err := r.readStream(ctx, name, dec, it.batches, opt.getMaxRetries()); err != nil {
+				it.setError(err)
+			}
+		}(stream.Name)
+	}
+	go func() {
+		wg.Wait()
+		close(it.batches)
+		close(it.done)
+	}()
+	return it
+}
+
+// readStream reads the stream and sends decoded rows into out.
+// The stream is resumed from the offset on transient errors.
+func (r *Reader) readStream(ctx context.Context, name string, dec decoder, out chan<- []*SDK.TableRow, maxRetries int) error {
+	bo := bigquery.Backoff{
+		BaseDelay: defaultReadBaseWait,
+		MaxDelay:  defaultReadMaxWait,
+	}
+
+	var offset int64
+	retries := 0
+	for {
+		err := r.readStreamFrom(ctx, name, offset, dec, out, &offset)
+		switch {
+		case err == nil:
+			return nil
+		case !isRetryableError(err) || retries >= maxRetries:
+			r.Errorf("error on `BigQueryRead.ReadRows` operation; error=[%s] projectID=[%s], stream=[%s] offset=[%d]", err.Error(), r.projectID, name, offset)
+			return err
+		}
+
+		if err := bigquery.SleepContext(ctx, bo.Next()); err != nil {
+			return err
+		}
+		retries++
+	}
+}
+
+func (r *Reader) readStreamFrom(ctx context.Context, name string, offset int64, dec decoder, out chan<- []*SDK.TableRow, nextOffset *int64) error {
+	stream, err := r.client.ReadRows(ctx, &storagepb.ReadRowsRequest{
+		ReadStream: name,
+		Offset:     offset,
+	})
+	if err != nil {
+		return err
+	}
+
+	for {
+		resp, err := stream.Recv()
+		switch {
+		case err == io.EOF:
+			return nil
+		case err != nil:
+			return err
+		}
+
+		rows, err := dec.decode(resp)
+		if err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			continue
+		}
+
+		select {
+		case out <- rows:
+			*nextOffset += int64(len(rows))
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Next moves to the next row and returns false when the iteration is finished.
+func (it *RowIterator) Next() bool {
+	it.index++
+	for it.index >= len(it.items) {
+		rows, ok := <-it.batches
+		if !ok {
+			// all of the streams are finished.
+			it.cancel()
+			return false
+		}
+		it.items = rows
+		it.index = 0
+	}
+	return true
+}
+
+// Row returns the current row.
+func (it *RowIterator) Row() *SDK.TableRow {
+	return it.items[it.index]
+}
+
+// Map decodes the current row into map by the schema.
+func (it *RowIterator) Map() (map[string]interface{}, error) {
+	return bigquery.DecodeRow(it.schema, it.Row())
+}
+
+// ScanStruct decodes the current row into dst by the schema.
+// dst must be a pointer of struct.
+func (it *RowIterator) ScanStruct(dst interface{}) error {
+	return bigquery.ScanRow(it.schema, it.Row(), dst)
+}
+
+// Schema returns the schema of the selected columns.
+func (it *RowIterator) Schema() *SDK.TableSchema {
+	return it.schema
+}
+
+// Err returns the first error occured during iteration.
+func (it *RowIterator) Err() error {
+	it.mu.Lock()
+	defer it.mu.Unlock()
+
+	if it.err == errIteratorClosed {
+		return nil
+	}
+	return it.err
+}
+
+// Close stops reading the streams.
+func (it *RowIterator) Close() error {
+	it.mu.Lock()
+	if it.closed {
+		it.mu.Unlock()
+		return nil
+	}
+	it.closed = true
+	if it.err == nil {
+		it.err = errIteratorClosed
+	}
+	it.mu.Unlock()
+
+	it.cancel()
+	for range it.batches {
+		// drain rows to stop the readers.
+	}
+	<-it.done
+	return nil
+}
+
+func (it *RowIterator) setError(err error) {
+	it.mu.Lock()
+	if it.err == nil {
+		it.err = err
+	}
+	it.mu.Unlock()
+
+	// stop other streams.
+	it.cancel()
+}
+
+func isRetryableError(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.ResourceExhausted, codes.Internal:
+		return true
+	}
+	return false
+}
+
+// decoder decodes the response of ReadRows into the rows of the same format as Tabledata.List.
+type decoder interface {
+	schema() *SDK.TableSchema
+	decode(resp *storagepb.ReadRowsResponse) ([]*SDK.TableRow, error)
+}
+
+func newDecoder(session *storagepb.ReadSession) (decoder, error) {
+	switch {
+	case session.GetAvroSchema() != nil:
+		return newAvroDecoder(session.GetAvroSchema().Schema)
+	case session.GetArrowSchema() != nil:
+		return newArrowDecoder(session.GetArrowSchema().SerializedSchema)
+	}
+	return nil, errors.New("the read session has no schema")
+}
+
+// civilDateTimeString returns DATETIME format string. (e.g. "2006-01-02 15:04:05.999999")
+func civilDateTimeString(dt civil.DateTime) string {
+	return dt.Date.String() + " " + civilTimeString(dt.Time)
+}
+
+// civilTimeString returns TIME format string with microsecond precision. (e.g. "15:04:05.999999")
+func civilTimeString(t civil.Time) string {
+	if t.Nanosecond == 0 {
+		return t.String()
+	}
+	return fmt.Sprintf("%02d:%02d:%02d.%06d", t.Hour, t.Minute, t.Second, t.Nanosecond/1000)
+}
+
+// newScalarCell converts the scalar value from Avro or Arrow into the cell value of Tabledata.List.
+func newScalarCell(typ string, scale int32, v interface{}) (interface{}, error) {
+	switch vv := v.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return vv, nil
+	case int64:
+		return strconv.FormatInt(vv, 10), nil
+	case int32:
+		return strconv.FormatInt(int64(vv), 10), nil
+	case float64:
+		return strconv.FormatFloat(vv, 'g', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(vv), nil
+	case []byte:
+		return base64.StdEncoding.EncodeToString(vv), nil
+	case *big.Rat:
+		return vv.FloatString(int(scale)), nil
+	case time.Duration:
+		return civilTimeString(civil.Time{
+			Hour:       int(vv / time.Hour),
+			Minute:     int(vv % time.Hour / time.Minute),
+			Second:     int(vv % time.Minute / time.Second),
+			Nanosecond: int(vv % time.Second),
+		}), nil
+	case time.Time:
+		switch typ {
+		case "DATE":
+			return civil.DateOf(vv).String(), nil
+		case "DATETIME":
+			return civilDateTimeString(civil.DateTimeOf(vv)), nil
+		case "TIME":
+			return civilTimeString(civil.TimeOf(vv)), nil
+		}
+		return timestampCell(vv), nil
+	}
+	return nil, fmt.Errorf("unsupported value from storage read api; type=[%s] value=[%v]", typ, v)
+}
+
+// timestampCell returns TIMESTAMP value of Tabledata.List, which is seconds from epoch. (e.g. "1500000000.123456")
+func timestampCell(t time.Time) string {
+	micros := t.Unix()*1e6 + int64(t.Nanosecond()/1e3)
+	sign := ""
+	if micros < 0 {
+		sign = "-"
+		micros = -micros
+	}
+	return fmt.Sprintf("%s%d.%06d", sign, micros/1e6, micros%1e6)
+}
+
+// newRecordCell returns RECORD value of Tabledata.List. ({"f": [{"v": value}, ...]})
+func newRecordCell(values []interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"f": newRepeatedCell(values),
+	}
+}
+
+// newRepeatedCell returns REPEATED value of Tabledata.List. ([{"v": value}, ...])
+func newRepeatedCell(values []interface{}) []interface{} {
+	list := make([]interface{}, len(values))
+	for i, v := range values {
+		list[i] = map[string]interface{}{
+			"v": v,
+		}
+	}
+	return list
+}
+
+func newTableRow(values []interface{}) *SDK.TableRow {
+	cells := make([]*SDK.TableCell, len(values))
+	for i, v := range values {
+		cells[i] = &SDK.TableCell{V: v}
+	}
+	return &SDK.TableRow{F: cells}
+}
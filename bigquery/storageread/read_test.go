@@ -0,0 +1,280 @@
+package storageread
+
+import (
+	"context"
+	"net"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+
+	"cloud.google.com/go/bigquery/storage/apiv1/storagepb"
+	"github.com/linkedin/goavro/v2"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const readTestSchema = `{"type": "record", "name": "__root__", "fields": [{"name": "name", "type": "string"}]}`
+
+// readTestFailure is the error returned from the stream once, after the rows of the offset are sent.
+type readTestFailure struct {
+	offset int64
+	code   codes.Code
+	times  int
+}
+
+// fakeReadServer is a fake BigQueryRead server which returns the rows of the streams in Avro format.
+type fakeReadServer struct {
+	storagepb.UnimplementedBigQueryReadServer
+
+	codec     *goavro.Codec
+	batchSize int
+	streams   map[string][]string
+
+	mu       sync.Mutex
+	session  *storagepb.CreateReadSessionRequest
+	offsets  map[string][]int64
+	failures map[string]*readTestFailure
+}
+
+func (s *fakeReadServer) CreateReadSession(ctx context.Context, req *storagepb.CreateReadSessionRequest) (*storagepb.ReadSession, error) {
+	s.mu.Lock()
+	s.session = req
+	s.mu.Unlock()
+
+	names := make([]string, 0, len(s.streams))
+	for name := range s.streams {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	streams := make([]*storagepb.ReadStream, len(names))
+	for i, name := range names {
+		streams[i] = &storagepb.ReadStream{Name: name}
+	}
+	return &storagepb.ReadSession{
+		Schema: &storagepb.ReadSession_AvroSchema{
+			AvroSchema: &storagepb.AvroSchema{Schema: readTestSchema},
+		},
+		Streams: streams,
+	}, nil
+}
+
+func (s *fakeReadServer) ReadRows(req *storagepb.ReadRowsRequest, srv storagepb.BigQueryRead_ReadRowsServer) error {
+	s.mu.Lock()
+	s.offsets[req.ReadStream] = append(s.offsets[req.ReadStream], req.Offset)
+	failure := s.failures[req.ReadStream]
+	if failure != nil {
+		if failure.times <= 0 {
+			failure = nil
+		} else {
+			failure.times--
+		}
+	}
+	s.mu.Unlock()
+
+	rows := s.streams[req.ReadStream]
+	for i := req.Offset; i < int64(len(rows)); i += int64(s.batchSize) {
+		if failure != nil && i >= failure.offset {
+			return status.Error(failure.code, "fake error")
+		}
+
+		end := i + int64(s.batchSize)
+		if end > int64(len(rows)) {
+			end = int64(len(rows))
+		}
+		var buf []byte
+		for _, name := range rows[i:end] {
+			var err error
+			buf, err = s.codec.BinaryFromNative(buf, map[string]interface{}{"name": name})
+			if err != nil {
+				return err
+			}
+		}
+		err := srv.Send(&storagepb.ReadRowsResponse{
+			Rows: &storagepb.ReadRowsResponse_AvroRows{
+				AvroRows: &storagepb.AvroRows{SerializedBinaryRows: buf},
+			},
+			RowCount: end - i,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newTestReader returns Reader connected to the fake server.
+func newTestReader(t *testing.T, streams map[string][]string, failures map[string]*readTestFailure) (*Reader, *fakeReadServer) {
+	codec, err := goavro.NewCodec(readTestSchema)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	fake := &fakeReadServer{
+		codec:     codec,
+		batchSize: 2,
+		streams:   streams,
+		offsets:   make(map[string][]int64),
+		failures:  failures,
+	}
+
+	lis := bufconn.Listen(1024 * 1024)
+	srv := grpc.NewServer()
+	storagepb.RegisterBigQueryReadServer(srv, fake)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	r, err := NewWithOptions(context.Background(), "project", option.WithGRPCConn(conn))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	t.Cleanup(func() { r.Close() })
+	return r, fake
+}
+
+// readAll reads the names of all of the rows.
+func readAll(t *testing.T, it *RowIterator) []string {
+	var names []string
+	for it.Next() {
+		row, err := it.Map()
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err.Error())
+		}
+		names = append(names, row["name"].(string))
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestReaderRead(t *testing.T) {
+	streams := map[string][]string{
+		"stream-0": {"a0", "a1", "a2", "a3", "a4"},
+		"stream-1": {"b0", "b1", "b2"},
+		"stream-2": {},
+	}
+	failures := map[string]*readTestFailure{
+		"stream-0": {offset: 2, code: codes.Unavailable, times: 1},
+	}
+	r, fake := newTestReader(t, streams, failures)
+
+	it, err := r.Read(context.Background(), "ds", "tbl", ReadOption{
+		SelectedFields: []string{"name"},
+		MaxStreams:     3,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	defer it.Close()
+
+	got := readAll(t, it)
+	if err := it.Err(); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	want := []string{"a0", "a1", "a2", "a3", "a4", "b0", "b1", "b2"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	if fake.session.ReadSession.Table != "projects/project/datasets/ds/tables/tbl" {
+		t.Errorf("table: got %s", fake.session.ReadSession.Table)
+	}
+	if fake.session.MaxStreamCount != 3 {
+		t.Errorf("MaxStreamCount: got %d, want 3", fake.session.MaxStreamCount)
+	}
+	if got := fake.session.ReadSession.ReadOptions.SelectedFields; !reflect.DeepEqual(got, []string{"name"}) {
+		t.Errorf("SelectedFields: got %v", got)
+	}
+
+	// stream-0 is resumed from the offset after the error.
+	wantOffsets := map[string][]int64{
+		"stream-0": {0, 2},
+		"stream-1": {0},
+		"stream-2": {0},
+	}
+	if !reflect.DeepEqual(fake.offsets, wantOffsets) {
+		t.Errorf("offsets: got %v, want %v", fake.offsets, wantOffsets)
+	}
+}
+
+func TestReaderReadError(t *testing.T) {
+	tests := []struct {
+		name       string
+		failure    *readTestFailure
+		maxRetries int
+		wantCode   codes.Code
+		wantCalls  int
+	}{
+		{"not retryable", &readTestFailure{offset: 2, code: codes.InvalidArgument, times: 1}, 3, codes.InvalidArgument, 1},
+		{"max retries", &readTestFailure{offset: 0, code: codes.Unavailable, times: 10}, 1, codes.Unavailable, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			streams := map[string][]string{
+				"stream-0": {"a0", "a1", "a2"},
+			}
+			r, fake := newTestReader(t, streams, map[string]*readTestFailure{"stream-0": tt.failure})
+
+			it, err := r.Read(context.Background(), "ds", "tbl", ReadOption{MaxRetries: tt.maxRetries})
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+			defer it.Close()
+
+			readAll(t, it)
+			if code := status.Code(it.Err()); code != tt.wantCode {
+				t.Errorf("got %v, want %v", it.Err(), tt.wantCode)
+			}
+
+			fake.mu.Lock()
+			defer fake.mu.Unlock()
+			if got := len(fake.offsets["stream-0"]); got != tt.wantCalls {
+				t.Errorf("calls: got %d, want %d", got, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestRowIteratorClose(t *testing.T) {
+	rows := make([]string, 100)
+	for i := range rows {
+		rows[i] = "row"
+	}
+	r, _ := newTestReader(t, map[string][]string{"stream-0": rows, "stream-1": rows}, nil)
+
+	it, err := r.Read(context.Background(), "ds", "tbl", ReadOption{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if !it.Next() {
+		t.Fatalf("expected a row, got %v", it.Err())
+	}
+
+	// Close stops the streams blocked on sending rows.
+	if err := it.Close(); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if err := it.Err(); err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+	}
+	if err := it.Close(); err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+	}
+}
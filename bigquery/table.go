@@ -1,10 +1,9 @@
 package bigquery
 
 import (
-	"net/http"
+	"context"
 
 	SDK "google.golang.org/api/bigquery/v2"
-	"google.golang.org/api/googleapi"
 
 	"github.com/evalphobia/google-api-go-wrapper/config"
 )
@@ -31,6 +30,16 @@ func NewTableAPI(conf config.Config, projectID, datasetID, tableID string) (*Tab
 
 // Create creates the table with schema defined from given struct.
 func (t *TableAPI) Create(schemaStruct interface{}) error {
+	return t.CreateWithContext(context.Background(), schemaStruct)
+}
+
+// CreateWithContext creates the table with schema defined from given struct with the given context.
+func (t *TableAPI) CreateWithContext(ctx context.Context, schemaStruct interface{}) error {
+	return t.CreateWithOption(ctx, schemaStruct, TableOption{})
+}
+
+// CreateWithOption creates the table with schema defined from given struct and table settings.
+func (t *TableAPI) CreateWithOption(ctx context.Context, schemaStruct interface{}, opt TableOption) error {
 	schema, err := convertToSchema(schemaStruct)
 	if err != nil {
 		return err
@@ -38,71 +47,184 @@ func (t *TableAPI) Create(schemaStruct interface{}) error {
 
 	cli := t.dataset.client
 	tbl := &SDK.Table{
-		Schema: schema,
-		TableReference: &SDK.TableReference{
-			ProjectId: cli.projectID,
-			DatasetId: t.dataset.datasetID,
-			TableId:   t.tableID,
-		},
+		Schema:         schema,
+		TableReference: t.tableReference(),
 	}
+	opt.applyTo(tbl)
 
-	_, err = cli.CreateTable(t.tableID, tbl)
+	_, err = cli.CreateTableWithContext(ctx, t.dataset.datasetID, tbl)
 	return err
 }
 
+// SyncSchema updates the table schema by given struct.
+// Only additive changes (new NULLABLE column, REQUIRED to NULLABLE) are applied,
+// and incompatible changes are returned as SchemaDiff.Incompatible without applying.
+func (t *TableAPI) SyncSchema(schemaStruct interface{}) (*SchemaDiff, error) {
+	return t.SyncSchemaWithContext(context.Background(), schemaStruct)
+}
+
+// SyncSchemaWithContext updates the table schema by given struct with the given context.
+func (t *TableAPI) SyncSchemaWithContext(ctx context.Context, schemaStruct interface{}) (*SchemaDiff, error) {
+	schema, err := convertToSchema(schemaStruct)
+	if err != nil {
+		return nil, err
+	}
+
+	tbl, err := t.GetWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := DiffSchema(tbl.Schema, schema)
+	if !diff.HasAdditive() {
+		return diff, nil
+	}
+
+	cli := t.dataset.client
+	_, err = cli.PatchTableWithContext(ctx, t.dataset.datasetID, t.tableID, &SDK.Table{
+		Schema: diff.Schema,
+	})
+	return diff, err
+}
+
 // Get gets the table.
 func (t *TableAPI) Get() (*Table, error) {
+	return t.GetWithContext(context.Background())
+}
+
+// GetWithContext gets the table with the given context.
+func (t *TableAPI) GetWithContext(ctx context.Context) (*Table, error) {
 	cli := t.dataset.client
-	return cli.GetTable(t.dataset.datasetID, t.tableID)
+	return cli.GetTableWithContext(ctx, t.dataset.datasetID, t.tableID)
 }
 
 // IsExist checks if the table is exists in BQ.
 func (t *TableAPI) IsExist() (bool, error) {
-	_, err := t.Get()
-	if err == nil {
-		// table exists.
-		return true, nil
-	}
+	return t.IsExistWithContext(context.Background())
+}
 
-	apiErr, ok := err.(*googleapi.Error)
+// IsExistWithContext checks if the table is exists in BQ with the given context.
+func (t *TableAPI) IsExistWithContext(ctx context.Context) (bool, error) {
+	_, err := t.GetWithContext(ctx)
 	switch {
-	case !ok:
-		// unknown error
-		return false, err
-	case apiErr.Code == http.StatusNotFound:
+	case err == nil:
+		// table exists.
+		return true, nil
+	case isNotFound(err):
 		// table does not exist.
 		return false, nil
 	default:
-		// other API error
+		// other error
 		return false, err
 	}
 }
 
 // Drop deletes the table.
 func (t *TableAPI) Drop() error {
+	return t.DropWithContext(context.Background())
+}
+
+// DropWithContext deletes the table with the given context.
+func (t *TableAPI) DropWithContext(ctx context.Context) error {
 	cli := t.dataset.client
-	return cli.DropTable(t.dataset.datasetID, t.tableID)
+	return cli.DropTableWithContext(ctx, t.dataset.datasetID, t.tableID)
 }
 
 // InsertAll appends all of map data by using InsertAll api
 func (t *TableAPI) InsertAll(data interface{}) error {
+	return t.InsertAllWithContext(context.Background(), data)
+}
+
+// InsertAllWithContext appends all of map data by using InsertAll api with the given context.
+func (t *TableAPI) InsertAllWithContext(ctx context.Context, data interface{}) error {
+	return t.InsertAllWithOption(ctx, data, InsertAllOption{})
+}
+
+// InsertAllWithOption appends all of map data by using InsertAll api with the given option.
+// When some of rows are failed, *InsertAllError is returned.
+func (t *TableAPI) InsertAllWithOption(ctx context.Context, data interface{}, opt InsertAllOption) error {
 	rows, err := buildTableDataInsertAllRequest(data)
 	if err != nil {
 		return err
 	}
 
+	opt.applyTo(rows)
+	return t.insertAll(ctx, rows, opt)
+}
+
+// insertAll sends rows and retries only the rows failed with retryable reason.
+func (t *TableAPI) insertAll(ctx context.Context, req *SDK.TableDataInsertAllRequest, opt InsertAllOption) error {
 	cli := t.dataset.client
-	resp, err := cli.InsertAll(t.dataset.datasetID, t.tableID, rows)
-	switch {
-	case err != nil:
-		return err
-	case len(resp.InsertErrors) != 0:
-		return errOperationInsertAll
+	bo := Backoff{
+		BaseDelay: opt.RetryBaseDelay,
+		MaxDelay:  opt.RetryMaxDelay,
 	}
 
+	// indexes has the original index of the pending rows.
+	pending := req.Rows
+	indexes := make([]int64, len(pending))
+	for i := range indexes {
+		indexes[i] = int64(i)
+	}
+
+	var failed []RowError
+	for attempt := 0; ; attempt++ {
+		r := *req
+		r.Rows = pending
+		resp, err := cli.InsertAllWithContext(ctx, t.dataset.datasetID, t.tableID, &r)
+		if err != nil {
+			return err
+		}
+
+		var retryRows []*SDK.TableDataInsertAllRequestRows
+		var retryIndexes []int64
+		for _, insertErr := range resp.InsertErrors {
+			if insertErr.Index < 0 || insertErr.Index >= int64(len(pending)) {
+				// the row cannot be identified, so it's reported without retry.
+				failed = append(failed, newRowErrors(unknownRowIndex, insertErr.Errors)...)
+				continue
+			}
+
+			rowErrs := newRowErrors(indexes[insertErr.Index], insertErr.Errors)
+			if attempt < opt.MaxRetries && isRetryableRowErrors(rowErrs) {
+				retryRows = append(retryRows, pending[insertErr.Index])
+				retryIndexes = append(retryIndexes, indexes[insertErr.Index])
+				continue
+			}
+			failed = append(failed, rowErrs...)
+		}
+
+		if len(retryRows) == 0 {
+			break
+		}
+		if err := SleepContext(ctx, bo.Next()); err != nil {
+			return err
+		}
+		pending = retryRows
+		indexes = retryIndexes
+	}
+
+	if len(failed) != 0 {
+		return &InsertAllError{
+			RowErrors: failed,
+		}
+	}
 	return nil
 }
 
+// RowsIterator returns iterator for all of the rows in the table.
+func (t *TableAPI) RowsIterator(ctx context.Context, opt PageOption) *RowIterator {
+	return t.dataset.client.TableDataIterator(ctx, t.dataset.datasetID, t.tableID, opt)
+}
+
+func (t *TableAPI) tableReference() *SDK.TableReference {
+	return &SDK.TableReference{
+		ProjectId: t.dataset.client.projectID,
+		DatasetId: t.dataset.datasetID,
+		TableId:   t.tableID,
+	}
+}
+
 func buildTableDataInsertAllRequest(data interface{}) (*SDK.TableDataInsertAllRequest, error) {
 	switch v := data.(type) {
 	case []map[string]interface{}:
@@ -172,5 +294,8 @@ func buildRowsFromStruct(data interface{}) (*SDK.TableDataInsertAllRequestRows,
 	if err != nil {
 		return nil, err
 	}
-	return buildRowFromMap(row), nil
+
+	r := buildRowFromMap(row)
+	r.InsertId = getInsertID(data)
+	return r, nil
 }
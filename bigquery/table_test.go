@@ -0,0 +1,305 @@
+package bigquery
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	SDK "google.golang.org/api/bigquery/v2"
+
+	"github.com/evalphobia/google-api-go-wrapper/config"
+)
+
+// insertAllTestServer returns the scripted responses of InsertAll and records the names of the requested rows.
+type insertAllTestServer struct {
+	mu        sync.Mutex
+	responses []*SDK.TableDataInsertAllResponse
+	requests  [][]string
+}
+
+func (s *insertAllTestServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var req SDK.TableDataInsertAllRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	names := make([]string, len(req.Rows))
+	for i, row := range req.Rows {
+		names[i], _ = row.Json["name"].(string)
+	}
+	s.requests = append(s.requests, names)
+
+	resp := &SDK.TableDataInsertAllResponse{}
+	if len(s.responses) != 0 {
+		resp = s.responses[0]
+		s.responses = s.responses[1:]
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func newInsertErrors(reason string, indexes ...int64) *SDK.TableDataInsertAllResponse {
+	resp := &SDK.TableDataInsertAllResponse{}
+	for _, i := range indexes {
+		resp.InsertErrors = append(resp.InsertErrors, &SDK.TableDataInsertAllResponseInsertErrors{
+			Index:  i,
+			Errors: []*SDK.ErrorProto{{Reason: reason, Message: reason}},
+		})
+	}
+	return resp
+}
+
+func TestTableAPIInsertAllWithOption(t *testing.T) {
+	rows := []map[string]interface{}{
+		{"name": "a"},
+		{"name": "b"},
+		{"name": "c"},
+	}
+
+	tests := []struct {
+		name         string
+		maxRetries   int
+		responses    []*SDK.TableDataInsertAllResponse
+		wantRequests [][]string
+		wantErrors   []RowError
+	}{
+		{
+			name:         "success",
+			wantRequests: [][]string{{"a", "b", "c"}},
+		},
+		{
+			name:         "invalid row is not retried",
+			maxRetries:   3,
+			responses:    []*SDK.TableDataInsertAllResponse{newInsertErrors("invalid", 1)},
+			wantRequests: [][]string{{"a", "b", "c"}},
+			wantErrors:   []RowError{{Index: 1, Reason: "invalid", Message: "invalid"}},
+		},
+		{
+			name:       "retry only failed rows",
+			maxRetries: 3,
+			responses: []*SDK.TableDataInsertAllResponse{
+				newInsertErrors("backendError", 0, 2),
+				newInsertErrors("stopped", 1),
+			},
+			wantRequests: [][]string{{"a", "b", "c"}, {"a", "c"}, {"c"}},
+		},
+		{
+			name:       "retry limit",
+			maxRetries: 1,
+			responses: []*SDK.TableDataInsertAllResponse{
+				newInsertErrors("backendError", 0, 2),
+				newInsertErrors("stopped", 1),
+			},
+			wantRequests: [][]string{{"a", "b", "c"}, {"a", "c"}},
+			wantErrors:   []RowError{{Index: 2, Reason: "stopped", Message: "stopped"}},
+		},
+		{
+			name:         "no retry by default",
+			responses:    []*SDK.TableDataInsertAllResponse{newInsertErrors("backendError", 2)},
+			wantRequests: [][]string{{"a", "b", "c"}},
+			wantErrors:   []RowError{{Index: 2, Reason: "backendError", Message: "backendError"}},
+		},
+		{
+			name:         "out of range index",
+			maxRetries:   3,
+			responses:    []*SDK.TableDataInsertAllResponse{newInsertErrors("backendError", 3, -1)},
+			wantRequests: [][]string{{"a", "b", "c"}},
+			wantErrors: []RowError{
+				{Index: -1, Reason: "backendError", Message: "backendError"},
+				{Index: -1, Reason: "backendError", Message: "backendError"},
+			},
+		},
+		{
+			name: "row without error detail",
+			responses: []*SDK.TableDataInsertAllResponse{{
+				InsertErrors: []*SDK.TableDataInsertAllResponseInsertErrors{{Index: 0}},
+			}},
+			wantRequests: [][]string{{"a", "b", "c"}},
+			wantErrors:   []RowError{{Index: 0}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := &insertAllTestServer{responses: tt.responses}
+			ts := httptest.NewServer(srv)
+			defer ts.Close()
+
+			tbl, err := NewTableAPI(config.Config{
+				Endpoint:         ts.URL + "/bigquery/v2/",
+				NoAuthentication: true,
+			}, "project", "dataset", "table")
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+
+			err = tbl.InsertAllWithOption(context.Background(), rows, InsertAllOption{
+				MaxRetries:     tt.maxRetries,
+				RetryBaseDelay: time.Millisecond,
+				RetryMaxDelay:  time.Millisecond,
+			})
+			if !reflect.DeepEqual(srv.requests, tt.wantRequests) {
+				t.Errorf("requests: got %v, want %v", srv.requests, tt.wantRequests)
+			}
+
+			if len(tt.wantErrors) == 0 {
+				if err != nil {
+					t.Errorf("unexpected error: %s", err.Error())
+				}
+				return
+			}
+			insertErr, ok := err.(*InsertAllError)
+			if !ok {
+				t.Fatalf("expected *InsertAllError, got %v", err)
+			}
+			if !reflect.DeepEqual(insertErr.RowErrors, tt.wantErrors) {
+				t.Errorf("RowErrors: got %#v, want %#v", insertErr.RowErrors, tt.wantErrors)
+			}
+		})
+	}
+}
+
+func TestInsertAllErrorFailedIndexes(t *testing.T) {
+	err := &InsertAllError{
+		RowErrors: []RowError{
+			{Index: 2, Reason: "invalid"},
+			{Index: 2, Reason: "stopped"},
+			{Index: 0, Reason: "invalid"},
+			{Index: -1, Reason: "invalid"},
+		},
+	}
+	if got, want := err.FailedIndexes(), []int64{2, 0, -1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+// tableTestServer is a fake tables API which records the requests and the table in the request body.
+type tableTestServer struct {
+	mu       sync.Mutex
+	existing *SDK.Table // returned by Tables.Get, or Not Found when it's nil.
+
+	requests []string // "METHOD path"
+	table    *SDK.Table
+	body     string
+}
+
+func (s *tableTestServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := strings.TrimPrefix(r.URL.Path, "/bigquery/v2/projects/project")
+	s.requests = append(s.requests, r.Method+" "+path)
+
+	switch r.Method {
+	case http.MethodGet:
+		if s.existing == nil {
+			http.Error(w, `{"error":{"code":404,"message":"not found"}}`, http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(s.existing)
+	case http.MethodPost, http.MethodPatch:
+		b, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, `{"error":{"code":400,"message":"invalid body"}}`, http.StatusBadRequest)
+			return
+		}
+		tbl := &SDK.Table{}
+		if err := json.Unmarshal(b, tbl); err != nil {
+			http.Error(w, `{"error":{"code":400,"message":"invalid body"}}`, http.StatusBadRequest)
+			return
+		}
+		s.body = string(b)
+		s.table = tbl
+		json.NewEncoder(w).Encode(tbl)
+	default:
+		http.Error(w, `{"error":{"code":405,"message":"method not allowed"}}`, http.StatusMethodNotAllowed)
+	}
+}
+
+func TestTableAPICreateWithOption(t *testing.T) {
+	type schema struct {
+		ID        int64     `bigquery:"id"`
+		Category  string    `bigquery:"category"`
+		CreatedAt time.Time `bigquery:"created_at"`
+	}
+	ref := &SDK.TableReference{ProjectId: "project", DatasetId: "ds", TableId: "tbl"}
+
+	tests := []struct {
+		name string
+		opt  TableOption
+		want *SDK.Table
+	}{
+		{"no option", TableOption{}, &SDK.Table{TableReference: ref}},
+		{"time partitioning", TableOption{
+			Description:    "events",
+			Labels:         map[string]string{"env": "test"},
+			ExpirationTime: time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+			TimePartitioning: &TimePartitioning{
+				Field:      "created_at",
+				Expiration: 90 * 24 * time.Hour,
+			},
+			RequirePartitionFilter: true,
+			Clustering:             []string{"category", "id"},
+		}, &SDK.Table{
+			TableReference: ref,
+			Description:    "events",
+			Labels:         map[string]string{"env": "test"},
+			ExpirationTime: 1577934245000,
+			TimePartitioning: &SDK.TimePartitioning{
+				Type:         "DAY",
+				Field:        "created_at",
+				ExpirationMs: 7776000000,
+			},
+			RequirePartitionFilter: true,
+			Clustering:             &SDK.Clustering{Fields: []string{"category", "id"}},
+		}},
+		{"ingestion time partitioning", TableOption{
+			TimePartitioning: &TimePartitioning{Type: PartitionHour},
+		}, &SDK.Table{
+			TableReference:   ref,
+			TimePartitioning: &SDK.TimePartitioning{Type: "HOUR"},
+		}},
+		{"range partitioning", TableOption{
+			RangePartitioning: &RangePartitioning{Field: "id", Start: 0, End: 1000, Interval: 10},
+		}, &SDK.Table{
+			TableReference: ref,
+			RangePartitioning: &SDK.RangePartitioning{
+				Field: "id",
+				Range: &SDK.RangePartitioningRange{Start: 0, End: 1000, Interval: 10},
+			},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := &tableTestServer{}
+			tbl := newTestBigQuery(t, srv).DatasetAPI("ds").TableAPI("tbl")
+
+			if err := tbl.CreateWithOption(context.Background(), schema{}, tt.opt); err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+			if want := []string{"POST /datasets/ds/tables"}; !reflect.DeepEqual(srv.requests, want) {
+				t.Errorf("got %v, want %v", srv.requests, want)
+			}
+
+			got := srv.table
+			if got.Schema == nil || len(got.Schema.Fields) != 3 {
+				t.Fatalf("schema: got %#v, want 3 fields", got.Schema)
+			}
+			got.Schema = nil
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,304 @@
+package bigquery
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	SDK "google.golang.org/api/bigquery/v2"
+)
+
+const (
+	// see: https://cloud.google.com/bigquery/quotas#streaming_inserts
+	defaultWriterMaxRows        = 500
+	defaultWriterMaxBytes       = 9 * 1024 * 1024 // keep under 10MB of the request size limit.
+	defaultWriterMaxLatency     = 1 * time.Second
+	defaultWriterMaxConcurrency = 4
+
+	// rowSizeOverhead is the approximate size of json syntax per row. ({"insertId":"","json":{}})
+	rowSizeOverhead = 32
+)
+
+var errWriterClosed = errors.New("TableWriter is already closed")
+
+// TableWriterConfig is config for TableWriter.
+type TableWriterConfig struct {
+	// MaxRows is the maximum number of rows in a request. (default: 500)
+	MaxRows int
+	// MaxBytes is the maximum size of rows in a request. (default: 9MB)
+	MaxBytes int
+	// MaxLatency is the maximum duration to buffer rows before sending. (default: 1s)
+	MaxLatency time.Duration
+	// MaxConcurrency is the maximum number of concurrent requests. (default: 4)
+	MaxConcurrency int
+
+	InsertAllOption InsertAllOption
+
+	// OnError is called when background InsertAll operation is failed.
+	// It receives all of the errors, while Flush and Close return only the first one.
+	OnError func(err error)
+}
+
+func (c TableWriterConfig) getMaxRows() int {
+	if c.MaxRows > 0 {
+		return c.MaxRows
+	}
+	return defaultWriterMaxRows
+}
+
+func (c TableWriterConfig) getMaxBytes() int {
+	if c.MaxBytes > 0 {
+		return c.MaxBytes
+	}
+	return defaultWriterMaxBytes
+}
+
+func (c TableWriterConfig) getMaxLatency() time.Duration {
+	if c.MaxLatency > 0 {
+		return c.MaxLatency
+	}
+	return defaultWriterMaxLatency
+}
+
+func (c TableWriterConfig) getMaxConcurrency() int {
+	if c.MaxConcurrency > 0 {
+		return c.MaxConcurrency
+	}
+	return defaultWriterMaxConcurrency
+}
+
+// TableWriter buffers rows and sends them by InsertAll operation in background.
+type TableWriter struct {
+	ctx   context.Context
+	table *TableAPI
+	conf  TableWriterConfig
+
+	mu     sync.Mutex
+	rows   []*SDK.TableDataInsertAllRequestRows
+	size   int
+	gen    int // generation of the buffer, incremented on every flush.
+	closed bool
+
+	inflight int // number of batches taken from the buffer and not finished.
+	done     *sync.Cond
+	sem      chan struct{}
+
+	errMu    sync.Mutex
+	err      error // the first error after the last Flush.
+	errCount int
+}
+
+// TableWriterError is returned from Flush and Close when background InsertAll operations are failed.
+type TableWriterError struct {
+	// Err is the first error after the last Flush. (e.g. *InsertAllError)
+	Err error
+	// Count is the number of failed InsertAll operations after the last Flush.
+	Count int
+}
+
+func (e *TableWriterError) Error() string {
+	return fmt.Sprintf("error occured on bigquery.TableWriter; failed=[%d] error=[%s]", e.Count, e.Err.Error())
+}
+
+// Unwrap returns the first error.
+func (e *TableWriterError) Unwrap() error {
+	return e.Err
+}
+
+// NewTableWriter returns initialized TableWriter.
+// ctx is used for background InsertAll operations.
+func (t *TableAPI) NewTableWriter(ctx context.Context, conf TableWriterConfig) *TableWriter {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	w := &TableWriter{
+		ctx:   ctx,
+		table: t,
+		conf:  conf,
+		sem:   make(chan struct{}, conf.getMaxConcurrency()),
+	}
+	w.done = sync.NewCond(&w.mu)
+	return w
+}
+
+// Add adds the rows into buffer.
+// data is struct, map[string]interface{} or the slice of them, same as InsertAll.
+// This may block when the number of running requests reaches MaxConcurrency.
+func (w *TableWriter) Add(data interface{}) error {
+	req, err := buildTableDataInsertAllRequest(data)
+	if err != nil {
+		return err
+	}
+
+	for _, row := range req.Rows {
+		if row == nil {
+			continue
+		}
+		if err := w.addRow(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *TableWriter) addRow(row *SDK.TableDataInsertAllRequestRows) error {
+	size, err := estimateRowSize(row)
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return errWriterClosed
+	}
+
+	var batches [][]*SDK.TableDataInsertAllRequestRows
+	if len(w.rows) != 0 && w.size+size > w.conf.getMaxBytes() {
+		batches = append(batches, w.takeLocked())
+	}
+
+	if len(w.rows) == 0 {
+		w.startTimerLocked()
+	}
+	w.rows = append(w.rows, row)
+	w.size += size
+	if len(w.rows) >= w.conf.getMaxRows() || w.size >= w.conf.getMaxBytes() {
+		batches = append(batches, w.takeLocked())
+	}
+	w.mu.Unlock()
+
+	for _, b := range batches {
+		w.send(b)
+	}
+	return nil
+}
+
+// Flush sends all of the buffered rows and waits for all of the running requests.
+// When some of InsertAll operations are failed after the last Flush, it returns *TableWriterError
+// which has the first error and the number of the failed operations. Use OnError to get all of the errors.
+func (w *TableWriter) Flush() error {
+	w.mu.Lock()
+	batch := w.takeLocked()
+	w.mu.Unlock()
+
+	w.send(batch)
+
+	w.mu.Lock()
+	for w.inflight > 0 {
+		w.done.Wait()
+	}
+	w.mu.Unlock()
+	return w.takeError()
+}
+
+// Close flushes all of the buffered rows and stops accepting new rows.
+// It returns the same error as Flush, or an error when it's already closed.
+func (w *TableWriter) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return errWriterClosed
+	}
+	w.closed = true
+	w.mu.Unlock()
+
+	return w.Flush()
+}
+
+// startTimerLocked flushes the buffer after MaxLatency, unless the buffer is already flushed.
+func (w *TableWriter) startTimerLocked() {
+	gen := w.gen
+	time.AfterFunc(w.conf.getMaxLatency(), func() {
+		w.mu.Lock()
+		if w.gen != gen {
+			w.mu.Unlock()
+			return
+		}
+		batch := w.takeLocked()
+		w.mu.Unlock()
+
+		w.send(batch)
+	})
+}
+
+// takeLocked returns buffered rows and resets the buffer.
+func (w *TableWriter) takeLocked() []*SDK.TableDataInsertAllRequestRows {
+	rows := w.rows
+	w.rows = nil
+	w.size = 0
+	w.gen++
+	if len(rows) != 0 {
+		w.inflight++
+	}
+	return rows
+}
+
+// send executes InsertAll operation in background.
+func (w *TableWriter) send(rows []*SDK.TableDataInsertAllRequestRows) {
+	if len(rows) == 0 {
+		return
+	}
+
+	w.sem <- struct{}{}
+	go func() {
+		defer func() {
+			<-w.sem
+			w.mu.Lock()
+			w.inflight--
+			if w.inflight == 0 {
+				w.done.Broadcast()
+			}
+			w.mu.Unlock()
+		}()
+
+		req := &SDK.TableDataInsertAllRequest{
+			Rows: rows,
+		}
+		w.conf.InsertAllOption.applyTo(req)
+		if err := w.table.insertAll(w.ctx, req, w.conf.InsertAllOption); err != nil {
+			w.handleError(err)
+		}
+	}()
+}
+
+func (w *TableWriter) handleError(err error) {
+	w.errMu.Lock()
+	if w.err == nil {
+		w.err = err
+	}
+	w.errCount++
+	w.errMu.Unlock()
+
+	if w.conf.OnError != nil {
+		w.conf.OnError(err)
+	}
+}
+
+func (w *TableWriter) takeError() error {
+	w.errMu.Lock()
+	defer w.errMu.Unlock()
+
+	if w.err == nil {
+		return nil
+	}
+	err := &TableWriterError{
+		Err:   w.err,
+		Count: w.errCount,
+	}
+	w.err = nil
+	w.errCount = 0
+	return err
+}
+
+// estimateRowSize returns the approximate size of the row in the request body.
+func estimateRowSize(row *SDK.TableDataInsertAllRequestRows) (int, error) {
+	byt, err := json.Marshal(row.Json)
+	if err != nil {
+		return 0, err
+	}
+	return len(byt) + len(row.InsertId) + rowSizeOverhead, nil
+}
@@ -0,0 +1,94 @@
+package bigquery
+
+import (
+	"context"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	SDK "google.golang.org/api/bigquery/v2"
+
+	"github.com/evalphobia/google-api-go-wrapper/config"
+)
+
+func TestTableWriterFlushError(t *testing.T) {
+	tests := []struct {
+		name      string
+		responses []*SDK.TableDataInsertAllResponse
+		wantCount int
+	}{
+		{"success", nil, 0},
+		{"one failure", []*SDK.TableDataInsertAllResponse{
+			newInsertErrors("invalid", 0),
+		}, 1},
+		{"all failures", []*SDK.TableDataInsertAllResponse{
+			newInsertErrors("invalid", 0),
+			newInsertErrors("invalid", 0),
+			newInsertErrors("invalid", 0),
+		}, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := &insertAllTestServer{responses: tt.responses}
+			ts := httptest.NewServer(srv)
+			defer ts.Close()
+
+			tbl, err := NewTableAPI(config.Config{
+				Endpoint:         ts.URL + "/bigquery/v2/",
+				NoAuthentication: true,
+			}, "project", "dataset", "table")
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+
+			var mu sync.Mutex
+			var onErrors []error
+			w := tbl.NewTableWriter(context.Background(), TableWriterConfig{
+				MaxRows: 1,
+				OnError: func(err error) {
+					mu.Lock()
+					onErrors = append(onErrors, err)
+					mu.Unlock()
+				},
+			})
+			for _, name := range []string{"a", "b", "c"} {
+				if err := w.Add(map[string]interface{}{"name": name}); err != nil {
+					t.Fatalf("unexpected error: %s", err.Error())
+				}
+			}
+
+			err = w.Flush()
+			if len(onErrors) != tt.wantCount {
+				t.Errorf("OnError: got %d errors, want %d", len(onErrors), tt.wantCount)
+			}
+			if tt.wantCount == 0 {
+				if err != nil {
+					t.Errorf("unexpected error: %s", err.Error())
+				}
+			} else {
+				writerErr, ok := err.(*TableWriterError)
+				if !ok {
+					t.Fatalf("expected *TableWriterError, got %v", err)
+				}
+				if writerErr.Count != tt.wantCount {
+					t.Errorf("Count: got %d, want %d", writerErr.Count, tt.wantCount)
+				}
+				if _, ok := writerErr.Err.(*InsertAllError); !ok {
+					t.Errorf("expected *InsertAllError, got %v", writerErr.Err)
+				}
+			}
+
+			// errors are cleared by Flush.
+			if err := w.Close(); err != nil {
+				t.Errorf("unexpected error on Close: %s", err.Error())
+			}
+			if err := w.Close(); err != errWriterClosed {
+				t.Errorf("expected errWriterClosed, got %v", err)
+			}
+			if err := w.Add(map[string]interface{}{"name": "d"}); err != errWriterClosed {
+				t.Errorf("expected errWriterClosed, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package bigquery
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	SDK "google.golang.org/api/bigquery/v2"
+)
+
+// ViewOption is optional settings of the view.
+type ViewOption struct {
+	// UseLegacySQL uses legacy SQL for the view query. (default: standard SQL)
+	UseLegacySQL bool
+
+	Description  string
+	FriendlyName string
+	Labels       map[string]string
+}
+
+func (o ViewOption) toTable(ref *SDK.TableReference, sql string) *SDK.Table {
+	return &SDK.Table{
+		TableReference: ref,
+		Description:    o.Description,
+		FriendlyName:   o.FriendlyName,
+		Labels:         o.Labels,
+		View: &SDK.ViewDefinition{
+			Query:        sql,
+			UseLegacySql: o.UseLegacySQL,
+			// API treats the missing value as legacy SQL.
+			ForceSendFields: []string{"UseLegacySql"},
+		},
+	}
+}
+
+// MaterializedViewOption is optional settings of the materialized view.
+type MaterializedViewOption struct {
+	TableOption
+
+	// DisableRefresh disables automatic refresh of the materialized view.
+	DisableRefresh bool
+	// RefreshInterval is the maximum frequency of automatic refresh. (default: 30m)
+	RefreshInterval time.Duration
+}
+
+func (o MaterializedViewOption) toTable(ref *SDK.TableReference, sql string) *SDK.Table {
+	tbl := &SDK.Table{
+		TableReference: ref,
+		MaterializedView: &SDK.MaterializedViewDefinition{
+			Query:             sql,
+			EnableRefresh:     !o.DisableRefresh,
+			RefreshIntervalMs: int64(o.RefreshInterval / time.Millisecond),
+			ForceSendFields:   []string{"EnableRefresh"},
+		},
+	}
+	o.TableOption.applyTo(tbl)
+	return tbl
+}
+
+// CreateView creates the logical view by the given SQL.
+func (ds *DatasetAPI) CreateView(viewID, sql string, opt ViewOption) error {
+	return ds.CreateViewWithContext(context.Background(), viewID, sql, opt)
+}
+
+// CreateViewWithContext creates the logical view by the given SQL with the given context.
+func (ds *DatasetAPI) CreateViewWithContext(ctx context.Context, viewID, sql string, opt ViewOption) error {
+	tbl := opt.toTable(ds.TableAPI(viewID).tableReference(), sql)
+	_, err := ds.client.CreateTableWithContext(ctx, ds.datasetID, tbl)
+	return err
+}
+
+// UpdateView updates the SQL and settings of the logical view.
+func (ds *DatasetAPI) UpdateView(viewID, sql string, opt ViewOption) error {
+	return ds.UpdateViewWithContext(context.Background(), viewID, sql, opt)
+}
+
+// UpdateViewWithContext updates the SQL and settings of the logical view with the given context.
+func (ds *DatasetAPI) UpdateViewWithContext(ctx context.Context, viewID, sql string, opt ViewOption) error {
+	tbl := opt.toTable(ds.TableAPI(viewID).tableReference(), sql)
+	_, err := ds.client.PatchTableWithContext(ctx, ds.datasetID, viewID, tbl)
+	return err
+}
+
+// CreateMaterializedView creates the materialized view by the given SQL.
+func (ds *DatasetAPI) CreateMaterializedView(viewID, sql string, opt MaterializedViewOption) error {
+	return ds.CreateMaterializedViewWithContext(context.Background(), viewID, sql, opt)
+}
+
+// CreateMaterializedViewWithContext creates the materialized view by the given SQL with the given context.
+func (ds *DatasetAPI) CreateMaterializedViewWithContext(ctx context.Context, viewID, sql string, opt MaterializedViewOption) error {
+	tbl := opt.toTable(ds.TableAPI(viewID).tableReference(), sql)
+	_, err := ds.client.CreateTableWithContext(ctx, ds.datasetID, tbl)
+	return err
+}
+
+// EnsureView creates the logical view when it does not exist,
+// or updates the view when the SQL or SQL dialect is different from the existing view.
+// It returns true when the view is created or updated.
+func (ds *DatasetAPI) EnsureView(viewID, sql string, opt ViewOption) (changed bool, err error) {
+	return ds.EnsureViewWithContext(context.Background(), viewID, sql, opt)
+}
+
+// EnsureViewWithContext ensures the logical view with the given context.
+func (ds *DatasetAPI) EnsureViewWithContext(ctx context.Context, viewID, sql string, opt ViewOption) (changed bool, err error) {
+	tbl, err := ds.client.GetTableWithContext(ctx, ds.datasetID, viewID)
+	if err != nil {
+		if !isNotFound(err) {
+			return false, err
+		}
+		// view does not exist.
+		if err := ds.CreateViewWithContext(ctx, viewID, sql, opt); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+
+	if isSameView(tbl.View, sql, opt.UseLegacySQL) {
+		return false, nil
+	}
+	if err := ds.UpdateViewWithContext(ctx, viewID, sql, opt); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// isSameView checks if the view definition has the same SQL and SQL dialect.
+func isSameView(view *SDK.ViewDefinition, sql string, useLegacySQL bool) bool {
+	if view == nil {
+		return false
+	}
+	return view.UseLegacySql == useLegacySQL &&
+		strings.TrimSpace(view.Query) == strings.TrimSpace(sql)
+}
@@ -0,0 +1,128 @@
+package bigquery
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	SDK "google.golang.org/api/bigquery/v2"
+)
+
+func TestDatasetAPICreateView(t *testing.T) {
+	srv := &tableTestServer{}
+	ds := newTestBigQuery(t, srv).DatasetAPI("ds")
+
+	err := ds.CreateView("v_users", "SELECT id FROM ds.users", ViewOption{
+		Description: "active users",
+		Labels:      map[string]string{"env": "test"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	want := &SDK.Table{
+		TableReference: &SDK.TableReference{ProjectId: "project", DatasetId: "ds", TableId: "v_users"},
+		Description:    "active users",
+		Labels:         map[string]string{"env": "test"},
+		View:           &SDK.ViewDefinition{Query: "SELECT id FROM ds.users"},
+	}
+	if !reflect.DeepEqual(srv.table, want) {
+		t.Errorf("got %#v, want %#v", srv.table, want)
+	}
+	// standard SQL must be sent explicitly.
+	if !strings.Contains(srv.body, `"useLegacySql":false`) {
+		t.Errorf("got %s, want useLegacySql=false in the body", srv.body)
+	}
+}
+
+func TestDatasetAPICreateMaterializedView(t *testing.T) {
+	srv := &tableTestServer{}
+	ds := newTestBigQuery(t, srv).DatasetAPI("ds")
+
+	err := ds.CreateMaterializedView("mv_sales", "SELECT day, SUM(amount) FROM ds.sales GROUP BY day", MaterializedViewOption{
+		TableOption: TableOption{
+			TimePartitioning: &TimePartitioning{Field: "day"},
+			Clustering:       []string{"day"},
+		},
+		RefreshInterval: time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	want := &SDK.Table{
+		TableReference: &SDK.TableReference{ProjectId: "project", DatasetId: "ds", TableId: "mv_sales"},
+		MaterializedView: &SDK.MaterializedViewDefinition{
+			Query:             "SELECT day, SUM(amount) FROM ds.sales GROUP BY day",
+			EnableRefresh:     true,
+			RefreshIntervalMs: 3600000,
+		},
+		TimePartitioning: &SDK.TimePartitioning{Type: "DAY", Field: "day"},
+		Clustering:       &SDK.Clustering{Fields: []string{"day"}},
+	}
+	if !reflect.DeepEqual(srv.table, want) {
+		t.Errorf("got %#v, want %#v", srv.table, want)
+	}
+
+	// disabled refresh must be sent explicitly.
+	srv = &tableTestServer{}
+	ds = newTestBigQuery(t, srv).DatasetAPI("ds")
+	if err := ds.CreateMaterializedView("mv_sales", "SELECT 1", MaterializedViewOption{DisableRefresh: true}); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if !strings.Contains(srv.body, `"enableRefresh":false`) {
+		t.Errorf("got %s, want enableRefresh=false in the body", srv.body)
+	}
+}
+
+func TestDatasetAPIEnsureView(t *testing.T) {
+	const sql = "SELECT id FROM ds.users"
+
+	tests := []struct {
+		name         string
+		existing     *SDK.Table
+		opt          ViewOption
+		wantChanged  bool
+		wantRequests []string
+	}{
+		{"create", nil, ViewOption{}, true, []string{
+			"GET /datasets/ds/tables/v_users",
+			"POST /datasets/ds/tables",
+		}},
+		{"same", &SDK.Table{View: &SDK.ViewDefinition{Query: sql + "\n"}}, ViewOption{}, false, []string{
+			"GET /datasets/ds/tables/v_users",
+		}},
+		{"different SQL", &SDK.Table{View: &SDK.ViewDefinition{Query: "SELECT 1"}}, ViewOption{}, true, []string{
+			"GET /datasets/ds/tables/v_users",
+			"PATCH /datasets/ds/tables/v_users",
+		}},
+		{"different dialect", &SDK.Table{View: &SDK.ViewDefinition{Query: sql, UseLegacySql: true}}, ViewOption{}, true, []string{
+			"GET /datasets/ds/tables/v_users",
+			"PATCH /datasets/ds/tables/v_users",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := &tableTestServer{existing: tt.existing}
+			ds := newTestBigQuery(t, srv).DatasetAPI("ds")
+
+			changed, err := ds.EnsureView("v_users", sql, tt.opt)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+			if changed != tt.wantChanged {
+				t.Errorf("changed: got %v, want %v", changed, tt.wantChanged)
+			}
+			if !reflect.DeepEqual(srv.requests, tt.wantRequests) {
+				t.Errorf("got %v, want %v", srv.requests, tt.wantRequests)
+			}
+			if tt.wantChanged {
+				if srv.table.View == nil || srv.table.View.Query != sql || srv.table.View.UseLegacySql {
+					t.Errorf("got %#v, want the view of %s in standard SQL", srv.table.View, sql)
+				}
+			}
+		})
+	}
+}
@@ -33,6 +33,7 @@ func New(conf config.Config) (*Calendar, error) {
 	if err != nil {
 		return nil, err
 	}
+	svc.BasePath = conf.BasePath(svc.BasePath)
 
 	Calendar := &Calendar{
 		service: svc,
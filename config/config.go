@@ -89,9 +89,40 @@ type Config struct {
 
 	UseIAMRole   bool
 	NoUseIAMRole bool // for multiple config and avoid to use environment value.
+
+	// Endpoint overrides the base URL of the API for local emulators and fake servers.
+	// (e.g. "http://localhost:9050/bigquery/v2/", "http://localhost:4443/storage/v1/")
+	Endpoint string
+	// GRPCEndpoint overrides the host and port of gRPC API for local emulators and fake servers.
+	// (e.g. "localhost:9060" for Storage Read API)
+	GRPCEndpoint string
+	// NoAuthentication uses the plain http client without any credentials.
+	// It is used with Endpoint for local emulators and fake servers.
+	NoAuthentication bool
+
+	// Middlewares wrap the authenticated transport of the http client.
+	// The first middleware is the outermost. (see UserAgentMiddleware, HeaderMiddleware and LoggingMiddleware)
+	Middlewares []Middleware
+	// Retry enables the automatic retry of the API requests. (default: no retry)
+	// The retry wraps Middlewares, so each attempt passes through them.
+	Retry *RetryPolicy
 }
 
+// Client returns the authenticated http client wrapped by Middlewares and Retry.
 func (c Config) Client() (*http.Client, error) {
+	cli, err := c.newClient()
+	if err != nil {
+		return nil, err
+	}
+	return c.applyMiddlewares(cli), nil
+}
+
+func (c Config) newClient() (*http.Client, error) {
+	if c.NoAuthentication {
+		return &http.Client{
+			Timeout: c.Timeout,
+		}, nil
+	}
 	if c.useIAMRole() {
 		return google.DefaultClient(c.NewContext())
 	}
@@ -108,6 +139,32 @@ func (c Config) Client() (*http.Client, error) {
 	return cli, nil
 }
 
+// BasePath returns Endpoint when it's set, or returns the given default base path of the API.
+func (c Config) BasePath(defaultPath string) string {
+	if c.Endpoint != "" {
+		return c.Endpoint
+	}
+	return defaultPath
+}
+
+// NewTokenSource returns oauth2.TokenSource by the same precedence as Client.
+// (IAM role and application default credentials, OAuth and JWT)
+// It is used for the API clients which do not use http.Client, such as gRPC clients.
+func (c Config) NewTokenSource(ctx context.Context) (oauth2.TokenSource, error) {
+	if c.useIAMRole() {
+		cred, err := google.FindDefaultCredentials(ctx, c.Scopes...)
+		if err != nil {
+			return nil, err
+		}
+		return cred.TokenSource, nil
+	}
+	if c.useOAuthClient() {
+		return c.newOAuthTokenSource(ctx)
+	}
+	return c.TokenSource(ctx)
+}
+
+// TokenSource returns oauth2.TokenSource of JWT config.
 func (c Config) TokenSource(ctx context.Context) (oauth2.TokenSource, error) {
 	conf, err := c.JWTConfig()
 	if err != nil {
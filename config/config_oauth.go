@@ -18,14 +18,20 @@ const (
 
 // NewOAuthClient creates http.Client from OAuth parameters.
 func (c Config) NewOAuthClient() (*http.Client, error) {
-	conf, err := c.oauthConfig()
+	ctx := c.NewContext()
+	ts, err := c.newOAuthTokenSource(ctx)
 	if err != nil {
 		return nil, err
 	}
+	return oauth2.NewClient(ctx, ts), nil
+}
 
-	ctx := context.WithValue(oauth2.NoContext, oauth2.HTTPClient, &http.Client{
-		Timeout: c.Timeout,
-	})
+// newOAuthTokenSource creates oauth2.TokenSource from OAuth parameters and the token file.
+func (c Config) newOAuthTokenSource(ctx context.Context) (oauth2.TokenSource, error) {
+	conf, err := c.oauthConfig()
+	if err != nil {
+		return nil, err
+	}
 
 	// check existence of oauth token file.
 	tokenFile := c.getOAuthTokenFile()
@@ -53,8 +59,7 @@ func (c Config) NewOAuthClient() (*http.Client, error) {
 	if err != nil {
 		return nil, err
 	}
-
-	return oauth2.NewClient(ctx, ts), nil
+	return ts, nil
 }
 
 // GetOAuthCodeURL returns URL to get oauth code.
@@ -0,0 +1,70 @@
+package config
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestConfigBasePath(t *testing.T) {
+	const defaultPath = "https://bigquery.googleapis.com/bigquery/v2/"
+
+	tests := []struct {
+		name     string
+		endpoint string
+		want     string
+	}{
+		{"default", "", defaultPath},
+		{"endpoint", "http://localhost:9050/bigquery/v2/", "http://localhost:9050/bigquery/v2/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := Config{Endpoint: tt.endpoint}
+			if got := conf.BasePath(defaultPath); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfigClientNoAuthentication(t *testing.T) {
+	var gotHeader http.Header
+	var gotBody string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeader = r.Header
+		b, _ := ioutil.ReadAll(r.Body)
+		gotBody = string(b)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	// the credentials are not used even if they are set.
+	cli, err := Config{
+		Email:            "a@example.com",
+		PrivateKey:       "invalid",
+		NoAuthentication: true,
+		Middlewares:      []Middleware{UserAgentMiddleware("test-agent")},
+	}.Client()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	resp, err := cli.Post(ts.URL, "application/json", strings.NewReader(`{"id":"ds"}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	resp.Body.Close()
+
+	if auth := gotHeader.Get("Authorization"); auth != "" {
+		t.Errorf("got Authorization=[%s], want no Authorization", auth)
+	}
+	if ua := gotHeader.Get("User-Agent"); !strings.Contains(ua, "test-agent") {
+		t.Errorf("got User-Agent=[%s], want test-agent", ua)
+	}
+	if gotBody != `{"id":"ds"}` {
+		t.Errorf("got %s, want the request body", gotBody)
+	}
+}
@@ -0,0 +1,182 @@
+package config
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultRetryMaxAttempts = 3
+	defaultRetryBaseDelay   = 500 * time.Millisecond
+	defaultRetryMaxDelay    = 30 * time.Second
+)
+
+var defaultRetryableStatusCodes = []int{
+	http.StatusTooManyRequests,
+	http.StatusInternalServerError,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+type idempotentKey struct{}
+
+// WithIdempotent returns the context which marks the requests as idempotent.
+// The requests with the context are retried even if the method is POST or PATCH.
+func WithIdempotent(ctx context.Context) context.Context {
+	return context.WithValue(ctx, idempotentKey{}, true)
+}
+
+func isIdempotentContext(ctx context.Context) bool {
+	v, _ := ctx.Value(idempotentKey{}).(bool)
+	return v
+}
+
+// RetryPolicy is settings of the automatic retry with exponential backoff for the API requests.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts including the first request. (default: 3)
+	MaxAttempts int
+	// BaseDelay is the delay before the first retry, and it doubles on each retry. (default: 500ms)
+	BaseDelay time.Duration
+	// MaxDelay is the maximum delay between the attempts. (default: 30s)
+	MaxDelay time.Duration
+	// Jitter is the ratio of the random reduction of the delay. (0.0 - 1.0, default: no jitter)
+	Jitter float64
+	// RetryableStatusCodes are the status codes to retry. (default: 429, 500, 502, 503, 504)
+	RetryableStatusCodes []int
+
+	// RetryNonIdempotent retries all of the requests.
+	// By default, only GET, HEAD, PUT, DELETE and OPTIONS requests, and requests with the context
+	// from WithIdempotent are retried, because retrying other requests can apply the operation twice.
+	RetryNonIdempotent bool
+}
+
+func (p RetryPolicy) getMaxAttempts() int {
+	if p.MaxAttempts > 0 {
+		return p.MaxAttempts
+	}
+	return defaultRetryMaxAttempts
+}
+
+func (p RetryPolicy) getBaseDelay() time.Duration {
+	if p.BaseDelay > 0 {
+		return p.BaseDelay
+	}
+	return defaultRetryBaseDelay
+}
+
+func (p RetryPolicy) getMaxDelay() time.Duration {
+	if p.MaxDelay > 0 {
+		return p.MaxDelay
+	}
+	return defaultRetryMaxDelay
+}
+
+func (p RetryPolicy) getRetryableStatusCodes() []int {
+	if len(p.RetryableStatusCodes) != 0 {
+		return p.RetryableStatusCodes
+	}
+	return defaultRetryableStatusCodes
+}
+
+func (p RetryPolicy) isIdempotent(req *http.Request) bool {
+	if p.RetryNonIdempotent || isIdempotentContext(req.Context()) {
+		return true
+	}
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+func (p RetryPolicy) shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	if err != nil {
+		// network error. the request is not retried after cancel or timeout of the context.
+		return req.Context().Err() == nil
+	}
+	for _, code := range p.getRetryableStatusCodes() {
+		if resp.StatusCode == code {
+			return true
+		}
+	}
+	return false
+}
+
+// delay returns the delay before the next attempt. The response of Retry-After header is used when it's longer.
+func (p RetryPolicy) delay(retry int, resp *http.Response) time.Duration {
+	maxDelay := p.getMaxDelay()
+	d := p.getBaseDelay()
+	for i := 0; i < retry && d < maxDelay; i++ {
+		d *= 2
+	}
+	if d > maxDelay {
+		d = maxDelay
+	}
+	if p.Jitter > 0 {
+		d -= time.Duration(rand.Float64() * p.Jitter * float64(d))
+	}
+
+	if resp != nil {
+		if sec, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+			if after := time.Duration(sec) * time.Second; after > d {
+				d = after
+			}
+		}
+	}
+	if d > maxDelay {
+		d = maxDelay
+	}
+	return d
+}
+
+// RetryMiddleware retries the requests on the retryable status codes and network errors.
+// The request body must be re-readable by Request.GetBody to retry, and media uploads are not retried.
+func RetryMiddleware(policy RetryPolicy) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			hasBody := req.Body != nil && req.Body != http.NoBody
+			if !policy.isIdempotent(req) || (hasBody && req.GetBody == nil) {
+				return next.RoundTrip(req)
+			}
+
+			maxAttempts := policy.getMaxAttempts()
+			for retry := 0; ; retry++ {
+				r := req
+				if retry > 0 && hasBody {
+					body, err := req.GetBody()
+					if err != nil {
+						return nil, err
+					}
+					r = req.Clone(req.Context())
+					r.Body = body
+				}
+
+				resp, err := next.RoundTrip(r)
+				if retry+1 >= maxAttempts || !policy.shouldRetry(req, resp, err) {
+					return resp, err
+				}
+
+				wait := policy.delay(retry, resp)
+				if resp != nil {
+					// reuse the connection.
+					_, _ = io.Copy(ioutil.Discard, resp.Body)
+					resp.Body.Close()
+				}
+
+				timer := time.NewTimer(wait)
+				select {
+				case <-req.Context().Done():
+					timer.Stop()
+					return nil, req.Context().Err()
+				case <-timer.C:
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,188 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicyDelay(t *testing.T) {
+	tests := []struct {
+		name       string
+		policy     RetryPolicy
+		retry      int
+		retryAfter string
+		want       time.Duration
+	}{
+		{"default base", RetryPolicy{}, 0, "", 500 * time.Millisecond},
+		{"exponential", RetryPolicy{BaseDelay: time.Second}, 3, "", 8 * time.Second},
+		{"max delay", RetryPolicy{BaseDelay: time.Second, MaxDelay: 5 * time.Second}, 10, "", 5 * time.Second},
+		{"overflow", RetryPolicy{BaseDelay: time.Second}, 100, "", 30 * time.Second},
+		{"longer Retry-After", RetryPolicy{BaseDelay: time.Second}, 0, "3", 3 * time.Second},
+		{"shorter Retry-After", RetryPolicy{BaseDelay: time.Second}, 2, "1", 4 * time.Second},
+		{"Retry-After over max delay", RetryPolicy{BaseDelay: time.Second, MaxDelay: 2 * time.Second}, 0, "60", 2 * time.Second},
+		{"invalid Retry-After", RetryPolicy{BaseDelay: time.Second}, 0, "Wed, 21 Oct 2015 07:28:00 GMT", time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resp *http.Response
+			if tt.retryAfter != "" {
+				resp = &http.Response{Header: http.Header{"Retry-After": {tt.retryAfter}}}
+			}
+			if got := tt.policy.delay(tt.retry, resp); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyDelayJitter(t *testing.T) {
+	p := RetryPolicy{BaseDelay: time.Second, Jitter: 0.5}
+	for i := 0; i < 100; i++ {
+		got := p.delay(1, nil)
+		if got < time.Second || got > 2*time.Second {
+			t.Fatalf("got %s, want between 1s and 2s", got)
+		}
+	}
+}
+
+// retryTestTransport returns the scripted status codes or errors, and records the request bodies.
+type retryTestTransport struct {
+	statuses []int // 0 means network error.
+	bodies   []string
+}
+
+func (rt *retryTestTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body := ""
+	if req.Body != nil {
+		b, _ := ioutil.ReadAll(req.Body)
+		body = string(b)
+	}
+	rt.bodies = append(rt.bodies, body)
+
+	status := http.StatusOK
+	if len(rt.statuses) != 0 {
+		status = rt.statuses[0]
+		rt.statuses = rt.statuses[1:]
+	}
+	if status == 0 {
+		return nil, errors.New("connection reset")
+	}
+	return &http.Response{
+		StatusCode: status,
+		Header:     http.Header{},
+		Body:       ioutil.NopCloser(strings.NewReader("")),
+	}, nil
+}
+
+func TestRetryMiddleware(t *testing.T) {
+	tests := []struct {
+		name       string
+		policy     RetryPolicy
+		method     string
+		body       string
+		idempotent bool
+		statuses   []int
+		wantStatus int
+		wantErr    bool
+		wantCalls  int
+	}{
+		{"success", RetryPolicy{}, http.MethodGet, "", false, nil, http.StatusOK, false, 1},
+		{"retry status", RetryPolicy{}, http.MethodGet, "", false, []int{503, 500}, http.StatusOK, false, 3},
+		{"retry network error", RetryPolicy{}, http.MethodGet, "", false, []int{0}, http.StatusOK, false, 2},
+		{"max attempts", RetryPolicy{MaxAttempts: 2}, http.MethodGet, "", false, []int{503, 503, 503}, 503, false, 2},
+		{"last network error", RetryPolicy{MaxAttempts: 2}, http.MethodGet, "", false, []int{0, 0}, 0, true, 2},
+		{"not retryable status", RetryPolicy{}, http.MethodGet, "", false, []int{400}, 400, false, 1},
+		{"custom status", RetryPolicy{RetryableStatusCodes: []int{409}}, http.MethodGet, "", false, []int{503}, 503, false, 1},
+		{"POST is not retried", RetryPolicy{}, http.MethodPost, "{}", false, []int{503}, 503, false, 1},
+		{"idempotent POST", RetryPolicy{}, http.MethodPost, "{}", true, []int{503}, http.StatusOK, false, 2},
+		{"retry non idempotent", RetryPolicy{RetryNonIdempotent: true}, http.MethodPatch, "{}", false, []int{503}, http.StatusOK, false, 2},
+		{"PUT with body", RetryPolicy{}, http.MethodPut, "{\"a\":1}", false, []int{503, 503}, http.StatusOK, false, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.policy.BaseDelay = time.Millisecond
+			rt := &retryTestTransport{statuses: tt.statuses}
+			cli := &http.Client{Transport: RetryMiddleware(tt.policy)(rt)}
+
+			ctx := context.Background()
+			if tt.idempotent {
+				ctx = WithIdempotent(ctx)
+			}
+			req, err := http.NewRequest(tt.method, "http://example.com/", strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+			if tt.body == "" {
+				req.Body = http.NoBody
+			}
+			resp, err := cli.Do(req.WithContext(ctx))
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got %d", resp.StatusCode)
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err.Error())
+				}
+				resp.Body.Close()
+				if resp.StatusCode != tt.wantStatus {
+					t.Errorf("status: got %d, want %d", resp.StatusCode, tt.wantStatus)
+				}
+			}
+
+			if len(rt.bodies) != tt.wantCalls {
+				t.Fatalf("calls: got %d, want %d", len(rt.bodies), tt.wantCalls)
+			}
+			for i, body := range rt.bodies {
+				if body != tt.body {
+					t.Errorf("body[%d]: got %q, want %q", i, body, tt.body)
+				}
+			}
+		})
+	}
+}
+
+func TestRetryMiddlewareWithoutGetBody(t *testing.T) {
+	rt := &retryTestTransport{statuses: []int{503}}
+	req, err := http.NewRequest(http.MethodPut, "http://example.com/", ioutil.NopCloser(strings.NewReader("{}")))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	resp, err := RetryMiddleware(RetryPolicy{BaseDelay: time.Millisecond})(rt).RoundTrip(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if resp.StatusCode != 503 || len(rt.bodies) != 1 {
+		t.Errorf("got status=%d calls=%d, want no retry", resp.StatusCode, len(rt.bodies))
+	}
+}
+
+func TestRetryMiddlewareContextCancel(t *testing.T) {
+	rt := &retryTestTransport{statuses: []int{503, 503, 503}}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req, err := http.NewRequest(http.MethodGet, "http://example.com/", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	start := time.Now()
+	_, err = RetryMiddleware(RetryPolicy{BaseDelay: time.Minute, MaxDelay: time.Minute})(rt).RoundTrip(req.WithContext(ctx))
+	if err != context.DeadlineExceeded {
+		t.Errorf("got %v, want %v", err, context.DeadlineExceeded)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("waited %s after the context is done", elapsed)
+	}
+	if len(rt.bodies) != 1 {
+		t.Errorf("calls: got %d, want 1", len(rt.bodies))
+	}
+}
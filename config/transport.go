@@ -0,0 +1,92 @@
+package config
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/evalphobia/google-api-go-wrapper/log"
+)
+
+const transportServiceName = "http"
+
+// Middleware wraps http.RoundTripper to add the behavior to the requests of the API client.
+type Middleware func(http.RoundTripper) http.RoundTripper
+
+// RoundTripperFunc is an adapter to use the function as http.RoundTripper.
+type RoundTripperFunc func(*http.Request) (*http.Response, error)
+
+// RoundTrip calls f(req).
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// applyMiddlewares wraps the transport of the client by Middlewares and Retry.
+// The retry is the outermost, and the authenticated transport is the innermost.
+func (c Config) applyMiddlewares(cli *http.Client) *http.Client {
+	middlewares := c.Middlewares
+	if c.Retry != nil {
+		middlewares = append([]Middleware{RetryMiddleware(*c.Retry)}, middlewares...)
+	}
+	if len(middlewares) == 0 {
+		return cli
+	}
+
+	rt := cli.Transport
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		rt = middlewares[i](rt)
+	}
+	cli.Transport = rt
+	return cli
+}
+
+// UserAgentMiddleware appends the given value to User-Agent header of the requests.
+func UserAgentMiddleware(userAgent string) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			req = req.Clone(req.Context())
+			if ua := req.Header.Get("User-Agent"); ua != "" {
+				req.Header.Set("User-Agent", ua+" "+userAgent)
+			} else {
+				req.Header.Set("User-Agent", userAgent)
+			}
+			return next.RoundTrip(req)
+		})
+	}
+}
+
+// HeaderMiddleware sets the given headers to the requests.
+func HeaderMiddleware(header http.Header) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			req = req.Clone(req.Context())
+			for k, values := range header {
+				req.Header.Del(k)
+				for _, v := range values {
+					req.Header.Add(k, v)
+				}
+			}
+			return next.RoundTrip(req)
+		})
+	}
+}
+
+// LoggingMiddleware logs the method, url, status and elapsed time of the requests.
+// The headers and bodies are not logged, because they can contain the credentials and personal data.
+func LoggingMiddleware(logger log.Logger) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			start := time.Now()
+			resp, err := next.RoundTrip(req)
+			elapsed := time.Since(start)
+			if err != nil {
+				logger.Errorf(transportServiceName, "error on request; method=[%s] url=[%s] elapsed=[%s] error=[%s]", req.Method, req.URL.String(), elapsed, err.Error())
+				return resp, err
+			}
+			logger.Infof(transportServiceName, "method=[%s] url=[%s] status=[%d] elapsed=[%s]", req.Method, req.URL.String(), resp.StatusCode, elapsed)
+			return resp, nil
+		})
+	}
+}
@@ -0,0 +1,204 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// headerTestTransport records the headers of the requests.
+type headerTestTransport struct {
+	headers []http.Header
+	err     error
+}
+
+func (rt *headerTestTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rt.headers = append(rt.headers, req.Header)
+	if rt.err != nil {
+		return nil, rt.err
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{},
+		Body:       ioutil.NopCloser(strings.NewReader("")),
+	}, nil
+}
+
+func TestUserAgentMiddleware(t *testing.T) {
+	tests := []struct {
+		name    string
+		current string
+		want    string
+	}{
+		{"empty", "", "my-app/1.0"},
+		{"append", "google-api-go-client/0.5", "google-api-go-client/0.5 my-app/1.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rt := &headerTestTransport{}
+			req, _ := http.NewRequest(http.MethodGet, "http://example.com/", nil)
+			if tt.current != "" {
+				req.Header.Set("User-Agent", tt.current)
+			}
+
+			if _, err := UserAgentMiddleware("my-app/1.0")(rt).RoundTrip(req); err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+			if got := rt.headers[0].Get("User-Agent"); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+			if got := req.Header.Get("User-Agent"); got != tt.current {
+				t.Errorf("original request is modified: got %q, want %q", got, tt.current)
+			}
+		})
+	}
+}
+
+func TestHeaderMiddleware(t *testing.T) {
+	rt := &headerTestTransport{}
+	req, _ := http.NewRequest(http.MethodGet, "http://example.com/", nil)
+	req.Header.Set("X-Foo", "old")
+	req.Header.Set("X-Keep", "keep")
+
+	mw := HeaderMiddleware(http.Header{
+		"X-Foo": {"a", "b"},
+		"X-Bar": {"c"},
+	})
+	if _, err := mw(rt).RoundTrip(req); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	want := http.Header{
+		"X-Foo":  {"a", "b"},
+		"X-Bar":  {"c"},
+		"X-Keep": {"keep"},
+	}
+	if !reflect.DeepEqual(rt.headers[0], want) {
+		t.Errorf("got %v, want %v", rt.headers[0], want)
+	}
+	if got := req.Header["X-Foo"]; !reflect.DeepEqual(got, []string{"old"}) {
+		t.Errorf("original request is modified: got %v", got)
+	}
+}
+
+// testLogger records the logs.
+type testLogger struct {
+	infos  []string
+	errors []string
+}
+
+func (l *testLogger) Infof(service, format string, v ...interface{}) {
+	l.infos = append(l.infos, service+": "+fmt.Sprintf(format, v...))
+}
+
+func (l *testLogger) Errorf(service, format string, v ...interface{}) {
+	l.errors = append(l.errors, service+": "+fmt.Sprintf(format, v...))
+}
+
+func TestLoggingMiddleware(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantInfos  int
+		wantErrors int
+		wantText   string
+	}{
+		{"success", nil, 1, 0, "http: method=[GET] url=[http://example.com/path?q=1] status=[200]"},
+		{"error", errors.New("connection reset"), 0, 1, "http: error on request; method=[GET] url=[http://example.com/path?q=1]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rt := &headerTestTransport{err: tt.err}
+			logger := &testLogger{}
+			req, _ := http.NewRequest(http.MethodGet, "http://example.com/path?q=1", nil)
+			req.Header.Set("Authorization", "Bearer secret")
+
+			_, err := LoggingMiddleware(logger)(rt).RoundTrip(req)
+			if err != tt.err {
+				t.Errorf("got %v, want %v", err, tt.err)
+			}
+			if len(logger.infos) != tt.wantInfos || len(logger.errors) != tt.wantErrors {
+				t.Fatalf("got infos=%v errors=%v", logger.infos, logger.errors)
+			}
+			logs := strings.Join(append(logger.infos, logger.errors...), "\n")
+			if !strings.HasPrefix(logs, tt.wantText) {
+				t.Errorf("got %q, want prefix %q", logs, tt.wantText)
+			}
+			if strings.Contains(logs, "secret") {
+				t.Errorf("credential is logged: %q", logs)
+			}
+		})
+	}
+}
+
+func TestConfigApplyMiddlewares(t *testing.T) {
+	var order []string
+	record := func(name string) Middleware {
+		return func(next http.RoundTripper) http.RoundTripper {
+			return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				order = append(order, name)
+				return next.RoundTrip(req)
+			})
+		}
+	}
+
+	// the base transport fails once to check that Retry wraps all of the middlewares.
+	calls := 0
+	base := RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		calls++
+		order = append(order, "base")
+		status := http.StatusOK
+		if calls == 1 {
+			status = http.StatusServiceUnavailable
+		}
+		return &http.Response{
+			StatusCode: status,
+			Header:     http.Header{},
+			Body:       ioutil.NopCloser(strings.NewReader("")),
+		}, nil
+	})
+
+	tests := []struct {
+		name      string
+		conf      Config
+		wantOrder []string
+	}{
+		{"no middleware", Config{}, []string{"base"}},
+		{"middlewares", Config{
+			Middlewares: []Middleware{record("first"), record("second")},
+		}, []string{"first", "second", "base"}},
+		{"retry is outermost", Config{
+			Middlewares: []Middleware{record("first"), record("second")},
+			Retry:       &RetryPolicy{BaseDelay: time.Millisecond},
+		}, []string{"first", "second", "base", "first", "second", "base"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order = nil
+			calls = 1 // no failure without retry.
+			if tt.conf.Retry != nil {
+				calls = 0
+			}
+
+			cli := tt.conf.applyMiddlewares(&http.Client{Transport: base})
+			req, _ := http.NewRequest(http.MethodGet, "http://example.com/", nil)
+			resp, err := cli.Do(req)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+			resp.Body.Close()
+
+			if !reflect.DeepEqual(order, tt.wantOrder) {
+				t.Errorf("got %v, want %v", order, tt.wantOrder)
+			}
+		})
+	}
+}
@@ -45,6 +45,7 @@ func NewLogger(conf config.Config, projectID string) (*Logger, error) {
 	if err != nil {
 		return nil, err
 	}
+	svc.BasePath = conf.BasePath(svc.BasePath)
 
 	logger := &Logger{
 		service:    svc,
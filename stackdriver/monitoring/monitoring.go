@@ -43,6 +43,7 @@ func NewMonitor(conf config.Config, projectID string) (*Monitor, error) {
 	if err != nil {
 		return nil, err
 	}
+	svc.BasePath = conf.BasePath(svc.BasePath)
 
 	monitor := &Monitor{
 		service:    svc,
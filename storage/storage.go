@@ -36,7 +36,11 @@ func New(ctx context.Context, conf config.Config) (*Storage, error) {
 		return nil, err
 	}
 
-	svc, err := GCP.NewClient(ctx, option.WithHTTPClient(httpClient))
+	opts := []option.ClientOption{option.WithHTTPClient(httpClient)}
+	if conf.Endpoint != "" {
+		opts = append(opts, option.WithEndpoint(conf.Endpoint))
+	}
+	svc, err := GCP.NewClient(ctx, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -1,6 +1,7 @@
 package vision
 
 import (
+	"context"
 	"encoding/base64"
 	"errors"
 
@@ -34,6 +35,7 @@ func New(conf config.Config) (*Vision, error) {
 	if err != nil {
 		return nil, err
 	}
+	svc.BasePath = conf.BasePath(svc.BasePath)
 
 	vision := &Vision{
 		service: svc,
@@ -160,7 +162,9 @@ func (v *Vision) Get(r *Resource) (*Response, error) {
 
 // get executes Images.Annotate operation.
 func (v *Vision) get(req *SDK.BatchAnnotateImagesRequest) (*Response, error) {
-	resp, err := v.service.Images.Annotate(req).Do()
+	// Annotate uses POST method, but it does not change anything and can be retried.
+	ctx := config.WithIdempotent(context.Background())
+	resp, err := v.service.Images.Annotate(req).Context(ctx).Do()
 	if err != nil {
 		v.Errorf("error on `Annotate` operation;  error=%s", err.Error())
 	}